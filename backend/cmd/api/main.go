@@ -2,24 +2,29 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"strconv"
 	"time"
+	_ "time/tzdata" // ユーザーが指定したタイムゾーンを Lambda 環境でも解決できるようにする
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	echoadapter "github.com/awslabs/aws-lambda-go-api-proxy/echo"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"golang.org/x/time/rate"
 
 	"github.com/shuheikomatsuki/readoku/backend/internal/handler"
 	authMiddleware "github.com/shuheikomatsuki/readoku/backend/internal/middleware"
 	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
 	"github.com/shuheikomatsuki/readoku/backend/internal/service"
 	"github.com/shuheikomatsuki/readoku/backend/internal/ssmutil"
+	"github.com/shuheikomatsuki/readoku/backend/internal/webpage"
 )
 
 func main() {
@@ -28,17 +33,32 @@ func main() {
 		log.Fatalf("failed to load secrets: %v", err)
 	}
 
-	e := buildServer()
+	e, jobs := buildServer()
 
 	// Lambda 環境では API Gateway (HTTP API) と接続するハンドラで起動
+	// EventBridge のスケジュールイベントで呼ばれた場合は定期ジョブを実行する
 	if isLambda() {
 		adapter := echoadapter.NewV2(e)
-		lambda.Start(func(ctx context.Context, req events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+		lambda.Start(func(ctx context.Context, payload json.RawMessage) (any, error) {
+			if isScheduledEvent(payload) {
+				// ctx には Lambda の実行期限が設定されている
+				jobs.run(ctx, time.Now())
+				return nil, nil
+			}
+
+			var req events.APIGatewayV2HTTPRequest
+			if err := json.Unmarshal(payload, &req); err != nil {
+				return nil, fmt.Errorf("failed to decode API Gateway request: %w", err)
+			}
 			return adapter.ProxyWithContext(ctx, req)
 		})
 		return
 	}
 
+	if interval := schedulerInterval(); interval > 0 {
+		go jobs.loop(context.Background(), interval)
+	}
+
 	// ローカル / 常時稼働サーバーモード
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -47,7 +67,7 @@ func main() {
 	e.Logger.Fatal(e.Start(":" + port))
 }
 
-func buildServer() *echo.Echo {
+func buildServer() (*echo.Echo, *scheduledJobs) {
 	e := echo.New()
 
 	frontendURL := os.Getenv("FRONTEND_URL")
@@ -62,7 +82,7 @@ func buildServer() *echo.Echo {
 			"http://localhost:5173",
 			"http://127.0.0.1:5173",
 		},
-		AllowMethods: []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"},
+		AllowMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders: []string{
 			echo.HeaderOrigin,
 			echo.HeaderContentType,
@@ -72,6 +92,7 @@ func buildServer() *echo.Echo {
 		AllowCredentials: true,
 	}))
 
+	e.IPExtractor = clientIPExtractor()
 	e.Validator = handler.NewValidator()
 
 	// DB接続
@@ -93,7 +114,16 @@ func buildServer() *echo.Echo {
 	// Repository層
 	userRepo := repository.NewUserRepository(db)
 	storyRepo := repository.NewStoryRepository(db)
+	seriesRepo := repository.NewSeriesRepository(db)
+	translationRepo := repository.NewTranslationRepository(db)
 	readingRecordRepo := repository.NewReadingRecordRepository(db)
+	quizRepo := repository.NewQuizRepository(db)
+	templateRepo := repository.NewTemplateRepository(db)
+	dailyStoryRepo := repository.NewDailyStoryRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	shelfRepo := repository.NewShelfRepository(db)
+	shareRepo := repository.NewShareRepository(db)
+	communityRepo := repository.NewCommunityRepository(db)
 
 	// Service層
 	llmService, err := service.NewLLMService(os.Getenv("GEMINI_API_KEY"))
@@ -102,11 +132,29 @@ func buildServer() *echo.Echo {
 	}
 	authService := service.NewAuthService(userRepo)
 	userService := service.NewUserService(readingRecordRepo, userRepo, dailyLimit)
-	storyService := service.NewStoryService(storyRepo, readingRecordRepo, userRepo, llmService, dailyLimit)
+	storyService := service.NewStoryService(storyRepo, seriesRepo, translationRepo, readingRecordRepo, userRepo, tagRepo, llmService, dailyLimit)
+	quizService := service.NewQuizService(storyRepo, quizRepo, userRepo, llmService, dailyLimit)
+	templateService := service.NewTemplateService(templateRepo, userRepo)
+	dailyStoryService := service.NewDailyStoryService(dailyStoryRepo, storyService)
+	tagService := service.NewTagService(tagRepo, storyRepo)
+	shelfService := service.NewShelfService(shelfRepo, storyRepo)
+	exportService := service.NewExportService(storyRepo, tagRepo, shelfRepo, maxArchiveBytes())
+	importService := service.NewImportService(storyRepo, seriesRepo, webpage.NewFetcher())
+	shareService := service.NewShareService(shareRepo, storyRepo)
+	communityService := service.NewCommunityService(communityRepo, storyRepo)
 
 	// Handler層
 	authHandler := handler.NewAuthHandler(authService, userService)
-	storyHandler := handler.NewStoryHandler(storyService)
+	storyHandler := handler.NewStoryHandler(storyService, templateService)
+	quizHandler := handler.NewQuizHandler(quizService)
+	templateHandler := handler.NewTemplateHandler(templateService)
+	dailyStoryHandler := handler.NewDailyStoryHandler(dailyStoryService)
+	tagHandler := handler.NewTagHandler(tagService)
+	shelfHandler := handler.NewShelfHandler(shelfService)
+	importHandler := handler.NewImportHandler(importService)
+	exportHandler := handler.NewExportHandler(exportService)
+	shareHandler := handler.NewShareHandler(shareService)
+	communityHandler := handler.NewCommunityHandler(communityService)
 
 	e.GET("/health", func(c echo.Context) error {
 		return c.String(http.StatusOK, "ok")
@@ -141,29 +189,151 @@ func buildServer() *echo.Echo {
 	api.POST("/signup", authHandler.SignUp)
 	api.POST("/login", authHandler.Login)
 
+	// 共有リンクはログインなしで読めるため、トークンの総当たりを防ぐ専用の制限をかける
+	shared := api.Group("/shared")
+	shared.Use(middleware.RateLimiterWithConfig(sharedRateLimiterConfig()))
+	shared.GET("/:token", shareHandler.GetSharedStory)
+
 	userRoutes := api.Group("/users")
 	userRoutes.Use(authMiddleware.JWTAuthMiddleware)
 	userRoutes.GET("/me/stats", authHandler.GetUserStats)
 	userRoutes.GET("/me/generation-status", authHandler.GetGenerationStatus)
+	userRoutes.GET("/me/history", authHandler.GetReadingHistory)
+	userRoutes.GET("/me/daily-story", dailyStoryHandler.GetPreference)
+	userRoutes.PUT("/me/daily-story", dailyStoryHandler.UpdatePreference)
 
 	// 認証が必要なグループ
 	stories := api.Group("/stories")
 	stories.Use(authMiddleware.JWTAuthMiddleware)
 	stories.POST("", storyHandler.GenerateStory)
 	stories.GET("", storyHandler.GetStories)
+	stories.GET("/trash", storyHandler.GetTrash)
+	stories.GET("/duplicates", storyHandler.GetDuplicateClusters)
+	stories.POST("/bulk", storyHandler.BulkUpdateStories)
+	stories.POST("/import", importHandler.ImportStories)
+	stories.POST("/import/epub", importHandler.ImportEPUB)
+	stories.POST("/import/archive", importHandler.ImportArchive)
+	stories.POST("/import-url", importHandler.ImportURL)
+	stories.GET("/export.epub", exportHandler.ExportEPUB)
+	stories.GET("/export.zip", exportHandler.ExportArchive)
 	stories.GET("/:id", storyHandler.GetStory)
 	stories.DELETE("/:id", storyHandler.DeleteStory)
+	stories.POST("/:id/restore", storyHandler.RestoreStory)
 	stories.PATCH("/:id", storyHandler.UpdateStory)
+	stories.GET("/:id/revisions", storyHandler.GetRevisions)
+	stories.GET("/:id/revisions/:revision", storyHandler.GetRevisionDiff)
+	stories.POST("/:id/revisions/:revision/revert", storyHandler.RevertStory)
 	stories.POST("/:id/read", storyHandler.MarkStoryAsRead)
 	stories.DELETE("/:id/read/latest", storyHandler.UndoLastRead)
+	stories.PUT("/:id/favorite", storyHandler.FavoriteStory)
+	stories.DELETE("/:id/favorite", storyHandler.UnfavoriteStory)
+	stories.POST("/:id/continue", storyHandler.ContinueStory)
+	stories.POST("/:id/rewrite", storyHandler.RewriteStory)
+	stories.POST("/:id/translation", storyHandler.TranslateStory)
+	stories.POST("/:id/quiz", quizHandler.GenerateQuiz)
+	stories.GET("/:id/quiz", quizHandler.GetQuiz)
+	stories.POST("/:id/quiz/attempts", quizHandler.SubmitAnswers)
+	stories.PUT("/:id/tags/:tag_id", tagHandler.AttachTag)
+	stories.DELETE("/:id/tags/:tag_id", tagHandler.DetachTag)
+	stories.GET("/:id/shares", shareHandler.GetShares)
+	stories.POST("/:id/shares", shareHandler.CreateShare)
+	stories.DELETE("/:id/shares/:share_id", shareHandler.RevokeShare)
+	stories.POST("/:id/publish", communityHandler.PublishStory)
+
+	// 公開ライブラリ。公開したユーザーの情報は返さない
+	community := api.Group("/community/stories")
+	community.Use(authMiddleware.JWTAuthMiddleware)
+	community.GET("", communityHandler.GetCommunityStories)
+	community.GET("/:id", communityHandler.GetCommunityStory)
+	community.DELETE("/:id", communityHandler.UnpublishStory)
+	community.PUT("/:id/like", communityHandler.LikeStory)
+	community.DELETE("/:id/like", communityHandler.UnlikeStory)
+	community.POST("/:id/clone", communityHandler.CloneStory)
+
+	series := api.Group("/series")
+	series.Use(authMiddleware.JWTAuthMiddleware)
+	series.GET("/:id", storyHandler.GetSeries)
 
-	return e
+	templates := api.Group("/templates")
+	templates.Use(authMiddleware.JWTAuthMiddleware)
+	templates.GET("", templateHandler.GetTemplates)
+	templates.POST("", templateHandler.CreateTemplate)
+	templates.PATCH("/:id", templateHandler.UpdateTemplate)
+	templates.DELETE("/:id", templateHandler.DeleteTemplate)
+
+	tags := api.Group("/tags")
+	tags.Use(authMiddleware.JWTAuthMiddleware)
+	tags.GET("", tagHandler.GetTags)
+	tags.POST("", tagHandler.CreateTag)
+	tags.PATCH("/:id", tagHandler.RenameTag)
+	tags.DELETE("/:id", tagHandler.DeleteTag)
+
+	shelves := api.Group("/shelves")
+	shelves.Use(authMiddleware.JWTAuthMiddleware)
+	shelves.GET("", shelfHandler.GetShelves)
+	shelves.POST("", shelfHandler.CreateShelf)
+	shelves.GET("/:id", shelfHandler.GetShelf)
+	shelves.PATCH("/:id", shelfHandler.RenameShelf)
+	shelves.DELETE("/:id", shelfHandler.DeleteShelf)
+	shelves.POST("/:id/stories", shelfHandler.AddStory)
+	shelves.PUT("/:id/stories", shelfHandler.ReorderStories)
+	shelves.DELETE("/:id/stories/:story_id", shelfHandler.RemoveStory)
+
+	return e, &scheduledJobs{
+		dailyStoryService: dailyStoryService,
+		storyService:      storyService,
+		trashRetention:    trashRetention(),
+	}
 }
 
 func isLambda() bool {
 	return os.Getenv("AWS_LAMBDA_FUNCTION_NAME") != ""
 }
 
+// maxArchiveBytes はライブラリのアーカイブの大きさの上限を返す。
+// Lambda のアダプターは応答全体をメモリに溜めてから返すため、Lambda の応答の上限に収まる大きさに制限する
+func maxArchiveBytes() int64 {
+	if isLambda() {
+		return service.LambdaMaxArchiveBytes
+	}
+	return 0
+}
+
+// clientIPExtractor は c.RealIP() が返すクライアントの IP アドレスの決め方。
+// X-Forwarded-For などのヘッダーはクライアントが自由に付けられるため信用せず、接続元のアドレスを使う。
+// Lambda では API Gateway が確認した送信元 (requestContext.http.sourceIp) がポートなしで RemoteAddr に入るため、
+// "IP:port" として解釈できない場合は RemoteAddr をそのまま使う (echo.ExtractIPDirect は空文字を返してしまう)
+func clientIPExtractor() echo.IPExtractor {
+	return func(req *http.Request) string {
+		if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+			return host
+		}
+		return req.RemoteAddr
+	}
+}
+
+// sharedRateLimiterConfig は共有リンクの閲覧の制限。IP アドレスごとに SHARE_RATE_LIMIT_PER_MINUTE 回 (既定 30 回) まで。
+// 制限の状態はインスタンスのメモリに持つため、Lambda ではインスタンスごとの制限になる
+func sharedRateLimiterConfig() middleware.RateLimiterConfig {
+	perMinute, err := strconv.Atoi(os.Getenv("SHARE_RATE_LIMIT_PER_MINUTE"))
+	if err != nil || perMinute <= 0 {
+		perMinute = 30 // デフォルト値
+	}
+	return middleware.RateLimiterConfig{
+		Store: middleware.NewRateLimiterMemoryStoreWithConfig(middleware.RateLimiterMemoryStoreConfig{
+			Rate:      rate.Limit(float64(perMinute) / 60),
+			Burst:     perMinute,
+			ExpiresIn: 3 * time.Minute,
+		}),
+		IdentifierExtractor: func(c echo.Context) (string, error) {
+			return c.RealIP(), nil
+		},
+		DenyHandler: func(c echo.Context, identifier string, err error) error {
+			return c.JSON(http.StatusTooManyRequests, map[string]string{"error": "too many requests"})
+		},
+	}
+}
+
 func loadSecretsFromSSM() error {
 	type target struct {
 		envKey   string
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSharedRateLimiter(t *testing.T) {
+	t.Setenv("SHARE_RATE_LIMIT_PER_MINUTE", "1")
+
+	e := echo.New()
+	e.IPExtractor = clientIPExtractor()
+	e.GET("/shared", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, middleware.RateLimiterWithConfig(sharedRateLimiterConfig()))
+
+	request := func(remoteAddr, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, "/shared", nil)
+		req.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+			req.Header.Set(echo.HeaderXRealIP, forwardedFor)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request("203.0.113.10:40000", ""))
+	// 偽の X-Forwarded-For を付けても同じ接続元として数える
+	assert.Equal(t, http.StatusTooManyRequests, request("203.0.113.10:40001", "198.51.100.1"))
+	assert.Equal(t, http.StatusTooManyRequests, request("203.0.113.10:40002", "198.51.100.2"))
+	// 別の接続元は別の枠で数える
+	assert.Equal(t, http.StatusOK, request("203.0.113.20:40000", ""))
+
+	// Lambda では RemoteAddr がポートなしの送信元になる。接続元ごとに別の枠で数える
+	assert.Equal(t, http.StatusOK, request("198.51.100.30", ""))
+	assert.Equal(t, http.StatusTooManyRequests, request("198.51.100.30", "192.0.2.1"))
+	assert.Equal(t, http.StatusOK, request("198.51.100.31", ""))
+}
+
+func TestClientIPExtractor(t *testing.T) {
+	extract := clientIPExtractor()
+	testCases := map[string]string{
+		"203.0.113.10:40000": "203.0.113.10",
+		"[2001:db8::1]:443":  "2001:db8::1",
+		"203.0.113.10":       "203.0.113.10",
+		"2001:db8::1":        "2001:db8::1",
+	}
+	for remoteAddr, want := range testCases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(echo.HeaderXForwardedFor, "192.0.2.1")
+		assert.Equal(t, want, extract(req), remoteAddr)
+	}
+}
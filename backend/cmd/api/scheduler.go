@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/service"
+)
+
+// scheduledJobs は定期実行するバッチ処理をまとめたもの。
+// Lambda では EventBridge のスケジュールイベント、サーバーモードでは内部のループから呼び出す
+type scheduledJobs struct {
+	dailyStoryService service.IDailyStoryService
+	storyService      service.IStoryService
+	// trashRetention を過ぎたゴミ箱のストーリーを物理削除する
+	trashRetention time.Duration
+}
+
+// run は各ジョブを実行する。あるジョブが失敗しても他のジョブは実行する。
+// LLM を呼び出す「今日の文章」の生成は時間がかかるため最後に実行し、ctx の期限までに終わらない分は次回に回す
+func (j *scheduledJobs) run(ctx context.Context, now time.Time) {
+	j.purgeTrash(now)
+	j.backfillSimhashes()
+	j.deliverDailyStories(ctx, now)
+}
+
+func (j *scheduledJobs) deliverDailyStories(ctx context.Context, now time.Time) {
+	report, err := j.dailyStoryService.DeliverDueStories(ctx, now)
+	if err != nil {
+		log.Printf("daily story job failed: %v", err)
+		return
+	}
+	log.Printf("daily story job finished: delivered=%d skipped=%d failed=%d deferred=%d", report.Delivered, report.Skipped, report.Failed, report.Deferred)
+}
+
+func (j *scheduledJobs) purgeTrash(now time.Time) {
+	purged, err := j.storyService.PurgeTrash(now.Add(-j.trashRetention))
+	if err != nil {
+		log.Printf("trash purge job failed: %v", err)
+		return
+	}
+	log.Printf("trash purge job finished: purged=%d", purged)
+}
+
+// backfillSimhashes は simhash 列の追加前に作成したストーリーの SimHash を少しずつ埋める
+func (j *scheduledJobs) backfillSimhashes() {
+	filled, err := j.storyService.BackfillSimhashes()
+	if err != nil {
+		log.Printf("simhash backfill job failed: %v", err)
+		return
+	}
+	if filled > 0 {
+		log.Printf("simhash backfill job finished: filled=%d", filled)
+	}
+}
+
+// loop は ctx が終了するまで interval ごとにジョブを実行する
+func (j *scheduledJobs) loop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			j.run(ctx, now)
+		}
+	}
+}
+
+// schedulerInterval はサーバーモードでのジョブ実行間隔を返す。0 の場合はループを起動しない
+func schedulerInterval() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("SCHEDULER_INTERVAL_MINUTES"))
+	if err != nil || minutes < 0 {
+		return 10 * time.Minute // デフォルト値
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// trashRetention はゴミ箱のストーリーを保持する期間を返す
+func trashRetention() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS"))
+	if err != nil || days <= 0 {
+		return 30 * 24 * time.Hour // デフォルト値
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// isScheduledEvent は Lambda への入力が EventBridge のスケジュールイベントかどうかを判定する
+func isScheduledEvent(payload json.RawMessage) bool {
+	var event struct {
+		Source     string `json:"source"`
+		DetailType string `json:"detail-type"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return false
+	}
+	return event.Source == "aws.events" && event.DetailType == "Scheduled Event"
+}
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_stories_series_id_chapter_number;
+
+ALTER TABLE stories DROP CONSTRAINT IF EXISTS fk_series;
+ALTER TABLE stories DROP COLUMN IF EXISTS chapter_number;
+ALTER TABLE stories DROP COLUMN IF EXISTS series_id;
+
+DROP TABLE IF EXISTS series;
@@ -0,0 +1,32 @@
+-- series テーブル (連載ストーリー)
+CREATE TABLE IF NOT EXISTS series (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    -- これまでの章の圧縮された要約 (次章生成時のコンテキスト)
+    summary TEXT NOT NULL DEFAULT '',
+    summarized_through INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user
+        FOREIGN KEY(user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+CREATE TRIGGER set_timestamp_series
+BEFORE UPDATE ON series
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_timestamp();
+
+-- stories を連載に紐付ける
+ALTER TABLE stories ADD COLUMN series_id INTEGER;
+ALTER TABLE stories ADD COLUMN chapter_number INTEGER CHECK (chapter_number > 0);
+
+ALTER TABLE stories ADD CONSTRAINT fk_series
+    FOREIGN KEY (series_id)
+    REFERENCES series(id)
+    ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stories_series_id_chapter_number
+    ON stories (series_id, chapter_number);
@@ -0,0 +1,44 @@
+-- quiz_questions テーブル (ストーリーごとの読解問題)
+CREATE TABLE IF NOT EXISTS quiz_questions (
+    id SERIAL PRIMARY KEY,
+    story_id INTEGER NOT NULL,
+    question_type VARCHAR(20) NOT NULL CHECK (question_type IN ('multiple_choice', 'true_false')),
+    question TEXT NOT NULL,
+    choices TEXT[] NOT NULL,
+    correct_index INTEGER NOT NULL CHECK (correct_index >= 0),
+    position INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_story
+        FOREIGN KEY (story_id)
+        REFERENCES stories(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_questions_story_id_position
+    ON quiz_questions (story_id, position);
+
+-- quiz_attempts テーブル (解答と採点結果)
+CREATE TABLE IF NOT EXISTS quiz_attempts (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    story_id INTEGER NOT NULL,
+    answers INTEGER[] NOT NULL,
+    score INTEGER NOT NULL CHECK (score >= 0),
+    total INTEGER NOT NULL CHECK (total >= 0),
+    passed BOOLEAN NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_story
+        FOREIGN KEY (story_id)
+        REFERENCES stories(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_attempts_user_story_created_at_desc
+    ON quiz_attempts (user_id, story_id, created_at DESC);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_stories_parent_story_id;
+
+ALTER TABLE stories DROP CONSTRAINT IF EXISTS fk_parent_story;
+ALTER TABLE stories DROP COLUMN IF EXISTS parent_story_id;
+ALTER TABLE stories DROP COLUMN IF EXISTS level;
@@ -0,0 +1,11 @@
+-- CEFR レベルと、書き換え元のストーリーへの参照を追加
+ALTER TABLE stories ADD COLUMN level VARCHAR(2) CHECK (level IN ('A1', 'A2', 'B1', 'B2', 'C1', 'C2'));
+ALTER TABLE stories ADD COLUMN parent_story_id INTEGER;
+
+ALTER TABLE stories ADD CONSTRAINT fk_parent_story
+    FOREIGN KEY (parent_story_id)
+    REFERENCES stories(id)
+    ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_stories_parent_story_id
+    ON stories (parent_story_id);
@@ -0,0 +1,20 @@
+-- story_translations テーブル (段落単位で本文と対応付けた翻訳)
+CREATE TABLE IF NOT EXISTS story_translations (
+    story_id INTEGER NOT NULL,
+    language VARCHAR(10) NOT NULL DEFAULT 'ja',
+    paragraphs TEXT[] NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (story_id, language),
+
+    CONSTRAINT fk_story
+        FOREIGN KEY (story_id)
+        REFERENCES stories(id)
+        ON DELETE CASCADE
+);
+
+CREATE TRIGGER set_timestamp_story_translations
+BEFORE UPDATE ON story_translations
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_timestamp();
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS prompt_templates;
+ALTER TABLE users DROP COLUMN IF EXISTS is_admin;
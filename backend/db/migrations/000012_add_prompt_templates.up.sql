@@ -0,0 +1,28 @@
+-- 管理者フラグ (グローバルなプリセットテンプレートの公開に使用)
+ALTER TABLE users ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- prompt_templates テーブル
+-- user_id が NULL のテンプレートは全ユーザーに公開されるプリセット
+CREATE TABLE IF NOT EXISTS prompt_templates (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER,
+    name VARCHAR(100) NOT NULL,
+    body TEXT NOT NULL,
+    default_level VARCHAR(2) CHECK (default_level IN ('A1', 'A2', 'B1', 'B2', 'C1', 'C2')),
+    default_word_count INTEGER CHECK (default_word_count > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_prompt_templates_user_id
+    ON prompt_templates (user_id);
+
+CREATE TRIGGER set_timestamp_prompt_templates
+BEFORE UPDATE ON prompt_templates
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_timestamp();
@@ -0,0 +1,29 @@
+-- daily_story_preferences テーブル (毎日の自動生成の設定)
+-- delivery_hour は time_zone における配信時刻 (0-23 時)
+CREATE TABLE IF NOT EXISTS daily_story_preferences (
+    user_id INTEGER PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    topics TEXT[] NOT NULL,
+    level VARCHAR(2) CHECK (level IN ('A1', 'A2', 'B1', 'B2', 'C1', 'C2')),
+    word_count INTEGER CHECK (word_count > 0),
+    delivery_hour SMALLINT NOT NULL DEFAULT 7 CHECK (delivery_hour BETWEEN 0 AND 23),
+    time_zone VARCHAR(64) NOT NULL DEFAULT 'Asia/Tokyo',
+    last_delivered_on DATE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+-- ジョブは有効な設定のみを走査する
+CREATE INDEX IF NOT EXISTS idx_daily_story_preferences_enabled
+    ON daily_story_preferences (user_id)
+    WHERE enabled;
+
+CREATE TRIGGER set_timestamp_daily_story_preferences
+BEFORE UPDATE ON daily_story_preferences
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_timestamp();
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_stories_search_vector;
+ALTER TABLE stories DROP COLUMN IF EXISTS search_vector;
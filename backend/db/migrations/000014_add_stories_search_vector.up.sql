@@ -0,0 +1,10 @@
+-- 全文検索用の tsvector 列 (タイトルを本文より高く重み付け)
+ALTER TABLE stories
+    ADD COLUMN search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(content, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_stories_search_vector
+    ON stories USING GIN (search_vector);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_stories_user_id_level_created_at_desc;
+DROP INDEX IF EXISTS idx_stories_user_id_title;
+DROP INDEX IF EXISTS idx_stories_user_id_word_count;
@@ -0,0 +1,9 @@
+-- ストーリー一覧の絞り込み・並び替え用の複合インデックス
+CREATE INDEX IF NOT EXISTS idx_stories_user_id_word_count
+    ON stories (user_id, word_count, id);
+
+CREATE INDEX IF NOT EXISTS idx_stories_user_id_title
+    ON stories (user_id, title, id);
+
+CREATE INDEX IF NOT EXISTS idx_stories_user_id_level_created_at_desc
+    ON stories (user_id, level, created_at DESC);
@@ -0,0 +1,40 @@
+-- tags テーブル (ユーザーごとのタグ)
+CREATE TABLE IF NOT EXISTS tags (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT uq_tags_user_id_name UNIQUE (user_id, name)
+);
+
+CREATE TRIGGER set_timestamp_tags
+BEFORE UPDATE ON tags
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_timestamp();
+
+-- story_tags テーブル (ストーリーとタグの多対多)
+CREATE TABLE IF NOT EXISTS story_tags (
+    story_id INTEGER NOT NULL,
+    tag_id INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (story_id, tag_id),
+    CONSTRAINT fk_story
+        FOREIGN KEY (story_id)
+        REFERENCES stories(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_tag
+        FOREIGN KEY (tag_id)
+        REFERENCES tags(id)
+        ON DELETE CASCADE
+);
+
+-- タグによる一覧の絞り込みに使用
+CREATE INDEX IF NOT EXISTS idx_story_tags_tag_id_story_id
+    ON story_tags (tag_id, story_id);
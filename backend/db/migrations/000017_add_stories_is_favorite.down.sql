@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_stories_user_id_favorite_created_at_desc;
+ALTER TABLE stories DROP COLUMN IF EXISTS is_favorite;
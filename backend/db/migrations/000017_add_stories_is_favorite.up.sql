@@ -0,0 +1,7 @@
+-- お気に入り (繰り返し読む文章) のフラグ
+ALTER TABLE stories ADD COLUMN is_favorite BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- お気に入りでの一覧の絞り込みに使用
+CREATE INDEX IF NOT EXISTS idx_stories_user_id_favorite_created_at_desc
+    ON stories (user_id, created_at DESC)
+    WHERE is_favorite;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS shelf_stories;
+DROP TABLE IF EXISTS shelves;
@@ -0,0 +1,45 @@
+-- shelves テーブル (順序付きの読書リスト)
+CREATE TABLE IF NOT EXISTS shelves (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_shelves_user_id
+    ON shelves (user_id);
+
+CREATE TRIGGER set_timestamp_shelves
+BEFORE UPDATE ON shelves
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_timestamp();
+
+-- shelf_stories テーブル (棚に並べたストーリー。同じストーリーを複数の棚に置ける)
+-- 並べ替えでは位置を入れ替えるため、位置の一意制約はトランザクションの終わりで検査する
+CREATE TABLE IF NOT EXISTS shelf_stories (
+    shelf_id INTEGER NOT NULL,
+    story_id INTEGER NOT NULL,
+    position INTEGER NOT NULL CHECK (position > 0),
+    added_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (shelf_id, story_id),
+    CONSTRAINT uq_shelf_stories_shelf_id_position
+        UNIQUE (shelf_id, position) DEFERRABLE INITIALLY DEFERRED,
+    CONSTRAINT fk_shelf
+        FOREIGN KEY (shelf_id)
+        REFERENCES shelves(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_story
+        FOREIGN KEY (story_id)
+        REFERENCES stories(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_shelf_stories_story_id
+    ON shelf_stories (story_id);
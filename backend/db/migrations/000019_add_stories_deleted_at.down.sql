@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_stories_deleted_at;
+ALTER TABLE stories DROP COLUMN IF EXISTS deleted_at;
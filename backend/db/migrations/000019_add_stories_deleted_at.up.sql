@@ -0,0 +1,7 @@
+-- 論理削除 (ゴミ箱)。保持期間を過ぎたものは定期ジョブで物理削除する
+ALTER TABLE stories ADD COLUMN deleted_at TIMESTAMPTZ;
+
+-- ゴミ箱の一覧と物理削除の対象検索に使用
+CREATE INDEX IF NOT EXISTS idx_stories_deleted_at
+    ON stories (deleted_at)
+    WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,17 @@
+-- story_revisions テーブル (本文を編集する前の版。番号はストーリーごとに 1 から振る)
+CREATE TABLE IF NOT EXISTS story_revisions (
+    id SERIAL PRIMARY KEY,
+    story_id INTEGER NOT NULL,
+    revision_number INTEGER NOT NULL CHECK (revision_number > 0),
+    title VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    word_count INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_story_revisions_story_id_revision_number
+        UNIQUE (story_id, revision_number),
+    CONSTRAINT fk_story
+        FOREIGN KEY (story_id)
+        REFERENCES stories(id)
+        ON DELETE CASCADE
+);
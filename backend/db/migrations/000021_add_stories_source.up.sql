@@ -0,0 +1,4 @@
+-- 文章の出どころ (LLM で生成したものか、ユーザーが取り込んだものか)
+ALTER TABLE stories
+    ADD COLUMN source VARCHAR(20) NOT NULL DEFAULT 'generated'
+        CHECK (source IN ('generated', 'imported'));
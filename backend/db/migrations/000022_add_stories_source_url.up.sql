@@ -0,0 +1,2 @@
+-- URL から取り込んだ文章の取得元 (ページの正規の URL)
+ALTER TABLE stories ADD COLUMN source_url TEXT;
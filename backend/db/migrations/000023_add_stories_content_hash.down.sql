@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_stories_user_id_content_hash;
+ALTER TABLE stories DROP COLUMN IF EXISTS content_hash;
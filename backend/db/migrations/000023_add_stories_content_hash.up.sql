@@ -0,0 +1,6 @@
+-- 本文の SHA-256 (16 進)。アーカイブの取り込みで、同じ本文のストーリーが既にあるかを判定する
+ALTER TABLE stories ADD COLUMN content_hash VARCHAR(64);
+
+UPDATE stories SET content_hash = encode(sha256(convert_to(content, 'UTF8')), 'hex');
+
+CREATE INDEX IF NOT EXISTS idx_stories_user_id_content_hash ON stories (user_id, content_hash);
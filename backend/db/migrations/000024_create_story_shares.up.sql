@@ -0,0 +1,16 @@
+-- story_shares テーブル (ログインなしで読める共有リンク)
+CREATE TABLE IF NOT EXISTS story_shares (
+    id SERIAL PRIMARY KEY,
+    story_id INTEGER NOT NULL,
+    token VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_story
+        FOREIGN KEY (story_id)
+        REFERENCES stories(id)
+        ON DELETE CASCADE,
+    CONSTRAINT uq_story_shares_token UNIQUE (token)
+);
+
+CREATE INDEX IF NOT EXISTS idx_story_shares_story_id ON story_shares (story_id);
@@ -0,0 +1,7 @@
+UPDATE stories SET source = 'imported' WHERE source = 'community';
+ALTER TABLE stories DROP CONSTRAINT IF EXISTS stories_source_check;
+ALTER TABLE stories ADD CONSTRAINT stories_source_check
+    CHECK (source IN ('generated', 'imported'));
+
+DROP TABLE IF EXISTS community_story_likes;
+DROP TABLE IF EXISTS community_stories;
@@ -0,0 +1,66 @@
+-- community_stories テーブル (公開ライブラリ)
+-- 公開時点のタイトル・本文の写しを持つため、元のストーリーを編集・削除しても公開中の内容は変わらない
+-- user_id は公開したユーザー。閲覧する側には返さない
+CREATE TABLE IF NOT EXISTS community_stories (
+    id SERIAL PRIMARY KEY,
+    story_id INTEGER,
+    user_id INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    word_count INTEGER NOT NULL,
+    level VARCHAR(2) NOT NULL CHECK (level IN ('A1', 'A2', 'B1', 'B2', 'C1', 'C2')),
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    like_count INTEGER NOT NULL DEFAULT 0,
+    search_vector TSVECTOR
+        GENERATED ALWAYS AS (
+            setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+            setweight(to_tsvector('english', coalesce(content, '')), 'B')
+        ) STORED,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_story
+        FOREIGN KEY (story_id)
+        REFERENCES stories(id)
+        ON DELETE SET NULL,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    -- 1 つのストーリーは 1 度だけ公開でき、再公開は内容の更新になる
+    CONSTRAINT uq_community_stories_story_id UNIQUE (story_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_community_stories_created_at
+    ON community_stories (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_community_stories_search_vector
+    ON community_stories USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_community_stories_tags
+    ON community_stories USING GIN (tags);
+
+CREATE TRIGGER set_timestamp_community_stories
+BEFORE UPDATE ON community_stories
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_timestamp();
+
+-- community_story_likes テーブル (ユーザーごとに 1 回だけ「いいね」できる)
+CREATE TABLE IF NOT EXISTS community_story_likes (
+    community_story_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (community_story_id, user_id),
+    CONSTRAINT fk_community_story
+        FOREIGN KEY (community_story_id)
+        REFERENCES community_stories(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+-- 公開ライブラリから複製したストーリーの出どころ
+ALTER TABLE stories DROP CONSTRAINT IF EXISTS stories_source_check;
+ALTER TABLE stories ADD CONSTRAINT stories_source_check
+    CHECK (source IN ('generated', 'imported', 'community'));
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_stories_simhash_missing;
+ALTER TABLE stories DROP COLUMN IF EXISTS simhash;
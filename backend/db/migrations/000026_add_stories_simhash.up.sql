@@ -0,0 +1,7 @@
+-- 本文の SimHash (本文がほぼ同じストーリーの検出に使う)
+-- SQL では計算できないため、既存のストーリーは定期ジョブが少しずつ計算して埋める
+ALTER TABLE stories ADD COLUMN simhash BIGINT;
+
+CREATE INDEX IF NOT EXISTS idx_stories_simhash_missing
+    ON stories (id)
+    WHERE simhash IS NULL;
@@ -0,0 +1,122 @@
+package archive
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidArchive = errors.New("invalid archive")
+	ErrTooLarge       = errors.New("archive is too large")
+)
+
+// MaxUncompressedBytes は展開して読み込むファイルの合計サイズの上限 (zip 爆弾対策)
+const MaxUncompressedBytes = 128 * 1024 * 1024
+
+// Archive はアーカイブから読み取ったライブラリ。Stories は manifest.json の順に並ぶ
+type Archive struct {
+	ExportedAt time.Time
+	Tags       []string
+	Stories    []*ArchivedStory
+}
+
+// ArchivedStory はアーカイブから読み取ったストーリー。File はアーカイブ内の Markdown のパス
+type ArchivedStory struct {
+	Story
+	File string
+}
+
+// Read はアーカイブを読み込む。本文以外の情報は manifest.json を正とし、Markdown の front matter は読み飛ばす。
+// 本文は Markdown のものを使うため、展開して編集した本文もそのまま取り込める
+func Read(r io.ReaderAt, size int64) (*Archive, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	budget := int64(MaxUncompressedBytes)
+	readFile := func(name string) ([]byte, error) {
+		f, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing %s", ErrInvalidArchive, name)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+		defer rc.Close()
+		// 宣言されたサイズは信用せず、実際に読んだ量で上限を確認する
+		data, err := io.ReadAll(io.LimitReader(rc, budget+1))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+		if int64(len(data)) > budget {
+			return nil, ErrTooLarge
+		}
+		budget -= int64(len(data))
+		return data, nil
+	}
+
+	data, err := readFile(ManifestName)
+	if err != nil {
+		return nil, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	if manifest.Format != FormatName {
+		return nil, fmt.Errorf("%w: unknown format %q", ErrInvalidArchive, manifest.Format)
+	}
+	if manifest.Version < 1 || manifest.Version > FormatVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidArchive, manifest.Version)
+	}
+
+	archive := &Archive{ExportedAt: manifest.ExportedAt, Tags: manifest.Tags}
+	for _, entry := range manifest.Stories {
+		if entry == nil {
+			return nil, fmt.Errorf("%w: empty story entry", ErrInvalidArchive)
+		}
+		data, err := readFile(entry.File)
+		if err != nil {
+			return nil, err
+		}
+		archive.Stories = append(archive.Stories, &ArchivedStory{
+			File: entry.File,
+			Story: Story{
+				ID:             entry.ID,
+				Title:          entry.Title,
+				Content:        stripFrontMatter(string(data)),
+				WordCount:      entry.WordCount,
+				Level:          entry.Level,
+				Tags:           entry.Tags,
+				Source:         entry.Source,
+				SourceURL:      entry.SourceURL,
+				IsFavorite:     entry.IsFavorite,
+				CreatedAt:      entry.CreatedAt,
+				ReadingRecords: entry.ReadingRecords,
+			},
+		})
+	}
+	return archive, nil
+}
+
+// stripFrontMatter は AddStory が書き込んだ front matter と、その後の空行・末尾の改行を取り除く
+func stripFrontMatter(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	if rest, ok := strings.CutPrefix(s, "---\n"); ok {
+		if _, body, found := strings.Cut(rest, "\n---\n"); found {
+			s = strings.TrimPrefix(body, "\n")
+		}
+	}
+	return strings.TrimSuffix(s, "\n")
+}
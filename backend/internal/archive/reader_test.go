@@ -0,0 +1,124 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildTestZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestRead(t *testing.T) {
+	t.Run("success: should read back what Writer wrote", func(t *testing.T) {
+		level := "A2"
+		sourceURL := "https://news.example.com/rivers"
+		exportedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+		stories := []*Story{
+			{
+				ID:             7,
+				Title:          "Rivers --- Rising",
+				Content:        "# Part 1\n\n---\n\nHeavy rain fell.\n",
+				WordCount:      5,
+				Level:          &level,
+				Tags:           []string{"news"},
+				Source:         "imported",
+				SourceURL:      &sourceURL,
+				IsFavorite:     true,
+				CreatedAt:      time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC),
+				ReadingRecords: []*ReadingRecord{{ReadAt: time.Date(2024, 5, 2, 8, 30, 0, 0, time.UTC), WordCount: 5}},
+			},
+			{ID: 8, Title: "日本語", Content: "Tom found a key.", WordCount: 4, Source: "generated", CreatedAt: exportedAt},
+		}
+
+		var buf bytes.Buffer
+		w := NewWriter(&buf, exportedAt, []string{"news", "unused"})
+		for _, story := range stories {
+			require.NoError(t, w.AddStory(story))
+		}
+		require.NoError(t, w.Close())
+
+		archive, err := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+
+		require.NoError(t, err)
+		assert.Equal(t, exportedAt, archive.ExportedAt)
+		assert.Equal(t, []string{"news", "unused"}, archive.Tags)
+		require.Len(t, archive.Stories, 2)
+		assert.Equal(t, "stories/00007-rivers-rising.md", archive.Stories[0].File)
+		// 本文は末尾の改行も含めてそのまま戻り、ContentHash が一致する
+		assert.Equal(t, stories[0].Content, archive.Stories[0].Content)
+		assert.Equal(t, ContentHash(stories[0].Content), ContentHash(archive.Stories[0].Content))
+		assert.Equal(t, "Rivers --- Rising", archive.Stories[0].Title)
+		assert.Equal(t, &level, archive.Stories[0].Level)
+		assert.Equal(t, &sourceURL, archive.Stories[0].SourceURL)
+		assert.True(t, archive.Stories[0].IsFavorite)
+		assert.Equal(t, []string{"news"}, archive.Stories[0].Tags)
+		assert.Equal(t, stories[0].ReadingRecords, archive.Stories[0].ReadingRecords)
+		assert.Equal(t, "Tom found a key.", archive.Stories[1].Content)
+		assert.Nil(t, archive.Stories[1].Level)
+		assert.Empty(t, archive.Stories[1].ReadingRecords)
+	})
+
+	t.Run("success: should accept files without front matter and with CRLF line endings", func(t *testing.T) {
+		data := buildTestZip(t, map[string]string{
+			ManifestName:       `{"format": "readoku-archive", "version": 1, "tags": [], "stories": [{"id": 1, "file": "a.md", "title": "A"}]}`,
+			"a.md":             "Line one.\r\nLine two.\r\n",
+			"stories/extra.md": "ignored",
+		})
+
+		archive, err := Read(bytes.NewReader(data), int64(len(data)))
+
+		require.NoError(t, err)
+		require.Len(t, archive.Stories, 1)
+		assert.Equal(t, "Line one.\nLine two.", archive.Stories[0].Content)
+	})
+
+	t.Run("fail: should reject archives of another format or a newer version", func(t *testing.T) {
+		for _, manifest := range []string{
+			`{"format": "other", "version": 1}`,
+			`{"format": "readoku-archive", "version": 2}`,
+			`not json`,
+		} {
+			data := buildTestZip(t, map[string]string{ManifestName: manifest})
+
+			_, err := Read(bytes.NewReader(data), int64(len(data)))
+
+			assert.ErrorIs(t, err, ErrInvalidArchive, manifest)
+		}
+	})
+
+	t.Run("fail: should reject archives without a manifest or with missing files", func(t *testing.T) {
+		data := buildTestZip(t, map[string]string{"a.md": "text"})
+		_, err := Read(bytes.NewReader(data), int64(len(data)))
+		assert.ErrorIs(t, err, ErrInvalidArchive)
+
+		data = buildTestZip(t, map[string]string{
+			ManifestName: `{"format": "readoku-archive", "version": 1, "stories": [{"id": 1, "file": "../a.md", "title": "A"}]}`,
+		})
+		_, err = Read(bytes.NewReader(data), int64(len(data)))
+		assert.ErrorIs(t, err, ErrInvalidArchive)
+	})
+
+	t.Run("fail: should reject data that is not a zip", func(t *testing.T) {
+		data := []byte("not a zip")
+
+		_, err := Read(bytes.NewReader(data), int64(len(data)))
+
+		assert.ErrorIs(t, err, ErrInvalidArchive)
+	})
+}
@@ -0,0 +1,205 @@
+// Package archive はライブラリのアーカイブ (zip) の形式を扱う。
+// ストーリーごとに YAML の front matter 付きの Markdown と、全体の一覧である manifest.json を含む
+package archive
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// FormatName と FormatVersion は manifest.json に書き込む形式の識別子
+	FormatName    = "readoku-archive"
+	FormatVersion = 1
+	// ManifestName はアーカイブ内の manifest.json のパス
+	ManifestName = "manifest.json"
+)
+
+// Manifest はアーカイブの一覧。本文以外のストーリーの情報と、ユーザーのタグをすべて含む
+type Manifest struct {
+	Format     string           `json:"format"`
+	Version    int              `json:"version"`
+	ExportedAt time.Time        `json:"exported_at"`
+	Tags       []string         `json:"tags"`
+	Stories    []*ManifestStory `json:"stories"`
+}
+
+// ManifestStory は manifest.json のストーリー 1 件。File はアーカイブ内の Markdown のパス
+type ManifestStory struct {
+	ID             int              `json:"id"`
+	File           string           `json:"file"`
+	Title          string           `json:"title"`
+	WordCount      int              `json:"word_count"`
+	Level          *string          `json:"level"`
+	Tags           []string         `json:"tags"`
+	Source         string           `json:"source"`
+	SourceURL      *string          `json:"source_url,omitempty"`
+	IsFavorite     bool             `json:"is_favorite"`
+	CreatedAt      time.Time        `json:"created_at"`
+	ContentSHA256  string           `json:"content_sha256"`
+	ReadingRecords []*ReadingRecord `json:"reading_records"`
+}
+
+// ReadingRecord は読了記録 1 件。WordCount は読んだ時点の語数
+type ReadingRecord struct {
+	ReadAt    time.Time `json:"read_at"`
+	WordCount int       `json:"word_count"`
+}
+
+// Story はアーカイブに書き込むストーリー
+type Story struct {
+	ID             int
+	Title          string
+	Content        string
+	WordCount      int
+	Level          *string
+	Tags           []string
+	Source         string
+	SourceURL      *string
+	IsFavorite     bool
+	CreatedAt      time.Time
+	ReadingRecords []*ReadingRecord
+}
+
+// Writer はアーカイブを順に書き出す。本文は AddStory のたびに書き込み、保持するのは manifest の情報だけにする
+type Writer struct {
+	zw       *zip.Writer
+	manifest Manifest
+}
+
+func NewWriter(w io.Writer, exportedAt time.Time, tags []string) *Writer {
+	if tags == nil {
+		tags = []string{}
+	}
+	return &Writer{
+		zw: zip.NewWriter(w),
+		manifest: Manifest{
+			Format:     FormatName,
+			Version:    FormatVersion,
+			ExportedAt: exportedAt.UTC(),
+			Tags:       tags,
+			Stories:    []*ManifestStory{},
+		},
+	}
+}
+
+// AddStory はストーリーを stories/<id>-<タイトル>.md として書き込む
+func (w *Writer) AddStory(story *Story) error {
+	entry := &ManifestStory{
+		ID:             story.ID,
+		File:           fmt.Sprintf("stories/%05d-%s.md", story.ID, slug(story.Title)),
+		Title:          story.Title,
+		WordCount:      story.WordCount,
+		Level:          story.Level,
+		Tags:           story.Tags,
+		Source:         story.Source,
+		SourceURL:      story.SourceURL,
+		IsFavorite:     story.IsFavorite,
+		CreatedAt:      story.CreatedAt.UTC(),
+		ContentSHA256:  ContentHash(story.Content),
+		ReadingRecords: story.ReadingRecords,
+	}
+	if entry.Tags == nil {
+		entry.Tags = []string{}
+	}
+	if entry.ReadingRecords == nil {
+		entry.ReadingRecords = []*ReadingRecord{}
+	}
+
+	fw, err := w.zw.CreateHeader(&zip.FileHeader{Name: entry.File, Method: zip.Deflate, Modified: entry.CreatedAt})
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", entry.File, err)
+	}
+	if _, err := io.WriteString(fw, frontMatter(entry)+story.Content+"\n"); err != nil {
+		return fmt.Errorf("failed to write %s: %w", entry.File, err)
+	}
+	w.manifest.Stories = append(w.manifest.Stories, entry)
+	return nil
+}
+
+// Close は manifest.json を書き込み、アーカイブを閉じる
+func (w *Writer) Close() error {
+	fw, err := w.zw.Create(ManifestName)
+	if err != nil {
+		return fmt.Errorf("failed to create manifest: %w", err)
+	}
+	encoder := json.NewEncoder(fw)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(w.manifest); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if err := w.zw.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	return nil
+}
+
+// ContentHash は本文の SHA-256 を返す。取り込み時の重複の判定にも使う
+func ContentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// frontMatter は YAML の front matter を返す。文字列は JSON 形式 (YAML のダブルクォート文字列としても有効) で書く
+func frontMatter(story *ManifestStory) string {
+	level := "null"
+	if story.Level != nil {
+		level = quote(*story.Level)
+	}
+	tags := make([]string, len(story.Tags))
+	for i, tag := range story.Tags {
+		tags[i] = quote(tag)
+	}
+
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "id: %d\n", story.ID)
+	fmt.Fprintf(&b, "title: %s\n", quote(story.Title))
+	fmt.Fprintf(&b, "word_count: %d\n", story.WordCount)
+	fmt.Fprintf(&b, "created_at: %s\n", story.CreatedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "level: %s\n", level)
+	fmt.Fprintf(&b, "tags: [%s]\n", strings.Join(tags, ", "))
+	fmt.Fprintf(&b, "source: %s\n", quote(story.Source))
+	if story.SourceURL != nil {
+		fmt.Fprintf(&b, "source_url: %s\n", quote(*story.SourceURL))
+	}
+	b.WriteString("---\n\n")
+	return b.String()
+}
+
+func quote(s string) string {
+	var b strings.Builder
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(s)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// slug はタイトルをファイル名に使える形にする。英数字以外は "-" にまとめ、長すぎる場合は切り詰める
+func slug(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if b.Len() >= 60 {
+			break
+		}
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	name := strings.TrimSuffix(b.String(), "-")
+	if name == "" {
+		return "story"
+	}
+	return name
+}
@@ -0,0 +1,73 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	level := "B1"
+	createdAt := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	readAt := time.Date(2024, 5, 2, 8, 30, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, createdAt, []string{"news", "week 1"})
+	require.NoError(t, w.AddStory(&Story{
+		ID:             12,
+		Title:          `The "Lost" Key: Part 1`,
+		Content:        "Tom looked everywhere.",
+		WordCount:      3,
+		Level:          &level,
+		Tags:           []string{"week 1"},
+		Source:         "generated",
+		CreatedAt:      createdAt,
+		ReadingRecords: []*ReadingRecord{{ReadAt: readAt, WordCount: 3}},
+	}))
+	require.NoError(t, w.AddStory(&Story{ID: 13, Title: "日本語", Content: "Rivers & <lakes>", WordCount: 3, Source: "imported", CreatedAt: createdAt}))
+	require.NoError(t, w.Close())
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	files := map[string]string{}
+	var names []string
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[f.Name] = string(data)
+		names = append(names, f.Name)
+	}
+
+	// manifest はストーリーの後に書かれる
+	assert.Equal(t, []string{"stories/00012-the-lost-key-part-1.md", "stories/00013-story.md", ManifestName}, names)
+	assert.Equal(t, "---\n"+
+		"id: 12\n"+
+		"title: \"The \\\"Lost\\\" Key: Part 1\"\n"+
+		"word_count: 3\n"+
+		"created_at: 2024-05-01T09:00:00Z\n"+
+		"level: \"B1\"\n"+
+		"tags: [\"week 1\"]\n"+
+		"source: \"generated\"\n"+
+		"---\n\n"+
+		"Tom looked everywhere.\n", files["stories/00012-the-lost-key-part-1.md"])
+	assert.Contains(t, files["stories/00013-story.md"], "level: null\ntags: []\n")
+
+	var manifest Manifest
+	require.NoError(t, json.Unmarshal([]byte(files[ManifestName]), &manifest))
+	assert.Equal(t, FormatName, manifest.Format)
+	assert.Equal(t, []string{"news", "week 1"}, manifest.Tags)
+	require.Len(t, manifest.Stories, 2)
+	assert.Equal(t, ContentHash("Tom looked everywhere."), manifest.Stories[0].ContentSHA256)
+	require.Len(t, manifest.Stories[0].ReadingRecords, 1)
+	assert.True(t, readAt.Equal(manifest.Stories[0].ReadingRecords[0].ReadAt))
+	assert.Equal(t, "stories/00013-story.md", manifest.Stories[1].File)
+}
@@ -0,0 +1,178 @@
+// Package epub は EPUB ファイルの読み書きを扱う。外部のリソースは参照せず、アーカイブ内のファイルだけで完結する
+package epub
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+)
+
+var (
+	ErrInvalidEPUB = errors.New("invalid epub")
+	ErrTooLarge    = errors.New("epub is too large")
+)
+
+// MaxUncompressedBytes は展開して読み込むファイルの合計サイズの上限 (zip 爆弾対策)
+const MaxUncompressedBytes = 64 * 1024 * 1024
+
+// Book は EPUB から読み取った本。Chapters は spine の順に並ぶ
+type Book struct {
+	Title    string
+	Author   string
+	Language string
+	Chapters []Chapter
+}
+
+// Chapter は spine の 1 項目。Body は XHTML のまま返す
+type Chapter struct {
+	Href string
+	Body []byte
+}
+
+type container struct {
+	Rootfiles []struct {
+		FullPath string `xml:"full-path,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+type packageDocument struct {
+	Titles    []string `xml:"metadata>title"`
+	Creators  []string `xml:"metadata>creator"`
+	Languages []string `xml:"metadata>language"`
+	Manifest  []struct {
+		ID        string `xml:"id,attr"`
+		Href      string `xml:"href,attr"`
+		MediaType string `xml:"media-type,attr"`
+	} `xml:"manifest>item"`
+	Spine []struct {
+		IDRef  string `xml:"idref,attr"`
+		Linear string `xml:"linear,attr"`
+	} `xml:"spine>itemref"`
+}
+
+// chapterMediaTypes は本文として読み込むメディアタイプ
+var chapterMediaTypes = map[string]bool{
+	"application/xhtml+xml": true,
+	"text/html":             true,
+}
+
+// Read は EPUB を読み込み、spine の順に本文の XHTML を返す。linear="no" の項目 (表紙の付録など) は含めない
+func Read(r io.ReaderAt, size int64) (*Book, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEPUB, err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	budget := int64(MaxUncompressedBytes)
+	readFile := func(name string) ([]byte, error) {
+		f, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing %s", ErrInvalidEPUB, name)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidEPUB, err)
+		}
+		defer rc.Close()
+		// 宣言されたサイズは信用せず、実際に読んだ量で上限を確認する
+		data, err := io.ReadAll(io.LimitReader(rc, budget+1))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidEPUB, err)
+		}
+		if int64(len(data)) > budget {
+			return nil, ErrTooLarge
+		}
+		budget -= int64(len(data))
+		return data, nil
+	}
+
+	data, err := readFile("META-INF/container.xml")
+	if err != nil {
+		return nil, err
+	}
+	var c container
+	if err := xml.Unmarshal(data, &c); err != nil || len(c.Rootfiles) == 0 {
+		return nil, fmt.Errorf("%w: no rootfile in container.xml", ErrInvalidEPUB)
+	}
+	opfPath := c.Rootfiles[0].FullPath
+
+	data, err = readFile(opfPath)
+	if err != nil {
+		return nil, err
+	}
+	var pkg packageDocument
+	if err := xml.Unmarshal(data, &pkg); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEPUB, err)
+	}
+
+	book := &Book{
+		Title:    first(pkg.Titles),
+		Author:   first(pkg.Creators),
+		Language: first(pkg.Languages),
+	}
+
+	type manifestItem struct{ href, mediaType string }
+	manifest := make(map[string]manifestItem, len(pkg.Manifest))
+	for _, item := range pkg.Manifest {
+		manifest[item.ID] = manifestItem{href: item.Href, mediaType: item.MediaType}
+	}
+
+	baseDir := path.Dir(opfPath)
+	for _, ref := range pkg.Spine {
+		if ref.Linear == "no" {
+			continue
+		}
+		item, ok := manifest[ref.IDRef]
+		if !ok || !chapterMediaTypes[item.mediaType] {
+			continue
+		}
+		name, ok := resolveHref(baseDir, item.href)
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid href %q", ErrInvalidEPUB, item.href)
+		}
+		body, err := readFile(name)
+		if err != nil {
+			return nil, err
+		}
+		book.Chapters = append(book.Chapters, Chapter{Href: name, Body: body})
+	}
+	if len(book.Chapters) == 0 {
+		return nil, fmt.Errorf("%w: no chapters in spine", ErrInvalidEPUB)
+	}
+	return book, nil
+}
+
+// resolveHref は OPF からの相対パスをアーカイブ内のパスに解決する。アーカイブの外を指す場合は false を返す
+func resolveHref(baseDir, href string) (string, bool) {
+	href, _, _ = strings.Cut(href, "#")
+	if href == "" || strings.Contains(href, "://") || strings.HasPrefix(href, "/") {
+		return "", false
+	}
+	href, err := url.PathUnescape(href)
+	if err != nil {
+		return "", false
+	}
+	name := path.Clean(path.Join(baseDir, href))
+	if name == ".." || strings.HasPrefix(name, "../") {
+		return "", false
+	}
+	return name, true
+}
+
+func first(values []string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
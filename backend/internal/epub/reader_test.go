@@ -0,0 +1,94 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testContainer = `<?xml version="1.0"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles><rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/></rootfiles>
+</container>`
+
+const testPackage = `<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:title>The Graded Reader</dc:title>
+    <dc:creator>Jane Doe</dc:creator>
+    <dc:language>en</dc:language>
+  </metadata>
+  <manifest>
+    <item id="cover" href="cover.xhtml" media-type="application/xhtml+xml"/>
+    <item id="c2" href="text/chapter%202.xhtml" media-type="application/xhtml+xml"/>
+    <item id="c1" href="text/chapter1.xhtml" media-type="application/xhtml+xml"/>
+    <item id="img" href="images/cover.png" media-type="image/png"/>
+  </manifest>
+  <spine>
+    <itemref idref="cover" linear="no"/>
+    <itemref idref="c1"/>
+    <itemref idref="img"/>
+    <itemref idref="c2"/>
+  </spine>
+</package>`
+
+func buildTestEPUB(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestRead(t *testing.T) {
+	t.Run("success: should return chapters in spine order", func(t *testing.T) {
+		data := buildTestEPUB(t, map[string]string{
+			"mimetype":                   "application/epub+zip",
+			"META-INF/container.xml":     testContainer,
+			"OEBPS/content.opf":          testPackage,
+			"OEBPS/cover.xhtml":          "<html><body><img src='images/cover.png'/></body></html>",
+			"OEBPS/text/chapter1.xhtml":  "<html><body><h1>One</h1></body></html>",
+			"OEBPS/text/chapter 2.xhtml": "<html><body><h1>Two</h1></body></html>",
+			"OEBPS/images/cover.png":     "\x89PNG",
+		})
+
+		book, err := Read(bytes.NewReader(data), int64(len(data)))
+
+		require.NoError(t, err)
+		assert.Equal(t, "The Graded Reader", book.Title)
+		assert.Equal(t, "Jane Doe", book.Author)
+		assert.Equal(t, "en", book.Language)
+		require.Len(t, book.Chapters, 2)
+		assert.Equal(t, "OEBPS/text/chapter1.xhtml", book.Chapters[0].Href)
+		assert.Contains(t, string(book.Chapters[1].Body), "Two")
+	})
+
+	t.Run("fail: should reject hrefs outside the archive", func(t *testing.T) {
+		data := buildTestEPUB(t, map[string]string{
+			"META-INF/container.xml": testContainer,
+			"OEBPS/content.opf": `<package><manifest><item id="x" href="../../etc/passwd" media-type="application/xhtml+xml"/></manifest>
+				<spine><itemref idref="x"/></spine></package>`,
+		})
+
+		_, err := Read(bytes.NewReader(data), int64(len(data)))
+
+		assert.ErrorIs(t, err, ErrInvalidEPUB)
+	})
+
+	t.Run("fail: should reject files that are not zip archives", func(t *testing.T) {
+		data := []byte("<html>not an epub</html>")
+
+		_, err := Read(bytes.NewReader(data), int64(len(data)))
+
+		assert.ErrorIs(t, err, ErrInvalidEPUB)
+	})
+}
@@ -0,0 +1,204 @@
+package epub
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Publication は書き出す EPUB の内容。Identifier は本を一意に識別する URN (urn:uuid:... など)
+type Publication struct {
+	Identifier string
+	Title      string
+	Author     string
+	Language   string
+	Modified   time.Time
+	Sections   []Section
+}
+
+// Section は書き出す 1 章。Body は <body> の中に入れる XHTML の断片 (整形式であること)
+type Section struct {
+	Title string
+	Body  string
+}
+
+const containerXML = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+
+const stylesheet = `body { font-family: serif; line-height: 1.6; }
+h1 { font-size: 1.4em; margin: 1em 0; }
+p { margin: 0 0 0.8em; }
+blockquote { margin: 0 1.5em; font-style: italic; }
+pre { white-space: pre-wrap; font-size: 0.9em; }
+`
+
+var funcs = template.FuncMap{
+	"xml":         escapeXML,
+	"inc":         func(i int) int { return i + 1 },
+	"chapterFile": chapterFile,
+}
+
+// entry はアーカイブに書き込む 1 ファイル
+type entry struct {
+	name   string
+	render func(io.Writer) error
+}
+
+var packageTemplate = template.Must(template.New("content.opf").Funcs(funcs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="pub-id" xml:lang="{{xml .Language}}">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="pub-id">{{xml .Identifier}}</dc:identifier>
+    <dc:title>{{xml .Title}}</dc:title>
+    <dc:language>{{xml .Language}}</dc:language>
+{{- if .Author}}
+    <dc:creator>{{xml .Author}}</dc:creator>
+{{- end}}
+    <meta property="dcterms:modified">{{.Modified.UTC.Format "2006-01-02T15:04:05Z"}}</meta>
+  </metadata>
+  <manifest>
+    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+    <item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>
+    <item id="css" href="style.css" media-type="text/css"/>
+{{- range $i, $s := .Sections}}
+    <item id="chapter-{{inc $i}}" href="{{chapterFile $i}}" media-type="application/xhtml+xml"/>
+{{- end}}
+  </manifest>
+  <spine toc="ncx">
+{{- range $i, $s := .Sections}}
+    <itemref idref="chapter-{{inc $i}}"/>
+{{- end}}
+  </spine>
+</package>
+`))
+
+var navTemplate = template.Must(template.New("nav.xhtml").Funcs(funcs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="{{xml .Language}}" lang="{{xml .Language}}">
+<head>
+  <meta charset="UTF-8"/>
+  <title>{{xml .Title}}</title>
+  <link rel="stylesheet" type="text/css" href="style.css"/>
+</head>
+<body>
+  <nav epub:type="toc" id="toc">
+    <h1>Contents</h1>
+    <ol>
+{{- range $i, $s := .Sections}}
+      <li><a href="{{chapterFile $i}}">{{xml $s.Title}}</a></li>
+{{- end}}
+    </ol>
+  </nav>
+</body>
+</html>
+`))
+
+// ncxTemplate は EPUB 2 のみに対応したリーダー向けの目次
+var ncxTemplate = template.Must(template.New("toc.ncx").Funcs(funcs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+  <head>
+    <meta name="dtb:uid" content="{{xml .Identifier}}"/>
+  </head>
+  <docTitle><text>{{xml .Title}}</text></docTitle>
+  <navMap>
+{{- range $i, $s := .Sections}}
+    <navPoint id="nav-{{inc $i}}" playOrder="{{inc $i}}">
+      <navLabel><text>{{xml $s.Title}}</text></navLabel>
+      <content src="{{chapterFile $i}}"/>
+    </navPoint>
+{{- end}}
+  </navMap>
+</ncx>
+`))
+
+var chapterTemplate = template.Must(template.New("chapter.xhtml").Funcs(funcs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="{{xml .Language}}" lang="{{xml .Language}}">
+<head>
+  <meta charset="UTF-8"/>
+  <title>{{xml .Section.Title}}</title>
+  <link rel="stylesheet" type="text/css" href="style.css"/>
+</head>
+<body>
+  <section epub:type="chapter">
+    <h1>{{xml .Section.Title}}</h1>
+{{.Section.Body}}
+  </section>
+</body>
+</html>
+`))
+
+// Write は EPUB 3 を書き出す。mimetype を無圧縮の先頭のエントリにし、nav.xhtml と toc.ncx の目次を付ける
+func Write(w io.Writer, pub *Publication) error {
+	if len(pub.Sections) == 0 {
+		return fmt.Errorf("epub needs at least one section")
+	}
+	if pub.Language == "" {
+		copied := *pub
+		copied.Language = "en"
+		pub = &copied
+	}
+
+	zw := zip.NewWriter(w)
+	mimetype, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return fmt.Errorf("failed to write mimetype: %w", err)
+	}
+	if _, err := io.WriteString(mimetype, "application/epub+zip"); err != nil {
+		return fmt.Errorf("failed to write mimetype: %w", err)
+	}
+
+	files := []entry{
+		{"META-INF/container.xml", writeString(containerXML)},
+		{"OEBPS/content.opf", func(w io.Writer) error { return packageTemplate.Execute(w, pub) }},
+		{"OEBPS/nav.xhtml", func(w io.Writer) error { return navTemplate.Execute(w, pub) }},
+		{"OEBPS/toc.ncx", func(w io.Writer) error { return ncxTemplate.Execute(w, pub) }},
+		{"OEBPS/style.css", writeString(stylesheet)},
+	}
+	for i, section := range pub.Sections {
+		data := struct {
+			Language string
+			Section  Section
+		}{pub.Language, section}
+		files = append(files, entry{"OEBPS/" + chapterFile(i), func(w io.Writer) error { return chapterTemplate.Execute(w, data) }})
+	}
+
+	for _, file := range files {
+		fw, err := zw.Create(file.name)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", file.name, err)
+		}
+		if err := file.render(fw); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to finish epub: %w", err)
+	}
+	return nil
+}
+
+func chapterFile(i int) string {
+	return fmt.Sprintf("chapter-%03d.xhtml", i+1)
+}
+
+func writeString(s string) func(io.Writer) error {
+	return func(w io.Writer) error {
+		_, err := io.WriteString(w, s)
+		return err
+	}
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
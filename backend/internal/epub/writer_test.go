@@ -0,0 +1,75 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	pub := &Publication{
+		Identifier: "urn:uuid:0f1e2d3c-4b5a-6978-8695-a4b3c2d1e0f9",
+		Title:      "Stories & Tales",
+		Author:     "Readoku",
+		Modified:   time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC),
+		Sections: []Section{
+			{Title: "The <Lost> Key", Body: "<p>Tom looked everywhere.</p>"},
+			{Title: "Rivers", Body: "<p>The river rose.</p>"},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, pub))
+	data := buf.Bytes()
+
+	t.Run("mimetype is the first stored entry", func(t *testing.T) {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		require.NoError(t, err)
+		require.NotEmpty(t, zr.File)
+		assert.Equal(t, "mimetype", zr.File[0].Name)
+		assert.Equal(t, zip.Store, zr.File[0].Method)
+
+		// XML のファイルはすべて整形式である
+		for _, f := range zr.File[1:] {
+			if f.Name == "OEBPS/style.css" {
+				continue
+			}
+			rc, err := f.Open()
+			require.NoError(t, err)
+			decoder := xml.NewDecoder(rc)
+			decoder.Strict = true
+			for {
+				_, err := decoder.Token()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err, f.Name)
+			}
+			rc.Close()
+		}
+	})
+
+	t.Run("can be read back in order", func(t *testing.T) {
+		book, err := Read(bytes.NewReader(data), int64(len(data)))
+
+		require.NoError(t, err)
+		assert.Equal(t, "Stories & Tales", book.Title)
+		assert.Equal(t, "Readoku", book.Author)
+		assert.Equal(t, "en", book.Language)
+		require.Len(t, book.Chapters, 2)
+		assert.Contains(t, string(book.Chapters[0].Body), "The &lt;Lost&gt; Key")
+		assert.Contains(t, string(book.Chapters[1].Body), "The river rose.")
+	})
+
+	t.Run("fail: should require at least one section", func(t *testing.T) {
+		err := Write(io.Discard, &Publication{Title: "Empty"})
+
+		assert.Error(t, err)
+	})
+}
@@ -3,10 +3,12 @@ package handler
 import (
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
 	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
 	"github.com/shuheikomatsuki/readoku/backend/internal/service"
 )
@@ -16,6 +18,7 @@ type IAuthHandler interface {
 	Login(e echo.Context) error
 	GetUserStats(e echo.Context) error
 	GetGenerationStatus(e echo.Context) error
+	GetReadingHistory(e echo.Context) error
 }
 
 type AuthHandler struct {
@@ -39,6 +42,12 @@ type UserStatsResponse struct {
 	Last7DaysWordCount map[string]int `json:"last_7_days_word_count"`
 }
 
+type ReadingHistoryResponse struct {
+	Records    []*model.ReadingHistoryItem `json:"records"`
+	NextCursor *string                     `json:"next_cursor"`
+	PrevCursor *string                     `json:"prev_cursor"`
+}
+
 type SignUpRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=8"`
@@ -154,3 +163,31 @@ func (h *AuthHandler) GetGenerationStatus(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, status)
 }
+
+func (h *AuthHandler) GetReadingHistory(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	history, err := h.UserService.GetReadingHistory(userID, c.QueryParam("cursor"), limit)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCursor) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid cursor"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get reading history"})
+	}
+
+	res := ReadingHistoryResponse{
+		Records:    history.Records,
+		NextCursor: history.NextCursor,
+		PrevCursor: history.PrevCursor,
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
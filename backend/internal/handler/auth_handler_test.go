@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
 	"github.com/shuheikomatsuki/readoku/backend/internal/repository" // ErrEmailAlreadyExists の比較用
 	"github.com/shuheikomatsuki/readoku/backend/internal/service"    // service パッケージをインポート
 	"github.com/shuheikomatsuki/readoku/backend/internal/timeutil"
@@ -202,3 +203,61 @@ func TestAuthHandler_GetGenetationStatus(t *testing.T) {
 		mockUserService.AssertExpectations(t)
 	})
 }
+
+func TestAuthHandler_GetReadingHistory(t *testing.T) {
+	mockAuthSvc, mockUserSvc, e := setupAuthTestHandler(t)
+
+	h := NewAuthHandler(mockAuthSvc, mockUserSvc)
+
+	claims := &JwtCustomClaims{
+		testUserID,
+		jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(timeutil.NowTokyo().Add(time.Hour * 1))},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	t.Run("success: should return history with cursors", func(t *testing.T) {
+		next := "next-cursor"
+		storyID, title := testStoryID, "Read story"
+		page := &service.ReadingHistoryPage{
+			Records: []*model.ReadingHistoryItem{
+				{ID: 2, StoryID: &storyID, Title: &title, WordCount: 120},
+				{ID: 1, WordCount: 80},
+			},
+			NextCursor: &next,
+		}
+		mockUserSvc.On("GetReadingHistory", testUserID, "", 5).Return(page, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/users/me/history?limit=5", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.GetReadingHistory(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response ReadingHistoryResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.Len(t, response.Records, 2)
+		assert.Equal(t, "Read story", *response.Records[0].Title)
+		// 完全に削除したストーリーの記録は story_id と title が null になる
+		assert.Nil(t, response.Records[1].StoryID)
+		assert.Nil(t, response.Records[1].Title)
+		assert.Equal(t, next, *response.NextCursor)
+		assert.Nil(t, response.PrevCursor)
+
+		mockUserSvc.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 400 for an invalid cursor", func(t *testing.T) {
+		mockUserSvc.On("GetReadingHistory", testUserID, "broken", 20).Return(nil, service.ErrInvalidCursor).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/users/me/history?cursor=broken", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.GetReadingHistory(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockUserSvc.AssertExpectations(t)
+	})
+}
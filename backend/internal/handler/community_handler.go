@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
+	"github.com/shuheikomatsuki/readoku/backend/internal/service"
+)
+
+type ICommunityHandler interface {
+	PublishStory(e echo.Context) error
+	UnpublishStory(e echo.Context) error
+	GetCommunityStories(e echo.Context) error
+	GetCommunityStory(e echo.Context) error
+	LikeStory(e echo.Context) error
+	UnlikeStory(e echo.Context) error
+	CloneStory(e echo.Context) error
+}
+
+type CommunityHandler struct {
+	CommunityService service.ICommunityService
+}
+
+// PublishStoryRequest は公開ライブラリへの公開。level を省略した場合はストーリーのレベルを使う
+type PublishStoryRequest struct {
+	Level *string  `json:"level" validate:"omitempty,oneof=A1 A2 B1 B2 C1 C2"`
+	Tags  []string `json:"tags" validate:"max=10,dive,max=50"`
+}
+
+type GetCommunityStoriesResponse struct {
+	Stories     []*model.CommunityStory `json:"stories"`
+	TotalCount  int                     `json:"total_count"`
+	TotalPages  int                     `json:"total_pages"`
+	CurrentPage int                     `json:"current_page"`
+}
+
+type LikeResponse struct {
+	LikeCount int  `json:"like_count"`
+	LikedByMe bool `json:"liked_by_me"`
+}
+
+func NewCommunityHandler(communityService service.ICommunityService) ICommunityHandler {
+	return &CommunityHandler{
+		CommunityService: communityService,
+	}
+}
+
+func (h *CommunityHandler) PublishStory(c echo.Context) error {
+	storyID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid story id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	var req PublishStoryRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	story, err := h.CommunityService.PublishStory(storyID, userID, service.PublishInput{Level: req.Level, Tags: req.Tags})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrStoryNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "story not found"})
+		case errors.Is(err, service.ErrCommunityLevelRequired),
+			errors.Is(err, service.ErrInvalidCommunityLevel),
+			errors.Is(err, service.ErrTooManyCommunityTags),
+			errors.Is(err, service.ErrInvalidCommunityTagName):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to publish story"})
+	}
+
+	return c.JSON(http.StatusOK, story)
+}
+
+func (h *CommunityHandler) UnpublishStory(c echo.Context) error {
+	communityStoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid community story id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	if err := h.CommunityService.UnpublishStory(communityStoryID, userID); err != nil {
+		if errors.Is(err, service.ErrCommunityStoryNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "community story not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to unpublish story"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *CommunityHandler) GetCommunityStories(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	query, err := parseCommunityListQuery(c, userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	result, err := h.CommunityService.ListCommunityStories(userID, query)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "database error"})
+	}
+
+	return c.JSON(http.StatusOK, GetCommunityStoriesResponse{
+		Stories:     result.Stories,
+		TotalCount:  result.TotalCount,
+		TotalPages:  result.TotalPages,
+		CurrentPage: result.CurrentPage,
+	})
+}
+
+// parseCommunityListQuery は公開ライブラリの一覧のクエリパラメータを解釈する。
+// page / limit は不正な値の場合に既定値を使い、絞り込み・並び替えの不正な値はエラーにする
+func parseCommunityListQuery(c echo.Context, userID int) (service.CommunityListQuery, error) {
+	page, err := strconv.Atoi(c.QueryParam("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+
+	query := service.CommunityListQuery{Page: page, Limit: limit}
+
+	query.Query = strings.TrimSpace(c.QueryParam("q"))
+	if len(query.Query) > maxSearchQueryLength {
+		return query, errors.New("search query is too long")
+	}
+
+	query.Level = c.QueryParam("level")
+	if query.Level != "" && !slices.Contains(model.CEFRLevels, query.Level) {
+		return query, fmt.Errorf("level must be one of [%s]", strings.Join(model.CEFRLevels, " "))
+	}
+
+	query.Tag = strings.TrimSpace(c.QueryParam("tag"))
+
+	if mine := c.QueryParam("mine"); mine != "" {
+		publishedByMe, err := strconv.ParseBool(mine)
+		if err != nil {
+			return query, errors.New("mine must be a boolean")
+		}
+		if publishedByMe {
+			query.PublishedBy = &userID
+		}
+	}
+
+	query.Sort = repository.CommunitySort(c.QueryParam("sort"))
+	if !query.Sort.IsValid() {
+		return query, errors.New("sort must be one of [newest popular]")
+	}
+
+	return query, nil
+}
+
+func (h *CommunityHandler) GetCommunityStory(c echo.Context) error {
+	communityStoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid community story id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	story, err := h.CommunityService.GetCommunityStory(communityStoryID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrCommunityStoryNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "community story not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "database error"})
+	}
+
+	return c.JSON(http.StatusOK, story)
+}
+
+func (h *CommunityHandler) LikeStory(c echo.Context) error {
+	return h.updateLike(c, true)
+}
+
+func (h *CommunityHandler) UnlikeStory(c echo.Context) error {
+	return h.updateLike(c, false)
+}
+
+// updateLike は「いいね」を付ける・取り消す。どちらも何度呼んでも同じ結果になる
+func (h *CommunityHandler) updateLike(c echo.Context, like bool) error {
+	communityStoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid community story id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	update, failure := h.CommunityService.LikeStory, "failed to like story"
+	if !like {
+		update, failure = h.CommunityService.UnlikeStory, "failed to unlike story"
+	}
+	likeCount, err := update(communityStoryID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrCommunityStoryNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "community story not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": failure})
+	}
+
+	return c.JSON(http.StatusOK, LikeResponse{LikeCount: likeCount, LikedByMe: like})
+}
+
+func (h *CommunityHandler) CloneStory(c echo.Context) error {
+	communityStoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid community story id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	story, err := h.CommunityService.CloneStory(communityStoryID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrCommunityStoryNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "community story not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to clone story"})
+	}
+
+	return c.JSON(http.StatusCreated, story)
+}
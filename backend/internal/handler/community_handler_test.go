@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
+	"github.com/shuheikomatsuki/readoku/backend/internal/service"
+)
+
+func TestCommunityHandler_PublishStory(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockCommunityService := new(MockCommunityService)
+	h := NewCommunityHandler(mockCommunityService)
+
+	newContext := func(body string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/stories/:id/publish")
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.Itoa(testStoryID))
+		return c, rec
+	}
+
+	t.Run("success: should publish the story without exposing the publisher", func(t *testing.T) {
+		level := "B1"
+		published := &model.CommunityStory{ID: 3, UserID: testUserID, Title: "Rivers", Level: "B1", Tags: []string{"nature"}, PublishedByMe: true}
+		mockCommunityService.On("PublishStory", testStoryID, testUserID, service.PublishInput{Level: &level, Tags: []string{"nature"}}).
+			Return(published, nil).Once()
+
+		c, rec := newContext(`{"level": "B1", "tags": ["nature"]}`)
+
+		require.NoError(t, h.PublishStory(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, float64(3), response["id"])
+		assert.NotContains(t, response, "user_id")
+
+		mockCommunityService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 400 for an unknown level", func(t *testing.T) {
+		c, _ := newContext(`{"level": "Z9"}`)
+
+		err := h.PublishStory(c)
+
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	})
+
+	t.Run("fail: should return 400 when the story has no level", func(t *testing.T) {
+		mockCommunityService.On("PublishStory", testStoryID, testUserID, mock.Anything).Return(nil, service.ErrCommunityLevelRequired).Once()
+
+		c, rec := newContext(`{}`)
+
+		require.NoError(t, h.PublishStory(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		mockCommunityService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 404 for another user's story", func(t *testing.T) {
+		mockCommunityService.On("PublishStory", testStoryID, testUserID, mock.Anything).Return(nil, service.ErrStoryNotFound).Once()
+
+		c, rec := newContext(`{"level": "A1"}`)
+
+		require.NoError(t, h.PublishStory(c))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		mockCommunityService.AssertExpectations(t)
+	})
+}
+
+func TestCommunityHandler_GetCommunityStories(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockCommunityService := new(MockCommunityService)
+	h := NewCommunityHandler(mockCommunityService)
+
+	newContext := func(rawQuery string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodGet, "/?"+rawQuery, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		return c, rec
+	}
+
+	t.Run("success: should pass the search, filters and sort", func(t *testing.T) {
+		publishedBy := testUserID
+		query := service.CommunityListQuery{
+			CommunityFilter: repository.CommunityFilter{Query: "river", Level: "B1", Tag: "nature", PublishedBy: &publishedBy},
+			Sort:            repository.CommunitySortPopular,
+			Page:            2,
+			Limit:           20,
+		}
+		result := &service.PaginatedCommunityStories{
+			Stories:     []*model.CommunityStory{{ID: 3, Title: "Rivers", LikeCount: 4}},
+			TotalCount:  21,
+			TotalPages:  2,
+			CurrentPage: 2,
+		}
+		mockCommunityService.On("ListCommunityStories", testUserID, query).Return(result, nil).Once()
+
+		c, rec := newContext("q=river&level=B1&tag=nature&mine=true&sort=popular&page=2&limit=20")
+
+		require.NoError(t, h.GetCommunityStories(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response GetCommunityStoriesResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.Len(t, response.Stories, 1)
+		assert.Equal(t, 4, response.Stories[0].LikeCount)
+		assert.Equal(t, 21, response.TotalCount)
+
+		mockCommunityService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 400 for an unknown sort", func(t *testing.T) {
+		c, rec := newContext("sort=oldest")
+
+		require.NoError(t, h.GetCommunityStories(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestCommunityHandler_LikeStory(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockCommunityService := new(MockCommunityService)
+	h := NewCommunityHandler(mockCommunityService)
+
+	newContext := func(method, id string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(method, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/community/stories/:id/like")
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		return c, rec
+	}
+
+	t.Run("success: should like and return the count", func(t *testing.T) {
+		mockCommunityService.On("LikeStory", 3, testUserID).Return(5, nil).Once()
+
+		c, rec := newContext(http.MethodPut, "3")
+
+		require.NoError(t, h.LikeStory(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"like_count": 5, "liked_by_me": true}`, rec.Body.String())
+
+		mockCommunityService.AssertExpectations(t)
+	})
+
+	t.Run("success: should unlike and return the count", func(t *testing.T) {
+		mockCommunityService.On("UnlikeStory", 3, testUserID).Return(4, nil).Once()
+
+		c, rec := newContext(http.MethodDelete, "3")
+
+		require.NoError(t, h.UnlikeStory(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"like_count": 4, "liked_by_me": false}`, rec.Body.String())
+
+		mockCommunityService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 404 for an unknown story", func(t *testing.T) {
+		mockCommunityService.On("LikeStory", 9, testUserID).Return(0, service.ErrCommunityStoryNotFound).Once()
+
+		c, rec := newContext(http.MethodPut, "9")
+
+		require.NoError(t, h.LikeStory(c))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		mockCommunityService.AssertExpectations(t)
+	})
+}
+
+func TestCommunityHandler_CloneStory(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockCommunityService := new(MockCommunityService)
+	h := NewCommunityHandler(mockCommunityService)
+
+	newContext := func(id string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/community/stories/:id/clone")
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		return c, rec
+	}
+
+	t.Run("success: should return the cloned story", func(t *testing.T) {
+		cloned := &model.Story{ID: 20, UserID: testUserID, Title: "Rivers", Source: model.StorySourceCommunity}
+		mockCommunityService.On("CloneStory", 3, testUserID).Return(cloned, nil).Once()
+
+		c, rec := newContext("3")
+
+		require.NoError(t, h.CloneStory(c))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		var response model.Story
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, 20, response.ID)
+		assert.Equal(t, model.StorySourceCommunity, response.Source)
+
+		mockCommunityService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 404 for an unpublished story", func(t *testing.T) {
+		mockCommunityService.On("CloneStory", 4, testUserID).Return(nil, service.ErrCommunityStoryNotFound).Once()
+
+		c, rec := newContext("4")
+
+		require.NoError(t, h.CloneStory(c))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		mockCommunityService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 400 for an invalid id", func(t *testing.T) {
+		c, rec := newContext("abc")
+
+		require.NoError(t, h.CloneStory(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
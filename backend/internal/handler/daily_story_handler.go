@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/service"
+)
+
+type IDailyStoryHandler interface {
+	GetPreference(e echo.Context) error
+	UpdatePreference(e echo.Context) error
+}
+
+type DailyStoryHandler struct {
+	DailyStoryService service.IDailyStoryService
+}
+
+type DailyStoryPreferenceRequest struct {
+	Enabled      bool     `json:"enabled"`
+	Topics       []string `json:"topics" validate:"required,min=1,max=20,dive,max=200"`
+	Level        *string  `json:"level" validate:"omitempty,oneof=A1 A2 B1 B2 C1 C2"`
+	WordCount    *int     `json:"word_count" validate:"omitempty,min=50,max=2000"`
+	DeliveryHour int      `json:"delivery_hour" validate:"min=0,max=23"`
+	TimeZone     string   `json:"time_zone" validate:"required"`
+}
+
+func NewDailyStoryHandler(dailyStoryService service.IDailyStoryService) IDailyStoryHandler {
+	return &DailyStoryHandler{
+		DailyStoryService: dailyStoryService,
+	}
+}
+
+func (h *DailyStoryHandler) GetPreference(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	preference, err := h.DailyStoryService.GetPreference(userID)
+	if err != nil {
+		if errors.Is(err, service.ErrDailyStoryPreferenceNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "daily story preference not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "database error"})
+	}
+
+	return c.JSON(http.StatusOK, preference)
+}
+
+func (h *DailyStoryHandler) UpdatePreference(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	var req DailyStoryPreferenceRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	preference, err := h.DailyStoryService.UpdatePreference(userID, service.DailyStoryPreferenceInput{
+		Enabled:      req.Enabled,
+		Topics:       req.Topics,
+		Level:        req.Level,
+		WordCount:    req.WordCount,
+		DeliveryHour: req.DeliveryHour,
+		TimeZone:     req.TimeZone,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTimeZone) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid time zone"})
+		}
+		if errors.Is(err, service.ErrNoDailyStoryTopics) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "at least one topic is required"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to save daily story preference"})
+	}
+
+	return c.JSON(http.StatusOK, preference)
+}
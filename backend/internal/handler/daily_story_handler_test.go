@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/service"
+)
+
+func TestDailyStoryHandler_UpdatePreference(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockDailyStoryService := new(MockDailyStoryService)
+	h := NewDailyStoryHandler(mockDailyStoryService)
+
+	t.Run("success: should save the preference", func(t *testing.T) {
+		input := service.DailyStoryPreferenceInput{
+			Enabled:      true,
+			Topics:       []string{"science", "travel"},
+			DeliveryHour: 6,
+			TimeZone:     "Asia/Tokyo",
+		}
+		saved := &model.DailyStoryPreference{UserID: testUserID, Enabled: true, Topics: pq.StringArray{"science", "travel"}, DeliveryHour: 6, TimeZone: "Asia/Tokyo"}
+		mockDailyStoryService.On("UpdatePreference", testUserID, input).Return(saved, nil).Once()
+
+		requestBody := `{"enabled": true, "topics": ["science", "travel"], "delivery_hour": 6, "time_zone": "Asia/Tokyo"}`
+		req := httptest.NewRequest(http.MethodPut, "/users/me/daily-story", strings.NewReader(requestBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.UpdatePreference(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response model.DailyStoryPreference
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, 6, response.DeliveryHour)
+
+		mockDailyStoryService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should reject an out-of-range delivery hour", func(t *testing.T) {
+		requestBody := `{"topics": ["science"], "delivery_hour": 24, "time_zone": "Asia/Tokyo"}`
+		req := httptest.NewRequest(http.MethodPut, "/users/me/daily-story", strings.NewReader(requestBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		err := h.UpdatePreference(c)
+		require.Error(t, err)
+		httpErr, ok := err.(*echo.HTTPError)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	})
+
+	t.Run("fail: should return 400 for an invalid time zone", func(t *testing.T) {
+		input := service.DailyStoryPreferenceInput{Topics: []string{"science"}, DeliveryHour: 7, TimeZone: "Nowhere/City"}
+		mockDailyStoryService.On("UpdatePreference", testUserID, input).Return(nil, service.ErrInvalidTimeZone).Once()
+
+		requestBody := `{"topics": ["science"], "delivery_hour": 7, "time_zone": "Nowhere/City"}`
+		req := httptest.NewRequest(http.MethodPut, "/users/me/daily-story", strings.NewReader(requestBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.UpdatePreference(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockDailyStoryService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/epub"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
+	"github.com/shuheikomatsuki/readoku/backend/internal/service"
+	"github.com/shuheikomatsuki/readoku/backend/internal/timeutil"
+)
+
+type IExportHandler interface {
+	ExportEPUB(c echo.Context) error
+	ExportArchive(c echo.Context) error
+}
+
+type ExportHandler struct {
+	ExportService service.IExportService
+}
+
+func NewExportHandler(exportService service.IExportService) IExportHandler {
+	return &ExportHandler{
+		ExportService: exportService,
+	}
+}
+
+// ExportEPUB は ids (カンマ区切り)・tag_id・shelf_id のいずれかで選んだストーリーを EPUB 3 としてダウンロードさせる
+func (h *ExportHandler) ExportEPUB(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	selection, err := parseStorySelection(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	export, err := h.ExportService.ExportEPUB(userID, selection, c.QueryParam("title"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidExportSelection):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case errors.Is(err, service.ErrExportTooLarge):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("at most %d stories can be exported at once", service.MaxEPUBExportStories)})
+		case errors.Is(err, service.ErrStoryNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "story not found"})
+		case errors.Is(err, service.ErrTagNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "tag not found"})
+		case errors.Is(err, service.ErrShelfNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "shelf not found"})
+		case errors.Is(err, service.ErrNothingToExport):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "no stories to export"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to export stories"})
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "application/epub+zip")
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", export.FileName))
+	c.Response().WriteHeader(http.StatusOK)
+	// 書き出しを始めた後はステータスを変えられないため、失敗はエラーとして返してログに残すだけにする
+	return epub.Write(c.Response(), export.Publication)
+}
+
+// ExportArchive はライブラリ全体を Markdown と manifest.json の zip としてダウンロードさせる。
+// 本文は読み込みながら送るため、応答の途中で失敗した場合はステータスを変えられず、壊れた zip になる。
+// Lambda では応答の大きさに上限があるため、上限を超えるライブラリは何も送らずに 413 を返す
+func (h *ExportHandler) ExportArchive(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	fileName := fmt.Sprintf("readoku-library-%s.zip", timeutil.NowTokyo().Format("20060102"))
+	c.Response().Header().Set(echo.HeaderContentType, "application/zip")
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fileName))
+
+	if err := h.ExportService.ExportArchive(userID, c.Response()); err != nil {
+		if c.Response().Committed {
+			return err
+		}
+		// c.JSON は設定済みの Content-Type を上書きしないため、zip として送らないよう先に消す
+		c.Response().Header().Del(echo.HeaderContentType)
+		c.Response().Header().Del(echo.HeaderContentDisposition)
+		if errors.Is(err, service.ErrArchiveTooLarge) {
+			return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{
+				"error": fmt.Sprintf("the library is larger than %d MB and cannot be downloaded as one archive; export it as EPUB by tag or shelf instead", service.LambdaMaxArchiveBytes/(1024*1024)),
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to export library"})
+	}
+	return nil
+}
+
+// parseStorySelection は ids・tag_id・shelf_id のクエリパラメータを解釈する。ids はカンマ区切りか、複数回の指定
+func parseStorySelection(c echo.Context) (repository.StorySelection, error) {
+	var selection repository.StorySelection
+	for _, value := range c.QueryParams()["ids"] {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part == "" {
+				continue
+			}
+			id, err := strconv.Atoi(part)
+			if err != nil || id <= 0 {
+				return selection, errors.New("ids must be a comma-separated list of story ids")
+			}
+			selection.StoryIDs = append(selection.StoryIDs, id)
+		}
+	}
+
+	var err error
+	if selection.TagID, err = parseOptionalInt(c, "tag_id"); err != nil {
+		return selection, err
+	}
+	if selection.ShelfID, err = parseOptionalInt(c, "shelf_id"); err != nil {
+		return selection, err
+	}
+	return selection, nil
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/epub"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
+	"github.com/shuheikomatsuki/readoku/backend/internal/service"
+)
+
+func TestExportHandler_ExportEPUB(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockExportService := new(MockExportService)
+	h := NewExportHandler(mockExportService)
+
+	t.Run("success: should return an epub attachment", func(t *testing.T) {
+		selection := repository.StorySelection{StoryIDs: []int{3, 1, 2}}
+		export := &service.EPUBExport{
+			FileName: "week-1.epub",
+			Publication: &epub.Publication{
+				Identifier: "urn:uuid:00000000-0000-5000-8000-000000000000",
+				Title:      "Week 1",
+				Sections:   []epub.Section{{Title: "The Lost Key", Body: "<p>Tom looked everywhere.</p>"}},
+			},
+		}
+		mockExportService.On("ExportEPUB", testUserID, selection, "Week 1").Return(export, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/stories/export.epub?ids=3,1&ids=2&title=Week+1", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.ExportEPUB(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/epub+zip", rec.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="week-1.epub"`, rec.Header().Get("Content-Disposition"))
+
+		book, err := epub.Read(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+		require.NoError(t, err)
+		assert.Equal(t, "Week 1", book.Title)
+
+		mockExportService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should reject invalid ids", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/stories/export.epub?ids=1,abc", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.ExportEPUB(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("fail: should return 404 for another user's tag", func(t *testing.T) {
+		tagID := 7
+		mockExportService.On("ExportEPUB", testUserID, repository.StorySelection{TagID: &tagID}, "").Return(nil, service.ErrTagNotFound).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/stories/export.epub?tag_id=7", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.ExportEPUB(c))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		mockExportService.AssertExpectations(t)
+	})
+}
+
+func TestExportHandler_ExportArchive(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockExportService := new(MockExportService)
+	h := NewExportHandler(mockExportService)
+
+	t.Run("success: should stream the archive as an attachment", func(t *testing.T) {
+		write := func(w io.Writer) error {
+			_, err := io.WriteString(w, "PK archive")
+			return err
+		}
+		mockExportService.On("ExportArchive", testUserID, mock.Anything).Return(write, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/stories/export.zip", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.ExportArchive(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/zip", rec.Header().Get("Content-Type"))
+		assert.Regexp(t, `^attachment; filename="readoku-library-\d{8}\.zip"$`, rec.Header().Get("Content-Disposition"))
+		assert.Equal(t, "PK archive", rec.Body.String())
+
+		mockExportService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 413 when the archive is over the size limit", func(t *testing.T) {
+		mockExportService.On("ExportArchive", testUserID, mock.Anything).Return(nil, service.ErrArchiveTooLarge).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/stories/export.zip", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.ExportArchive(c))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.Contains(t, rec.Body.String(), "4 MB")
+
+		mockExportService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 500 when nothing has been sent yet", func(t *testing.T) {
+		mockExportService.On("ExportArchive", testUserID, mock.Anything).Return(nil, errors.New("db error")).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/stories/export.zip", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.ExportArchive(c))
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.Empty(t, rec.Header().Get("Content-Disposition"))
+		assert.JSONEq(t, `{"error":"failed to export library"}`, rec.Body.String())
+
+		mockExportService.AssertExpectations(t)
+	})
+}
@@ -1,7 +1,12 @@
 package handler
 
 import (
+	"context"
+	"io"
+	"time"
+
 	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
 	"github.com/shuheikomatsuki/readoku/backend/internal/service"
 	"github.com/stretchr/testify/mock"
 )
@@ -48,20 +53,28 @@ func (m *MockUserService) GetGenerationStatus(userID int) (*service.GenerationSt
 	return args.Get(0).(*service.GenerationStatus), args.Error(1)
 }
 
+func (m *MockUserService) GetReadingHistory(userID int, cursor string, limit int) (*service.ReadingHistoryPage, error) {
+	args := m.Called(userID, cursor, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.ReadingHistoryPage), args.Error(1)
+}
+
 type MockStoryService struct {
 	mock.Mock
 }
 
-func (m *MockStoryService) GenerateStory(userID int, prompt string) (*model.Story, error) {
-	args := m.Called(userID, prompt)
+func (m *MockStoryService) GenerateStory(userID int, input service.GenerateStoryInput) (*model.Story, error) {
+	args := m.Called(userID, input)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*model.Story), args.Error(1)
 }
 
-func (m *MockStoryService) GetStories(userID int, page, limit int) (*service.PaginatedStories, error) {
-	args := m.Called(userID, page, limit)
+func (m *MockStoryService) GetStories(userID int, query service.StoryListQuery) (*service.PaginatedStories, error) {
+	args := m.Called(userID, query)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -94,11 +107,150 @@ func (m *MockStoryService) MarkStoryAsRead(storyID, userID int) error {
 	return args.Error(0)
 }
 
+func (m *MockStoryService) GetTrash(userID int) ([]*model.Story, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Story), args.Error(1)
+}
+
+func (m *MockStoryService) RestoreStory(storyID, userID int) (*model.Story, error) {
+	args := m.Called(storyID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Story), args.Error(1)
+}
+
+func (m *MockStoryService) PurgeTrash(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStoryService) BulkUpdateStories(userID int, op repository.BulkOperation) ([]*model.BulkStoryResult, error) {
+	args := m.Called(userID, op)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.BulkStoryResult), args.Error(1)
+}
+
+func (m *MockStoryService) UpdateStoryContent(storyID, userID int, title *string, content string) (*model.Story, error) {
+	args := m.Called(storyID, userID, title, content)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Story), args.Error(1)
+}
+
+func (m *MockStoryService) GetRevisions(storyID, userID int) ([]*model.StoryRevision, error) {
+	args := m.Called(storyID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.StoryRevision), args.Error(1)
+}
+
+func (m *MockStoryService) GetRevisionDiff(storyID, userID, revisionNumber int) (*service.RevisionDiff, error) {
+	args := m.Called(storyID, userID, revisionNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.RevisionDiff), args.Error(1)
+}
+
+func (m *MockStoryService) RevertStory(storyID, userID, revisionNumber int) (*model.Story, error) {
+	args := m.Called(storyID, userID, revisionNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Story), args.Error(1)
+}
+
+func (m *MockStoryService) SetFavorite(storyID, userID int, favorite bool) error {
+	args := m.Called(storyID, userID, favorite)
+	return args.Error(0)
+}
+
 func (m *MockStoryService) UndoLastRead(storyID, userID int) error {
 	args := m.Called(storyID, userID)
 	return args.Error(0)
 }
 
+func (m *MockStoryService) ContinueStory(storyID, userID int) (*model.Story, error) {
+	args := m.Called(storyID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Story), args.Error(1)
+}
+
+func (m *MockStoryService) GetSeries(seriesID, userID int) (*service.SeriesDetail, error) {
+	args := m.Called(seriesID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.SeriesDetail), args.Error(1)
+}
+
+func (m *MockStoryService) RewriteStory(storyID, userID int, level string) (*model.Story, error) {
+	args := m.Called(storyID, userID, level)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Story), args.Error(1)
+}
+
+func (m *MockStoryService) TranslateStory(storyID, userID int) ([]service.ParallelParagraph, error) {
+	args := m.Called(storyID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]service.ParallelParagraph), args.Error(1)
+}
+
+func (m *MockStoryService) GetDuplicateClusters(userID int) ([]*model.DuplicateCluster, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.DuplicateCluster), args.Error(1)
+}
+
+func (m *MockStoryService) BackfillSimhashes() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+type MockQuizService struct {
+	mock.Mock
+}
+
+func (m *MockQuizService) GenerateQuiz(storyID, userID int) ([]*model.QuizQuestion, error) {
+	args := m.Called(storyID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.QuizQuestion), args.Error(1)
+}
+
+func (m *MockQuizService) GetQuiz(storyID, userID int) ([]*model.QuizQuestion, error) {
+	args := m.Called(storyID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.QuizQuestion), args.Error(1)
+}
+
+func (m *MockQuizService) SubmitAnswers(storyID, userID int, answers []int) (*service.QuizResult, error) {
+	args := m.Called(storyID, userID, answers)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.QuizResult), args.Error(1)
+}
+
 var testUser = &model.User{
 	ID:           1,
 	Email:        "test@example.com",
@@ -112,3 +264,317 @@ var testStory = &model.Story{
 	Content:   "This is a test story content.",
 	WordCount: 6,
 }
+
+type MockTagService struct {
+	mock.Mock
+}
+
+func (m *MockTagService) ListTags(userID int) ([]*model.Tag, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Tag), args.Error(1)
+}
+
+func (m *MockTagService) CreateTag(userID int, name string) (*model.Tag, error) {
+	args := m.Called(userID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Tag), args.Error(1)
+}
+
+func (m *MockTagService) RenameTag(tagID, userID int, name string) (*model.Tag, error) {
+	args := m.Called(tagID, userID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Tag), args.Error(1)
+}
+
+func (m *MockTagService) DeleteTag(tagID, userID int) error {
+	args := m.Called(tagID, userID)
+	return args.Error(0)
+}
+
+func (m *MockTagService) AttachTag(storyID, tagID, userID int) error {
+	args := m.Called(storyID, tagID, userID)
+	return args.Error(0)
+}
+
+func (m *MockTagService) DetachTag(storyID, tagID, userID int) error {
+	args := m.Called(storyID, tagID, userID)
+	return args.Error(0)
+}
+
+type MockShelfService struct {
+	mock.Mock
+}
+
+func (m *MockShelfService) ListShelves(userID int) ([]*model.Shelf, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Shelf), args.Error(1)
+}
+
+func (m *MockShelfService) CreateShelf(userID int, name string) (*model.Shelf, error) {
+	args := m.Called(userID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Shelf), args.Error(1)
+}
+
+func (m *MockShelfService) GetShelf(shelfID, userID int) (*service.ShelfDetail, error) {
+	args := m.Called(shelfID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.ShelfDetail), args.Error(1)
+}
+
+func (m *MockShelfService) RenameShelf(shelfID, userID int, name string) (*model.Shelf, error) {
+	args := m.Called(shelfID, userID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Shelf), args.Error(1)
+}
+
+func (m *MockShelfService) DeleteShelf(shelfID, userID int) error {
+	args := m.Called(shelfID, userID)
+	return args.Error(0)
+}
+
+func (m *MockShelfService) AddStory(shelfID, storyID, userID int) (*service.ShelfDetail, error) {
+	args := m.Called(shelfID, storyID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.ShelfDetail), args.Error(1)
+}
+
+func (m *MockShelfService) RemoveStory(shelfID, storyID, userID int) error {
+	args := m.Called(shelfID, storyID, userID)
+	return args.Error(0)
+}
+
+func (m *MockShelfService) ReorderStories(shelfID, userID int, storyIDs []int) (*service.ShelfDetail, error) {
+	args := m.Called(shelfID, userID, storyIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.ShelfDetail), args.Error(1)
+}
+
+type MockTemplateService struct {
+	mock.Mock
+}
+
+func (m *MockTemplateService) ListTemplates(userID int) ([]*model.PromptTemplate, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.PromptTemplate), args.Error(1)
+}
+
+func (m *MockTemplateService) CreateTemplate(userID int, input service.TemplateInput) (*model.PromptTemplate, error) {
+	args := m.Called(userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PromptTemplate), args.Error(1)
+}
+
+func (m *MockTemplateService) UpdateTemplate(templateID, userID int, input service.TemplateInput) (*model.PromptTemplate, error) {
+	args := m.Called(templateID, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PromptTemplate), args.Error(1)
+}
+
+func (m *MockTemplateService) DeleteTemplate(templateID, userID int) error {
+	args := m.Called(templateID, userID)
+	return args.Error(0)
+}
+
+func (m *MockTemplateService) RenderTemplate(templateID, userID int, variables map[string]string) (*service.RenderedTemplate, error) {
+	args := m.Called(templateID, userID, variables)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.RenderedTemplate), args.Error(1)
+}
+
+type MockDailyStoryService struct {
+	mock.Mock
+}
+
+func (m *MockDailyStoryService) GetPreference(userID int) (*model.DailyStoryPreference, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.DailyStoryPreference), args.Error(1)
+}
+
+func (m *MockDailyStoryService) UpdatePreference(userID int, input service.DailyStoryPreferenceInput) (*model.DailyStoryPreference, error) {
+	args := m.Called(userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.DailyStoryPreference), args.Error(1)
+}
+
+func (m *MockDailyStoryService) DeliverDueStories(ctx context.Context, now time.Time) (*service.DeliveryReport, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.DeliveryReport), args.Error(1)
+}
+
+type MockImportService struct {
+	mock.Mock
+}
+
+func (m *MockImportService) ImportStories(userID int, inputs []service.ImportInput) ([]*model.Story, error) {
+	args := m.Called(userID, inputs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Story), args.Error(1)
+}
+
+func (m *MockImportService) ImportEPUB(userID int, fileName string, r io.ReaderAt, size int64) (*service.EPUBImport, error) {
+	args := m.Called(userID, fileName, r, size)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.EPUBImport), args.Error(1)
+}
+
+func (m *MockImportService) ImportURL(userID int, rawURL string) (*model.Story, error) {
+	args := m.Called(userID, rawURL)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Story), args.Error(1)
+}
+
+func (m *MockImportService) ImportArchive(userID int, r io.ReaderAt, size int64) (*model.ArchiveImport, error) {
+	args := m.Called(userID, r, size)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ArchiveImport), args.Error(1)
+}
+
+type MockExportService struct {
+	mock.Mock
+}
+
+func (m *MockExportService) ExportEPUB(userID int, selection repository.StorySelection, title string) (*service.EPUBExport, error) {
+	args := m.Called(userID, selection, title)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.EPUBExport), args.Error(1)
+}
+
+func (m *MockExportService) ExportArchive(userID int, w io.Writer) error {
+	args := m.Called(userID, w)
+	if write, ok := args.Get(0).(func(io.Writer) error); ok {
+		return write(w)
+	}
+	return args.Error(1)
+}
+
+type MockShareService struct {
+	mock.Mock
+}
+
+func (m *MockShareService) CreateShare(storyID, userID int, expiresAt *time.Time) (*model.StoryShare, error) {
+	args := m.Called(storyID, userID, expiresAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.StoryShare), args.Error(1)
+}
+
+func (m *MockShareService) ListShares(storyID, userID int) ([]*model.StoryShare, error) {
+	args := m.Called(storyID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.StoryShare), args.Error(1)
+}
+
+func (m *MockShareService) RevokeShare(shareID, storyID, userID int) error {
+	args := m.Called(shareID, storyID, userID)
+	return args.Error(0)
+}
+
+func (m *MockShareService) GetSharedStory(token string) (*model.SharedStory, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SharedStory), args.Error(1)
+}
+
+type MockCommunityService struct {
+	mock.Mock
+}
+
+func (m *MockCommunityService) PublishStory(storyID, userID int, input service.PublishInput) (*model.CommunityStory, error) {
+	args := m.Called(storyID, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CommunityStory), args.Error(1)
+}
+
+func (m *MockCommunityService) UnpublishStory(communityStoryID, userID int) error {
+	args := m.Called(communityStoryID, userID)
+	return args.Error(0)
+}
+
+func (m *MockCommunityService) ListCommunityStories(userID int, query service.CommunityListQuery) (*service.PaginatedCommunityStories, error) {
+	args := m.Called(userID, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.PaginatedCommunityStories), args.Error(1)
+}
+
+func (m *MockCommunityService) GetCommunityStory(communityStoryID, userID int) (*model.CommunityStory, error) {
+	args := m.Called(communityStoryID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CommunityStory), args.Error(1)
+}
+
+func (m *MockCommunityService) LikeStory(communityStoryID, userID int) (int, error) {
+	args := m.Called(communityStoryID, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCommunityService) UnlikeStory(communityStoryID, userID int) (int, error) {
+	args := m.Called(communityStoryID, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCommunityService) CloneStory(communityStoryID, userID int) (*model.Story, error) {
+	args := m.Called(communityStoryID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Story), args.Error(1)
+}
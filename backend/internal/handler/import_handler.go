@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/service"
+)
+
+type IImportHandler interface {
+	ImportStories(e echo.Context) error
+	ImportEPUB(e echo.Context) error
+	ImportURL(e echo.Context) error
+	ImportArchive(e echo.Context) error
+}
+
+type ImportHandler struct {
+	ImportService service.IImportService
+}
+
+// ImportTextRequest は貼り付けた本文の取り込み。title を省略した場合は本文の見出しか 1 行目を使う
+type ImportTextRequest struct {
+	Title   string `json:"title" validate:"max=100"`
+	Content string `json:"content" validate:"required"`
+}
+
+type ImportURLRequest struct {
+	URL string `json:"url" validate:"required,url,max=2048"`
+}
+
+type ImportStoriesResponse struct {
+	Stories []*model.Story `json:"stories"`
+}
+
+type EPUBImportResponse struct {
+	Series  *model.Series  `json:"series"`
+	Stories []*model.Story `json:"stories"`
+}
+
+// maxImportFiles は 1 回のリクエストで取り込めるファイル数の上限
+const maxImportFiles = 20
+
+// importFileExtensions は取り込めるファイルの拡張子 (プレーンテキストと Markdown)
+var importFileExtensions = []string{".txt", ".text", ".md", ".markdown"}
+
+func NewImportHandler(importService service.IImportService) IImportHandler {
+	return &ImportHandler{
+		ImportService: importService,
+	}
+}
+
+// ImportStories は multipart の file (複数可) か、JSON の content からストーリーを取り込む
+func (h *ImportHandler) ImportStories(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	var inputs []service.ImportInput
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		inputs, err = readImportFiles(c)
+		if err != nil {
+			return err
+		}
+	} else {
+		var req ImportTextRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		}
+		if err := c.Validate(&req); err != nil {
+			return err
+		}
+		inputs = []service.ImportInput{{Title: req.Title, Content: req.Content}}
+	}
+
+	stories, err := h.ImportService.ImportStories(userID, inputs)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrEmptyImport):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "imported text is empty"})
+		case errors.Is(err, service.ErrUnsupportedImport):
+			return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "only UTF-8 plain text or Markdown can be imported"})
+		case errors.Is(err, service.ErrImportTooLarge):
+			return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": "imported text is too large"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to import stories"})
+	}
+
+	return c.JSON(http.StatusCreated, ImportStoriesResponse{Stories: stories})
+}
+
+// ImportEPUB は multipart の file (.epub) を章ごとのストーリーに分け、連載として取り込む
+func (h *ImportHandler) ImportEPUB(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "file is required"})
+	}
+	if strings.ToLower(path.Ext(file.Filename)) != ".epub" {
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "only .epub files can be imported"})
+	}
+	if file.Size > service.MaxEPUBBytes {
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": "epub is too large"})
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "failed to read file"})
+	}
+	defer f.Close()
+
+	result, err := h.ImportService.ImportEPUB(userID, file.Filename, f, file.Size)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidEPUB):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid epub file"})
+		case errors.Is(err, service.ErrEmptyImport):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "epub has no text to import"})
+		case errors.Is(err, service.ErrImportTooLarge):
+			return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": "epub is too large"})
+		case errors.Is(err, service.ErrUnsupportedImport):
+			return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "epub text must be UTF-8"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to import epub"})
+	}
+
+	return c.JSON(http.StatusCreated, EPUBImportResponse{Series: result.Series, Stories: result.Stories})
+}
+
+// ImportURL は Web ページの記事を取り込む。取得元の URL は source_url として保存する
+func (h *ImportHandler) ImportURL(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	var req ImportURLRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	story, err := h.ImportService.ImportURL(userID, req.URL)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidImportURL):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "url must be a public http or https address"})
+		case errors.Is(err, service.ErrEmptyImport):
+			return c.JSON(http.StatusUnprocessableEntity, map[string]string{"error": "no readable text found on the page"})
+		case errors.Is(err, service.ErrUnsupportedImport):
+			return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "only html pages can be imported"})
+		case errors.Is(err, service.ErrImportTooLarge):
+			return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": "page is too large"})
+		case errors.Is(err, service.ErrImportFetchFailed):
+			return c.JSON(http.StatusBadGateway, map[string]string{"error": "failed to fetch the page"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to import page"})
+	}
+
+	return c.JSON(http.StatusCreated, story)
+}
+
+// ImportArchive は multipart の file (GET /stories/export.zip で書き出した .zip) からライブラリを復元する。
+// 本文が同じストーリーは作成せず、タイトル・レベルが異なるものは conflict として結果に含める
+func (h *ImportHandler) ImportArchive(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "file is required"})
+	}
+	if strings.ToLower(path.Ext(file.Filename)) != ".zip" {
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "only .zip archives can be imported"})
+	}
+	if file.Size > service.MaxArchiveBytes {
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": "archive is too large"})
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "failed to read file"})
+	}
+	defer f.Close()
+
+	result, err := h.ImportService.ImportArchive(userID, f, file.Size)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidArchive):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case errors.Is(err, service.ErrImportTooLarge):
+			return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": "archive is too large"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to import archive"})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// readImportFiles は multipart の file をすべて読み込む。title はファイルが 1 つの場合のみ使う
+func readImportFiles(c echo.Context) ([]service.ImportInput, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, ValidationErrorResponse{Error: "invalid multipart form"})
+	}
+	files := form.File["file"]
+	if len(files) == 0 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, ValidationErrorResponse{Error: "file is required"})
+	}
+	if len(files) > maxImportFiles {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, ValidationErrorResponse{Error: "too many files"})
+	}
+
+	inputs := make([]service.ImportInput, 0, len(files))
+	for _, file := range files {
+		if !slices.Contains(importFileExtensions, strings.ToLower(path.Ext(file.Filename))) {
+			return nil, echo.NewHTTPError(http.StatusUnsupportedMediaType, ValidationErrorResponse{Error: "only .txt and .md files can be imported"})
+		}
+		if file.Size > service.MaxImportContentBytes {
+			return nil, echo.NewHTTPError(http.StatusRequestEntityTooLarge, ValidationErrorResponse{Error: "imported text is too large"})
+		}
+
+		f, err := file.Open()
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, ValidationErrorResponse{Error: "failed to read file"})
+		}
+		content, err := io.ReadAll(io.LimitReader(f, service.MaxImportContentBytes+1))
+		f.Close()
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, ValidationErrorResponse{Error: "failed to read file"})
+		}
+		inputs = append(inputs, service.ImportInput{FileName: file.Filename, Content: string(content)})
+	}
+
+	if titles := form.Value["title"]; len(inputs) == 1 && len(titles) > 0 {
+		inputs[0].Title = titles[0]
+	}
+	return inputs, nil
+}
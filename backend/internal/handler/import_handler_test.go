@@ -0,0 +1,299 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/service"
+)
+
+func newMultipartImportRequest(t *testing.T, files map[string]string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, content := range files {
+		part, err := writer.CreateFormFile("file", name)
+		require.NoError(t, err)
+		_, err = part.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/stories/import", &body)
+	req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+	return req
+}
+
+func TestImportHandler_ImportStories(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockImportService := new(MockImportService)
+	h := NewImportHandler(mockImportService)
+
+	t.Run("success: should import an uploaded Markdown file", func(t *testing.T) {
+		inputs := []service.ImportInput{{FileName: "chapter1.md", Content: "# Chapter 1\n\nIt was a cold morning."}}
+		imported := &model.Story{ID: 20, UserID: testUserID, Title: "Chapter 1", Content: "It was a cold morning.", WordCount: 5, Source: model.StorySourceImported}
+		mockImportService.On("ImportStories", testUserID, inputs).Return([]*model.Story{imported}, nil).Once()
+
+		req := newMultipartImportRequest(t, map[string]string{"chapter1.md": inputs[0].Content})
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.ImportStories(c))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		var response ImportStoriesResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.Len(t, response.Stories, 1)
+		assert.Equal(t, model.StorySourceImported, response.Stories[0].Source)
+
+		mockImportService.AssertExpectations(t)
+	})
+
+	t.Run("success: should import a pasted body", func(t *testing.T) {
+		inputs := []service.ImportInput{{Title: "News", Content: "Prices rose again."}}
+		mockImportService.On("ImportStories", testUserID, inputs).Return([]*model.Story{{ID: 21, Title: "News"}}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/stories/import", strings.NewReader(`{"title": "News", "content": "Prices rose again."}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.ImportStories(c))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		mockImportService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should reject unsupported file types", func(t *testing.T) {
+		req := newMultipartImportRequest(t, map[string]string{"book.pdf": "%PDF-1.7"})
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		err := h.ImportStories(c)
+
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusUnsupportedMediaType, httpErr.Code)
+	})
+
+	t.Run("fail: should return 400 for an empty text", func(t *testing.T) {
+		inputs := []service.ImportInput{{Content: "# Title only"}}
+		mockImportService.On("ImportStories", testUserID, inputs).Return(nil, service.ErrEmptyImport).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/stories/import", strings.NewReader(`{"content": "# Title only"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.ImportStories(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		mockImportService.AssertExpectations(t)
+	})
+}
+
+func TestImportHandler_ImportEPUB(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockImportService := new(MockImportService)
+	h := NewImportHandler(mockImportService)
+
+	t.Run("success: should import an epub as a series", func(t *testing.T) {
+		result := &service.EPUBImport{
+			Series:  &model.Series{ID: 3, UserID: testUserID, Title: "My Book"},
+			Stories: []*model.Story{{ID: 30, Title: "Chapter One", Source: model.StorySourceImported}},
+		}
+		mockImportService.On("ImportEPUB", testUserID, "book.epub", mock.Anything, int64(len("epub data"))).Return(result, nil).Once()
+
+		req := newMultipartImportRequest(t, map[string]string{"book.epub": "epub data"})
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.ImportEPUB(c))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		var response EPUBImportResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "My Book", response.Series.Title)
+		require.Len(t, response.Stories, 1)
+
+		mockImportService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should reject files that are not epub", func(t *testing.T) {
+		req := newMultipartImportRequest(t, map[string]string{"book.txt": "text"})
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.ImportEPUB(c))
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	})
+
+	t.Run("fail: should return 400 for a broken epub", func(t *testing.T) {
+		mockImportService.On("ImportEPUB", testUserID, "broken.epub", mock.Anything, mock.Anything).Return(nil, service.ErrInvalidEPUB).Once()
+
+		req := newMultipartImportRequest(t, map[string]string{"broken.epub": "not a zip"})
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.ImportEPUB(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		mockImportService.AssertExpectations(t)
+	})
+}
+
+func TestImportHandler_ImportArchive(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockImportService := new(MockImportService)
+	h := NewImportHandler(mockImportService)
+
+	newRequest := func(files map[string]string) (echo.Context, *httptest.ResponseRecorder) {
+		req := newMultipartImportRequest(t, files)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		return c, rec
+	}
+
+	t.Run("success: should return the result of each story", func(t *testing.T) {
+		result := &model.ArchiveImport{
+			Created:     1,
+			Conflicts:   1,
+			CreatedTags: []string{"news"},
+			Stories: []*model.ArchiveImportResult{
+				{File: "stories/00007-rivers.md", Title: "Rivers", Status: model.ArchiveImportCreated, StoryID: 101, AddedTags: 1},
+				{File: "stories/00008-key.md", Title: "Key", Status: model.ArchiveImportConflict, StoryID: 55, Conflicts: []string{"title"}},
+			},
+		}
+		mockImportService.On("ImportArchive", testUserID, mock.Anything, int64(len("zip data"))).Return(result, nil).Once()
+
+		c, rec := newRequest(map[string]string{"readoku-library-20240601.zip": "zip data"})
+
+		require.NoError(t, h.ImportArchive(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response model.ArchiveImport
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, 1, response.Created)
+		assert.Equal(t, 1, response.Conflicts)
+		require.Len(t, response.Stories, 2)
+		assert.Equal(t, []string{"title"}, response.Stories[1].Conflicts)
+
+		mockImportService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should reject files that are not zip", func(t *testing.T) {
+		c, rec := newRequest(map[string]string{"library.epub": "data"})
+
+		require.NoError(t, h.ImportArchive(c))
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	})
+
+	t.Run("fail: should return 400 with the reason for an invalid archive", func(t *testing.T) {
+		err := fmt.Errorf("%w: stories/00001-a.md: content is empty", service.ErrInvalidArchive)
+		mockImportService.On("ImportArchive", testUserID, mock.Anything, mock.Anything).Return(nil, err).Once()
+
+		c, rec := newRequest(map[string]string{"library.zip": "data"})
+
+		require.NoError(t, h.ImportArchive(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "content is empty")
+
+		mockImportService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 413 when the archive is too large", func(t *testing.T) {
+		mockImportService.On("ImportArchive", testUserID, mock.Anything, mock.Anything).Return(nil, service.ErrImportTooLarge).Once()
+
+		c, rec := newRequest(map[string]string{"library.zip": "data"})
+
+		require.NoError(t, h.ImportArchive(c))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+		mockImportService.AssertExpectations(t)
+	})
+}
+
+func TestImportHandler_ImportURL(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockImportService := new(MockImportService)
+	h := NewImportHandler(mockImportService)
+
+	newRequest := func(body string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPost, "/stories/import-url", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		return c, rec
+	}
+
+	t.Run("success: should import the page", func(t *testing.T) {
+		sourceURL := "https://news.example.com/rivers"
+		imported := &model.Story{ID: 40, UserID: testUserID, Title: "Rivers Are Rising", Source: model.StorySourceImported, SourceURL: &sourceURL}
+		mockImportService.On("ImportURL", testUserID, "https://news.example.com/rivers?ref=home").Return(imported, nil).Once()
+
+		c, rec := newRequest(`{"url": "https://news.example.com/rivers?ref=home"}`)
+
+		require.NoError(t, h.ImportURL(c))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		var response model.Story
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.NotNil(t, response.SourceURL)
+		assert.Equal(t, sourceURL, *response.SourceURL)
+
+		mockImportService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should reject a malformed url", func(t *testing.T) {
+		c, _ := newRequest(`{"url": "not a url"}`)
+
+		err := h.ImportURL(c)
+
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	})
+
+	t.Run("fail: should return 400 for private addresses", func(t *testing.T) {
+		mockImportService.On("ImportURL", testUserID, "http://169.254.169.254/latest").Return(nil, service.ErrInvalidImportURL).Once()
+
+		c, rec := newRequest(`{"url": "http://169.254.169.254/latest"}`)
+
+		require.NoError(t, h.ImportURL(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		mockImportService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 502 when the page cannot be fetched", func(t *testing.T) {
+		mockImportService.On("ImportURL", testUserID, "https://down.example.com/").Return(nil, service.ErrImportFetchFailed).Once()
+
+		c, rec := newRequest(`{"url": "https://down.example.com/"}`)
+
+		require.NoError(t, h.ImportURL(c))
+		assert.Equal(t, http.StatusBadGateway, rec.Code)
+
+		mockImportService.AssertExpectations(t)
+	})
+}
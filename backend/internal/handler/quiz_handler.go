@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/service"
+)
+
+type IQuizHandler interface {
+	GenerateQuiz(e echo.Context) error
+	GetQuiz(e echo.Context) error
+	SubmitAnswers(e echo.Context) error
+}
+
+type QuizHandler struct {
+	QuizService service.IQuizService
+}
+
+type QuizResponse struct {
+	StoryID   int                   `json:"story_id"`
+	Questions []*model.QuizQuestion `json:"questions"`
+}
+
+type SubmitAnswersRequest struct {
+	Answers []int `json:"answers" validate:"required,min=1"`
+}
+
+type QuizResultResponse struct {
+	*model.QuizAttempt
+	Results []service.QuestionResult `json:"results"`
+}
+
+func NewQuizHandler(quizService service.IQuizService) IQuizHandler {
+	return &QuizHandler{
+		QuizService: quizService,
+	}
+}
+
+func (h *QuizHandler) GenerateQuiz(c echo.Context) error {
+	storyID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid story id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	questions, err := h.QuizService.GenerateQuiz(storyID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrStoryNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "story not found"})
+		}
+		if errors.Is(err, service.ErrGenerationLimitExceeded) {
+			return c.JSON(http.StatusTooManyRequests, map[string]string{"error": "You have reached your daily story generation limit."})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to generate quiz"})
+	}
+
+	return c.JSON(http.StatusCreated, QuizResponse{StoryID: storyID, Questions: questions})
+}
+
+func (h *QuizHandler) GetQuiz(c echo.Context) error {
+	storyID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid story id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	questions, err := h.QuizService.GetQuiz(storyID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrStoryNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "story not found"})
+		}
+		if errors.Is(err, service.ErrQuizNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "quiz not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "database error"})
+	}
+
+	return c.JSON(http.StatusOK, QuizResponse{StoryID: storyID, Questions: questions})
+}
+
+func (h *QuizHandler) SubmitAnswers(c echo.Context) error {
+	storyID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid story id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	var req SubmitAnswersRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	result, err := h.QuizService.SubmitAnswers(storyID, userID, req.Answers)
+	if err != nil {
+		if errors.Is(err, service.ErrStoryNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "story not found"})
+		}
+		if errors.Is(err, service.ErrQuizNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "quiz not found"})
+		}
+		if errors.Is(err, service.ErrInvalidQuizAnswers) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "answers must match the questions and each must be one of its choices"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to grade quiz"})
+	}
+
+	res := QuizResultResponse{
+		QuizAttempt: result.Attempt,
+		Results:     result.Results,
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/service"
+)
+
+func TestQuizHandler_GenerateQuiz(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockQuizService := new(MockQuizService)
+	h := NewQuizHandler(mockQuizService)
+
+	t.Run("fail: should return 429 Too Many Requests if limit exceeded", func(t *testing.T) {
+		mockQuizService.On("GenerateQuiz", testStoryID, testUserID).Return(nil, service.ErrGenerationLimitExceeded).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/stories/:id/quiz")
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.Itoa(testStoryID))
+
+		require.NoError(t, h.GenerateQuiz(c))
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+		mockQuizService.AssertExpectations(t)
+	})
+}
+
+func TestQuizHandler_GetQuiz(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockQuizService := new(MockQuizService)
+	h := NewQuizHandler(mockQuizService)
+
+	t.Run("success: should return questions without answers", func(t *testing.T) {
+		questions := []*model.QuizQuestion{
+			{ID: 1, StoryID: testStoryID, QuestionType: model.QuestionTypeTrueFalse, Question: "Q?", Choices: []string{"True", "False"}, CorrectIndex: 1, Position: 1},
+		}
+		mockQuizService.On("GetQuiz", testStoryID, testUserID).Return(questions, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/stories/:id/quiz")
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.Itoa(testStoryID))
+
+		require.NoError(t, h.GetQuiz(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "correct_index")
+
+		var response QuizResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Len(t, response.Questions, 1)
+
+		mockQuizService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 404 if no quiz exists", func(t *testing.T) {
+		mockQuizService.On("GetQuiz", testStoryID, testUserID).Return(nil, service.ErrQuizNotFound).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/stories/:id/quiz")
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.Itoa(testStoryID))
+
+		require.NoError(t, h.GetQuiz(c))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockQuizService.AssertExpectations(t)
+	})
+}
+
+func TestQuizHandler_SubmitAnswers(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockQuizService := new(MockQuizService)
+	h := NewQuizHandler(mockQuizService)
+
+	t.Run("success: should return graded result", func(t *testing.T) {
+		answers := []int{1, 0}
+		result := &service.QuizResult{
+			Attempt: &model.QuizAttempt{ID: 1, UserID: testUserID, StoryID: testStoryID, Answers: []int64{1, 0}, Score: 2, Total: 2, Passed: true},
+			Results: []service.QuestionResult{
+				{QuestionID: 1, Answer: 1, CorrectIndex: 1, Correct: true},
+				{QuestionID: 2, Answer: 0, CorrectIndex: 0, Correct: true},
+			},
+		}
+		mockQuizService.On("SubmitAnswers", testStoryID, testUserID, answers).Return(result, nil).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"answers": [1, 0]}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/stories/:id/quiz/attempts")
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.Itoa(testStoryID))
+
+		require.NoError(t, h.SubmitAnswers(c))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		var response QuizResultResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, 2, response.Score)
+		assert.True(t, response.Passed)
+		assert.Len(t, response.Results, 2)
+
+		mockQuizService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 400 if answers do not match", func(t *testing.T) {
+		answers := []int{1}
+		mockQuizService.On("SubmitAnswers", testStoryID, testUserID, answers).Return(nil, service.ErrInvalidQuizAnswers).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"answers": [1]}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/stories/:id/quiz/attempts")
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.Itoa(testStoryID))
+
+		require.NoError(t, h.SubmitAnswers(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockQuizService.AssertExpectations(t)
+	})
+}
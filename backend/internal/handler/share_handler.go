@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/service"
+)
+
+type IShareHandler interface {
+	CreateShare(e echo.Context) error
+	GetShares(e echo.Context) error
+	RevokeShare(e echo.Context) error
+	GetSharedStory(e echo.Context) error
+}
+
+type ShareHandler struct {
+	ShareService service.IShareService
+}
+
+// CreateShareRequest は共有リンクの作成。expires_at を省略した場合は取り消すまで有効
+type CreateShareRequest struct {
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type GetSharesResponse struct {
+	Shares []*model.StoryShare `json:"shares"`
+}
+
+// maxShareTokenLength は受け付けるトークンの最大長。発行するトークンより長いものは DB を引かずに 404 にする
+const maxShareTokenLength = 64
+
+func NewShareHandler(shareService service.IShareService) IShareHandler {
+	return &ShareHandler{
+		ShareService: shareService,
+	}
+}
+
+func (h *ShareHandler) CreateShare(c echo.Context) error {
+	storyID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid story id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	var req CreateShareRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	share, err := h.ShareService.CreateShare(storyID, userID, req.ExpiresAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrStoryNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "story not found"})
+		case errors.Is(err, service.ErrInvalidShareExpiry):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case errors.Is(err, service.ErrTooManyShares):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to create share"})
+	}
+
+	return c.JSON(http.StatusCreated, share)
+}
+
+func (h *ShareHandler) GetShares(c echo.Context) error {
+	storyID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid story id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	shares, err := h.ShareService.ListShares(storyID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrStoryNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "story not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "database error"})
+	}
+
+	return c.JSON(http.StatusOK, GetSharesResponse{Shares: shares})
+}
+
+func (h *ShareHandler) RevokeShare(c echo.Context) error {
+	storyID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid story id"})
+	}
+	shareID, err := strconv.Atoi(c.Param("share_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid share id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	if err := h.ShareService.RevokeShare(shareID, storyID, userID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrStoryNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "story not found"})
+		case errors.Is(err, service.ErrShareNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "share not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to revoke share"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetSharedStory はログインなしで共有リンクのストーリーを返す。取り消し・期限切れがすぐ反映されるようキャッシュさせない
+func (h *ShareHandler) GetSharedStory(c echo.Context) error {
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	c.Response().Header().Set("X-Robots-Tag", "noindex")
+
+	token := c.Param("token")
+	if token == "" || len(token) > maxShareTokenLength {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "shared story not found"})
+	}
+
+	story, err := h.ShareService.GetSharedStory(token)
+	if err != nil {
+		if errors.Is(err, service.ErrShareNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "shared story not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get shared story"})
+	}
+
+	return c.JSON(http.StatusOK, story)
+}
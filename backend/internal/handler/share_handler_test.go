@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/service"
+)
+
+func TestShareHandler_CreateShare(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockShareService := new(MockShareService)
+	h := NewShareHandler(mockShareService)
+
+	newContext := func(body string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/stories/:id/shares")
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.Itoa(testStoryID))
+		return c, rec
+	}
+
+	t.Run("success: should create a share with an expiry", func(t *testing.T) {
+		expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		share := &model.StoryShare{ID: 1, StoryID: testStoryID, Token: "abc", ExpiresAt: &expiresAt}
+		mockShareService.On("CreateShare", testStoryID, testUserID, mock.MatchedBy(func(at *time.Time) bool {
+			return at != nil && at.Equal(expiresAt)
+		})).Return(share, nil).Once()
+
+		c, rec := newContext(`{"expires_at": "2030-01-01T00:00:00Z"}`)
+
+		require.NoError(t, h.CreateShare(c))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		var response model.StoryShare
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "abc", response.Token)
+
+		mockShareService.AssertExpectations(t)
+	})
+
+	t.Run("success: should create a share without expiry when the body is empty", func(t *testing.T) {
+		share := &model.StoryShare{ID: 2, StoryID: testStoryID, Token: "def"}
+		mockShareService.On("CreateShare", testStoryID, testUserID, (*time.Time)(nil)).Return(share, nil).Once()
+
+		c, rec := newContext("")
+
+		require.NoError(t, h.CreateShare(c))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		mockShareService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 400 for an expiry in the past", func(t *testing.T) {
+		mockShareService.On("CreateShare", testStoryID, testUserID, mock.Anything).Return(nil, service.ErrInvalidShareExpiry).Once()
+
+		c, rec := newContext(`{"expires_at": "2000-01-01T00:00:00Z"}`)
+
+		require.NoError(t, h.CreateShare(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		mockShareService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 404 for another user's story", func(t *testing.T) {
+		mockShareService.On("CreateShare", testStoryID, testUserID, mock.Anything).Return(nil, service.ErrStoryNotFound).Once()
+
+		c, rec := newContext(`{}`)
+
+		require.NoError(t, h.CreateShare(c))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		mockShareService.AssertExpectations(t)
+	})
+}
+
+func TestShareHandler_RevokeShare(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockShareService := new(MockShareService)
+	h := NewShareHandler(mockShareService)
+
+	newContext := func(shareID string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/stories/:id/shares/:share_id")
+		c.SetParamNames("id", "share_id")
+		c.SetParamValues(strconv.Itoa(testStoryID), shareID)
+		return c, rec
+	}
+
+	t.Run("success: should revoke the share", func(t *testing.T) {
+		mockShareService.On("RevokeShare", 5, testStoryID, testUserID).Return(nil).Once()
+
+		c, rec := newContext("5")
+
+		require.NoError(t, h.RevokeShare(c))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+
+		mockShareService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 404 for an unknown share", func(t *testing.T) {
+		mockShareService.On("RevokeShare", 6, testStoryID, testUserID).Return(service.ErrShareNotFound).Once()
+
+		c, rec := newContext("6")
+
+		require.NoError(t, h.RevokeShare(c))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		mockShareService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 400 for an invalid share id", func(t *testing.T) {
+		c, rec := newContext("abc")
+
+		require.NoError(t, h.RevokeShare(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestShareHandler_GetSharedStory(t *testing.T) {
+	_, e, _ := setupTestHandler(t)
+	mockShareService := new(MockShareService)
+	h := NewShareHandler(mockShareService)
+
+	// 共有リンクはログインなしで読むため、トークン (JWT) を設定しない
+	newContext := func(shareToken string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/shared/:token")
+		c.SetParamNames("token")
+		c.SetParamValues(shareToken)
+		return c, rec
+	}
+
+	t.Run("success: should return only the title, content and word count", func(t *testing.T) {
+		shared := &model.SharedStory{Title: "Rivers", Content: "Heavy rain fell.", WordCount: 3}
+		mockShareService.On("GetSharedStory", "valid-token").Return(shared, nil).Once()
+
+		c, rec := newContext("valid-token")
+
+		require.NoError(t, h.GetSharedStory(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "no-store", rec.Header().Get(echo.HeaderCacheControl))
+
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, map[string]any{"title": "Rivers", "content": "Heavy rain fell.", "word_count": float64(3)}, response)
+
+		mockShareService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 404 for unknown, revoked or expired tokens", func(t *testing.T) {
+		mockShareService.On("GetSharedStory", "expired-token").Return(nil, service.ErrShareNotFound).Once()
+
+		c, rec := newContext("expired-token")
+
+		require.NoError(t, h.GetSharedStory(c))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		mockShareService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 404 without a lookup for overlong tokens", func(t *testing.T) {
+		overlong := strings.Repeat("a", maxShareTokenLength+1)
+		c, rec := newContext(overlong)
+
+		require.NoError(t, h.GetSharedStory(c))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		mockShareService.AssertNotCalled(t, "GetSharedStory", overlong)
+	})
+}
@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/service"
+)
+
+type IShelfHandler interface {
+	GetShelves(e echo.Context) error
+	CreateShelf(e echo.Context) error
+	GetShelf(e echo.Context) error
+	RenameShelf(e echo.Context) error
+	DeleteShelf(e echo.Context) error
+	AddStory(e echo.Context) error
+	RemoveStory(e echo.Context) error
+	ReorderStories(e echo.Context) error
+}
+
+type ShelfHandler struct {
+	ShelfService service.IShelfService
+}
+
+type ShelfRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+}
+
+type AddShelfStoryRequest struct {
+	StoryID int `json:"story_id" validate:"required"`
+}
+
+type ReorderShelfRequest struct {
+	StoryIDs []int `json:"story_ids" validate:"required"`
+}
+
+type GetShelvesResponse struct {
+	Shelves []*model.Shelf `json:"shelves"`
+}
+
+type ShelfDetailResponse struct {
+	model.Shelf
+	Stories []*model.ShelfItem `json:"stories"`
+}
+
+func NewShelfHandler(shelfService service.IShelfService) IShelfHandler {
+	return &ShelfHandler{
+		ShelfService: shelfService,
+	}
+}
+
+func newShelfDetailResponse(detail *service.ShelfDetail) ShelfDetailResponse {
+	return ShelfDetailResponse{
+		Shelf:   detail.Shelf,
+		Stories: detail.Items,
+	}
+}
+
+func (h *ShelfHandler) GetShelves(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	shelves, err := h.ShelfService.ListShelves(userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "database error"})
+	}
+
+	return c.JSON(http.StatusOK, GetShelvesResponse{Shelves: shelves})
+}
+
+func (h *ShelfHandler) CreateShelf(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	req, err := bindShelfRequest(c)
+	if err != nil {
+		return err
+	}
+
+	shelf, err := h.ShelfService.CreateShelf(userID, req.Name)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to create shelf"})
+	}
+
+	return c.JSON(http.StatusCreated, shelf)
+}
+
+func (h *ShelfHandler) GetShelf(c echo.Context) error {
+	shelfID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid shelf id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	detail, err := h.ShelfService.GetShelf(shelfID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrShelfNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "shelf not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "database error"})
+	}
+
+	return c.JSON(http.StatusOK, newShelfDetailResponse(detail))
+}
+
+func (h *ShelfHandler) RenameShelf(c echo.Context) error {
+	shelfID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid shelf id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	req, err := bindShelfRequest(c)
+	if err != nil {
+		return err
+	}
+
+	shelf, err := h.ShelfService.RenameShelf(shelfID, userID, req.Name)
+	if err != nil {
+		if errors.Is(err, service.ErrShelfNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "shelf not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to rename shelf"})
+	}
+
+	return c.JSON(http.StatusOK, shelf)
+}
+
+func (h *ShelfHandler) DeleteShelf(c echo.Context) error {
+	shelfID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid shelf id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	if err := h.ShelfService.DeleteShelf(shelfID, userID); err != nil {
+		if errors.Is(err, service.ErrShelfNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "shelf not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to delete shelf"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *ShelfHandler) AddStory(c echo.Context) error {
+	shelfID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid shelf id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	var req AddShelfStoryRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	detail, err := h.ShelfService.AddStory(shelfID, req.StoryID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrShelfNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "shelf not found"})
+		}
+		if errors.Is(err, service.ErrStoryNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "story not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to add story to shelf"})
+	}
+
+	return c.JSON(http.StatusOK, newShelfDetailResponse(detail))
+}
+
+func (h *ShelfHandler) RemoveStory(c echo.Context) error {
+	shelfID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid shelf id"})
+	}
+	storyID, err := strconv.Atoi(c.Param("story_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid story id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	if err := h.ShelfService.RemoveStory(shelfID, storyID, userID); err != nil {
+		if errors.Is(err, service.ErrShelfNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "shelf not found"})
+		}
+		if errors.Is(err, service.ErrStoryNotOnShelf) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "story is not on the shelf"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to remove story from shelf"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *ShelfHandler) ReorderStories(c echo.Context) error {
+	shelfID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid shelf id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	var req ReorderShelfRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	detail, err := h.ShelfService.ReorderStories(shelfID, userID, req.StoryIDs)
+	if err != nil {
+		if errors.Is(err, service.ErrShelfNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "shelf not found"})
+		}
+		if errors.Is(err, service.ErrInvalidShelfOrder) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to reorder shelf"})
+	}
+
+	return c.JSON(http.StatusOK, newShelfDetailResponse(detail))
+}
+
+// bindShelfRequest はリクエストを読み取り、前後の空白を除いた棚の名前を検証する
+func bindShelfRequest(c echo.Context) (*ShelfRequest, error) {
+	var req ShelfRequest
+	if err := c.Bind(&req); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, ValidationErrorResponse{Error: "invalid request body"})
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if err := c.Validate(&req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
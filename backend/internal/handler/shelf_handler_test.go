@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/service"
+)
+
+func TestShelfHandler_GetShelf(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockShelfService := new(MockShelfService)
+	h := NewShelfHandler(mockShelfService)
+
+	t.Run("success: should return progress and ordered stories", func(t *testing.T) {
+		detail := &service.ShelfDetail{
+			Shelf: model.Shelf{ID: 1, UserID: testUserID, Name: "Week 1", StoryCount: 2, ReadStoryCount: 1, TotalWordCount: 300, RemainingWordCount: 200},
+			Items: []*model.ShelfItem{
+				{Position: 1, StoryID: 10, Title: "First", WordCount: 100, ReadCount: 1},
+				{Position: 2, StoryID: 11, Title: "Second", WordCount: 200},
+			},
+		}
+		mockShelfService.On("GetShelf", 1, testUserID).Return(detail, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/shelves/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		require.NoError(t, h.GetShelf(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response ShelfDetailResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, 1, response.ReadStoryCount)
+		assert.Equal(t, 2, response.StoryCount)
+		assert.Equal(t, 200, response.RemainingWordCount)
+		require.Len(t, response.Stories, 2)
+		assert.Equal(t, 11, response.Stories[1].StoryID)
+
+		mockShelfService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 404 for an unknown shelf", func(t *testing.T) {
+		mockShelfService.On("GetShelf", 2, testUserID).Return(nil, service.ErrShelfNotFound).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/shelves/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("2")
+
+		require.NoError(t, h.GetShelf(c))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		mockShelfService.AssertExpectations(t)
+	})
+}
+
+func TestShelfHandler_ReorderStories(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockShelfService := new(MockShelfService)
+	h := NewShelfHandler(mockShelfService)
+
+	newContext := func(body string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/shelves/:id/stories")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		return c, rec
+	}
+
+	t.Run("success: should pass the new order", func(t *testing.T) {
+		detail := &service.ShelfDetail{Shelf: model.Shelf{ID: 1}, Items: []*model.ShelfItem{}}
+		mockShelfService.On("ReorderStories", 1, testUserID, []int{12, 10, 11}).Return(detail, nil).Once()
+
+		c, rec := newContext(`{"story_ids": [12, 10, 11]}`)
+		require.NoError(t, h.ReorderStories(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		mockShelfService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 400 when the order does not match the shelf", func(t *testing.T) {
+		mockShelfService.On("ReorderStories", 1, testUserID, []int{12}).Return(nil, service.ErrInvalidShelfOrder).Once()
+
+		c, rec := newContext(`{"story_ids": [12]}`)
+		require.NoError(t, h.ReorderStories(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		mockShelfService.AssertExpectations(t)
+	})
+}
+
+func TestShelfHandler_RemoveStory(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockShelfService := new(MockShelfService)
+	h := NewShelfHandler(mockShelfService)
+
+	t.Run("success: should remove the story from the shelf", func(t *testing.T) {
+		mockShelfService.On("RemoveStory", 1, testStoryID, testUserID).Return(nil).Once()
+
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/shelves/:id/stories/:story_id")
+		c.SetParamNames("id", "story_id")
+		c.SetParamValues("1", strconv.Itoa(testStoryID))
+
+		require.NoError(t, h.RemoveStory(c))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+
+		mockShelfService.AssertExpectations(t)
+	})
+}
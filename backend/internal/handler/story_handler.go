@@ -2,13 +2,19 @@ package handler
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
 	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
 	"github.com/shuheikomatsuki/readoku/backend/internal/service"
+	"github.com/shuheikomatsuki/readoku/backend/internal/timeutil"
 )
 
 type IStoryHandler interface {
@@ -16,36 +22,121 @@ type IStoryHandler interface {
 	GetStories(e echo.Context) error
 	GetStory(e echo.Context) error
 	DeleteStory(e echo.Context) error
+	GetTrash(e echo.Context) error
+	GetDuplicateClusters(e echo.Context) error
+	RestoreStory(e echo.Context) error
+	BulkUpdateStories(e echo.Context) error
+	GetRevisions(e echo.Context) error
+	GetRevisionDiff(e echo.Context) error
+	RevertStory(e echo.Context) error
 	UpdateStory(e echo.Context) error
 	MarkStoryAsRead(e echo.Context) error
+	FavoriteStory(e echo.Context) error
+	UnfavoriteStory(e echo.Context) error
 	UndoLastRead(e echo.Context) error
+	ContinueStory(e echo.Context) error
+	GetSeries(e echo.Context) error
+	RewriteStory(e echo.Context) error
+	TranslateStory(e echo.Context) error
 }
 
 type StoryHandler struct {
 	// StoryRepo  repository.IStoryRepository
 	// LLMService service.ILLMService
-	StoryService service.IStoryService
+	StoryService    service.IStoryService
+	TemplateService service.ITemplateService
+}
+
+// GenerateStoryRequest は文章生成リクエスト。template_id を指定した場合は prompt の代わりにテンプレートを展開する
+type GenerateStoryRequest struct {
+	Prompt          string            `json:"prompt"`
+	TemplateID      *int              `json:"template_id"`
+	Variables       map[string]string `json:"variables"`
+	Level           string            `json:"level" validate:"omitempty,oneof=A1 A2 B1 B2 C1 C2"`
+	WordCount       int               `json:"word_count" validate:"omitempty,min=50,max=2000"`
+	WithTranslation bool              `json:"with_translation"`
 }
 
 type GetStoriesResponse struct {
-	Stories     []*model.Story `json:"stories"`
-	TotalCount  int            `json:"total_count"`
-	TotalPages  int            `json:"total_pages"`
-	CurrentPage int            `json:"current_page"`
+	Stories     []*model.StoryListItem `json:"stories"`
+	TotalCount  int                    `json:"total_count"`
+	TotalPages  int                    `json:"total_pages"`
+	CurrentPage int                    `json:"current_page"`
+	NextCursor  *string                `json:"next_cursor"`
+	PrevCursor  *string                `json:"prev_cursor"`
 }
 
+// maxSearchQueryLength は全文検索クエリの最大長 (バイト)
+const maxSearchQueryLength = 200
+
+// UpdateStoryRequest はタイトル・本文の更新。本文を変更した場合は編集前の内容を版として残す
 type UpdateStoryRequest struct {
-	Title string `json:"title" validate:"required,min=1,max=100"`
+	Title   *string `json:"title" validate:"omitnil,min=1,max=100"`
+	Content *string `json:"content" validate:"omitnil,min=1,max=50000"`
+}
+
+type RevisionsResponse struct {
+	Revisions []*model.StoryRevision `json:"revisions"`
+}
+
+type RevisionDiffResponse struct {
+	Revision *model.StoryRevision `json:"revision"`
+	Changes  []service.DiffChange `json:"changes"`
 }
 
 type StoryDetailResponse struct {
 	model.Story
-	ReadCount int `json:"read_count"`
+	ReadCount    int                         `json:"read_count"`
+	Series       *service.SeriesNavigation   `json:"series,omitempty"`
+	Derivatives  []*model.StoryVariant       `json:"derivatives"`
+	ParallelText []service.ParallelParagraph `json:"parallel_text,omitempty"`
+	Tags         []*model.Tag                `json:"tags"`
+}
+
+type GenerateStoryResponse struct {
+	*model.Story
+	ParallelText []service.ParallelParagraph `json:"parallel_text,omitempty"`
+}
+
+type TranslationResponse struct {
+	StoryID      int                         `json:"story_id"`
+	ParallelText []service.ParallelParagraph `json:"parallel_text"`
+}
+
+type RewriteStoryRequest struct {
+	Level string `json:"level" validate:"required,oneof=A1 A2 B1 B2 C1 C2"`
+}
+
+type TrashResponse struct {
+	Stories []*model.Story `json:"stories"`
+}
+
+type DuplicateClustersResponse struct {
+	Clusters []*model.DuplicateCluster `json:"clusters"`
+}
+
+// BulkStoriesRequest は一括操作のリクエスト。tag_id は tag / untag、shelf_id は move_to_shelf で必須
+type BulkStoriesRequest struct {
+	StoryIDs []int  `json:"ids" validate:"required,min=1,max=100"`
+	Action   string `json:"action" validate:"required,oneof=delete tag untag mark_read move_to_shelf"`
+	TagID    int    `json:"tag_id"`
+	ShelfID  int    `json:"shelf_id"`
+}
+
+type BulkStoriesResponse struct {
+	Results []*model.BulkStoryResult `json:"results"`
+}
+
+type SeriesDetailResponse struct {
+	model.Series
+	Chapters       []*model.SeriesChapter `json:"chapters"`
+	TotalWordCount int                    `json:"total_word_count"`
 }
 
-func NewStoryHandler(storyService service.IStoryService) IStoryHandler {
+func NewStoryHandler(storyService service.IStoryService, templateService service.ITemplateService) IStoryHandler {
 	return &StoryHandler{
-		StoryService: storyService,
+		StoryService:    storyService,
+		TemplateService: templateService,
 	}
 }
 
@@ -55,19 +146,47 @@ func (h *StoryHandler) GenerateStory(c echo.Context) error {
 		return c.JSON(http.StatusUnauthorized, "invalid token")
 	}
 
-	var req struct {
-		Prompt string `json:"prompt"`
-	}
+	var req GenerateStoryRequest
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
 	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	input := service.GenerateStoryInput{
+		Prompt:    req.Prompt,
+		Level:     req.Level,
+		WordCount: req.WordCount,
+	}
+
+	// テンプレート指定時は展開結果をプロンプトにし、リクエストで未指定のパラメータはテンプレートの既定値を使う
+	if req.TemplateID != nil {
+		rendered, err := h.TemplateService.RenderTemplate(*req.TemplateID, userID, req.Variables)
+		if err != nil {
+			if errors.Is(err, service.ErrTemplateNotFound) {
+				return c.JSON(http.StatusNotFound, map[string]string{"error": "template not found"})
+			}
+			if errors.Is(err, service.ErrMissingTemplateVariables) {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to render template"})
+		}
+		input.Prompt = rendered.Prompt
+		if input.Level == "" {
+			input.Level = rendered.Level
+		}
+		if input.WordCount == 0 {
+			input.WordCount = rendered.WordCount
+		}
+	}
 
 	// (簡易バリデーション)
-	if req.Prompt == "" {
+	if input.Prompt == "" {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "prompt is required"})
 	}
 
-	story, err := h.StoryService.GenerateStory(userID, req.Prompt)
+	story, err := h.StoryService.GenerateStory(userID, input)
 	if err != nil {
 		if errors.Is(err, service.ErrGenerationLimitExceeded) {
 			return c.JSON(http.StatusTooManyRequests, map[string]string{"error": "You have reached your daily story generation limit."})
@@ -75,7 +194,19 @@ func (h *StoryHandler) GenerateStory(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to generate story content"})
 	}
 
-	return c.JSON(http.StatusCreated, story)
+	res := GenerateStoryResponse{Story: story}
+
+	// 翻訳に失敗しても生成済みのストーリーは返し、後から翻訳を作成できるようにする
+	if req.WithTranslation {
+		parallelText, err := h.StoryService.TranslateStory(story.ID, userID)
+		if err != nil {
+			c.Logger().Warnf("failed to translate story %d: %v", story.ID, err)
+		} else {
+			res.ParallelText = parallelText
+		}
+	}
+
+	return c.JSON(http.StatusCreated, res)
 }
 
 func (h *StoryHandler) GetStories(c echo.Context) error {
@@ -84,21 +215,19 @@ func (h *StoryHandler) GetStories(c echo.Context) error {
 		return c.JSON(http.StatusUnauthorized, "invalid token")
 	}
 
-	// --- クエリパラメータの解釈 ---
-	pageStr := c.QueryParam("page")
-	page, err := strconv.Atoi(pageStr)
-	if err != nil || page <= 0 {
-		page = 1
-	}
-
-	limitStr := c.QueryParam("limit")
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 10
+	query, err := parseStoryListQuery(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
-	paginatedResult, err := h.StoryService.GetStories(userID, page, limit)
+	paginatedResult, err := h.StoryService.GetStories(userID, query)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidCursor) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid cursor"})
+		}
+		if errors.Is(err, service.ErrCursorNotSupported) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "cursor is only available when sorted by newest or oldest"})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "database error"})
 	}
 
@@ -107,11 +236,111 @@ func (h *StoryHandler) GetStories(c echo.Context) error {
 		TotalCount:  paginatedResult.TotalCount,
 		TotalPages:  paginatedResult.TotalPages,
 		CurrentPage: paginatedResult.CurrentPage,
+		NextCursor:  paginatedResult.NextCursor,
+		PrevCursor:  paginatedResult.PrevCursor,
 	}
 
 	return c.JSON(http.StatusOK, res)
 }
 
+// parseStoryListQuery は一覧取得のクエリパラメータを解釈する。
+// page / limit は不正な値の場合に既定値を使い、絞り込み・並び替えの不正な値はエラーにする
+func parseStoryListQuery(c echo.Context) (service.StoryListQuery, error) {
+	page, err := strconv.Atoi(c.QueryParam("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+
+	query := service.StoryListQuery{Page: page, Limit: limit, Cursor: c.QueryParam("cursor")}
+
+	// q が指定された場合はタイトル・本文を全文検索し、関連度順に並べる
+	query.Query = strings.TrimSpace(c.QueryParam("q"))
+	if len(query.Query) > maxSearchQueryLength {
+		return query, errors.New("search query is too long")
+	}
+
+	if query.MinWordCount, err = parseOptionalInt(c, "min_words"); err != nil {
+		return query, err
+	}
+	if query.MaxWordCount, err = parseOptionalInt(c, "max_words"); err != nil {
+		return query, err
+	}
+	if query.MinWordCount != nil && query.MaxWordCount != nil && *query.MinWordCount > *query.MaxWordCount {
+		return query, errors.New("min_words must not be greater than max_words")
+	}
+	if query.CreatedFrom, err = parseOptionalTime(c, "created_from", false); err != nil {
+		return query, err
+	}
+	if query.CreatedTo, err = parseOptionalTime(c, "created_to", true); err != nil {
+		return query, err
+	}
+
+	query.Level = c.QueryParam("level")
+	if query.Level != "" && !slices.Contains(model.CEFRLevels, query.Level) {
+		return query, fmt.Errorf("level must be one of [%s]", strings.Join(model.CEFRLevels, " "))
+	}
+
+	if query.TagID, err = parseOptionalInt(c, "tag_id"); err != nil {
+		return query, err
+	}
+	if favorites := c.QueryParam("favorites"); favorites != "" {
+		if query.FavoritesOnly, err = strconv.ParseBool(favorites); err != nil {
+			return query, errors.New("favorites must be a boolean")
+		}
+	}
+
+	query.ReadStatus = repository.ReadStatus(c.QueryParam("status"))
+	switch query.ReadStatus {
+	case repository.ReadStatusAny, repository.ReadStatusRead, repository.ReadStatusUnread:
+	default:
+		return query, errors.New("status must be one of [read unread]")
+	}
+
+	query.Sort = repository.StorySort(c.QueryParam("sort"))
+	if !query.Sort.IsValid() {
+		return query, errors.New("sort must be one of [newest oldest longest shortest title recently_read]")
+	}
+
+	return query, nil
+}
+
+func parseOptionalInt(c echo.Context, name string) (*int, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("%s must be a non-negative integer", name)
+	}
+	return &n, nil
+}
+
+// parseOptionalTime は RFC3339 の日時または YYYY-MM-DD の日付 (日本時間) を解釈する。
+// endOfRange が true の場合、日付指定はその日を含むよう翌日 0 時を返す
+func parseOptionalTime(c echo.Context, name string, endOfRange bool) (*time.Time, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, value, timeutil.Tokyo())
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a date (YYYY-MM-DD) or an RFC3339 timestamp", name)
+	}
+	if endOfRange {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
 func (h *StoryHandler) GetStory(c echo.Context) error {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
@@ -133,8 +362,12 @@ func (h *StoryHandler) GetStory(c echo.Context) error {
 	}
 
 	res := StoryDetailResponse{
-		Story:     storyDetail.Story,
-		ReadCount: storyDetail.ReadCount,
+		Story:        storyDetail.Story,
+		ReadCount:    storyDetail.ReadCount,
+		Series:       storyDetail.Series,
+		Derivatives:  storyDetail.Derivatives,
+		ParallelText: storyDetail.ParallelText,
+		Tags:         storyDetail.Tags,
 	}
 
 	return c.JSON(http.StatusOK, res)
@@ -163,7 +396,102 @@ func (h *StoryHandler) DeleteStory(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to delete story"})
 	}
 
-	return c.JSON(http.StatusNoContent, nil)
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *StoryHandler) GetTrash(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	stories, err := h.StoryService.GetTrash(userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "database error"})
+	}
+
+	return c.JSON(http.StatusOK, TrashResponse{Stories: stories})
+}
+
+// GetDuplicateClusters は整理のため、本文がほぼ同じストーリーのまとまりを返す
+func (h *StoryHandler) GetDuplicateClusters(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	clusters, err := h.StoryService.GetDuplicateClusters(userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "database error"})
+	}
+
+	return c.JSON(http.StatusOK, DuplicateClustersResponse{Clusters: clusters})
+}
+
+func (h *StoryHandler) RestoreStory(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid story id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	story, err := h.StoryService.RestoreStory(id, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrStoryNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "story not found in trash"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to restore story"})
+	}
+
+	return c.JSON(http.StatusOK, story)
+}
+
+func (h *StoryHandler) BulkUpdateStories(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	var req BulkStoriesRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	op := repository.BulkOperation{Action: repository.BulkAction(req.Action), StoryIDs: req.StoryIDs}
+	switch op.Action {
+	case repository.BulkActionTag, repository.BulkActionUntag:
+		if req.TagID == 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "tag_id is required"})
+		}
+		op.TagID = req.TagID
+	case repository.BulkActionMoveToShelf:
+		if req.ShelfID == 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "shelf_id is required"})
+		}
+		op.ShelfID = req.ShelfID
+	}
+
+	results, err := h.StoryService.BulkUpdateStories(userID, op)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrTagNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "tag not found"})
+		case errors.Is(err, service.ErrShelfNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "shelf not found"})
+		case errors.Is(err, service.ErrInvalidBulkAction):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid action"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to apply bulk operation"})
+	}
+
+	return c.JSON(http.StatusOK, BulkStoriesResponse{Results: results})
 }
 
 func (h *StoryHandler) UpdateStory(c echo.Context) error {
@@ -185,7 +513,15 @@ func (h *StoryHandler) UpdateStory(c echo.Context) error {
 		return err
 	}
 
-	updatedStory, err := h.StoryService.UpdateStoryTitle(id, userID, req.Title)
+	var updatedStory *model.Story
+	switch {
+	case req.Content != nil:
+		updatedStory, err = h.StoryService.UpdateStoryContent(id, userID, req.Title, *req.Content)
+	case req.Title != nil:
+		updatedStory, err = h.StoryService.UpdateStoryTitle(id, userID, *req.Title)
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "title or content is required"})
+	}
 	if err != nil {
 		if errors.Is(err, service.ErrStoryNotFound) {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "story not found"})
@@ -196,6 +532,89 @@ func (h *StoryHandler) UpdateStory(c echo.Context) error {
 	return c.JSON(http.StatusOK, updatedStory)
 }
 
+func (h *StoryHandler) GetRevisions(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid story id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	revisions, err := h.StoryService.GetRevisions(id, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrStoryNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "story not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "database error"})
+	}
+
+	return c.JSON(http.StatusOK, RevisionsResponse{Revisions: revisions})
+}
+
+func (h *StoryHandler) GetRevisionDiff(c echo.Context) error {
+	id, revisionNumber, err := parseRevisionParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	diff, err := h.StoryService.GetRevisionDiff(id, userID, revisionNumber)
+	if err != nil {
+		return revisionErrorResponse(c, err, "database error")
+	}
+
+	return c.JSON(http.StatusOK, RevisionDiffResponse{Revision: diff.Revision, Changes: diff.Changes})
+}
+
+func (h *StoryHandler) RevertStory(c echo.Context) error {
+	id, revisionNumber, err := parseRevisionParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	story, err := h.StoryService.RevertStory(id, userID, revisionNumber)
+	if err != nil {
+		return revisionErrorResponse(c, err, "failed to revert story")
+	}
+
+	return c.JSON(http.StatusOK, story)
+}
+
+// parseRevisionParams はパスからストーリー ID と版番号を取り出す
+func parseRevisionParams(c echo.Context) (int, int, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return 0, 0, errors.New("invalid story id")
+	}
+	revisionNumber, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		return 0, 0, errors.New("invalid revision number")
+	}
+	return id, revisionNumber, nil
+}
+
+func revisionErrorResponse(c echo.Context, err error, fallback string) error {
+	switch {
+	case errors.Is(err, service.ErrStoryNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "story not found"})
+	case errors.Is(err, service.ErrRevisionNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "revision not found"})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": fallback})
+}
+
 func (h *StoryHandler) MarkStoryAsRead(c echo.Context) error {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -219,6 +638,35 @@ func (h *StoryHandler) MarkStoryAsRead(c echo.Context) error {
 	return c.JSON(http.StatusCreated, map[string]string{"message": "Story marked as read successfully"})
 }
 
+func (h *StoryHandler) FavoriteStory(c echo.Context) error {
+	return h.setFavorite(c, true)
+}
+
+func (h *StoryHandler) UnfavoriteStory(c echo.Context) error {
+	return h.setFavorite(c, false)
+}
+
+func (h *StoryHandler) setFavorite(c echo.Context, favorite bool) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid story id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	if err := h.StoryService.SetFavorite(id, userID, favorite); err != nil {
+		if errors.Is(err, service.ErrStoryNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "story not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to update favorite"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
 func (h *StoryHandler) UndoLastRead(c echo.Context) error {
 	storyID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -240,3 +688,120 @@ func (h *StoryHandler) UndoLastRead(c echo.Context) error {
 
 	return c.JSON(http.StatusNoContent, nil)
 }
+
+func (h *StoryHandler) ContinueStory(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid story id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	story, err := h.StoryService.ContinueStory(id, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrStoryNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "story not found"})
+		}
+		if errors.Is(err, service.ErrGenerationLimitExceeded) {
+			return c.JSON(http.StatusTooManyRequests, map[string]string{"error": "You have reached your daily story generation limit."})
+		}
+		if errors.Is(err, service.ErrChapterAlreadyExists) {
+			return c.JSON(http.StatusConflict, map[string]string{"error": "the next chapter is already being generated"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to generate next chapter"})
+	}
+
+	return c.JSON(http.StatusCreated, story)
+}
+
+func (h *StoryHandler) GetSeries(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid series id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	seriesDetail, err := h.StoryService.GetSeries(id, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrSeriesNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "series not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "database error"})
+	}
+
+	res := SeriesDetailResponse{
+		Series:         seriesDetail.Series,
+		Chapters:       seriesDetail.Chapters,
+		TotalWordCount: seriesDetail.TotalWordCount,
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h *StoryHandler) RewriteStory(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid story id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	var req RewriteStoryRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	story, err := h.StoryService.RewriteStory(id, userID, req.Level)
+	if err != nil {
+		if errors.Is(err, service.ErrStoryNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "story not found"})
+		}
+		if errors.Is(err, service.ErrAlreadyAtLevel) {
+			return c.JSON(http.StatusConflict, map[string]string{"error": "story is already at the requested level"})
+		}
+		if errors.Is(err, service.ErrGenerationLimitExceeded) {
+			return c.JSON(http.StatusTooManyRequests, map[string]string{"error": "You have reached your daily story generation limit."})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to rewrite story"})
+	}
+
+	return c.JSON(http.StatusCreated, story)
+}
+
+func (h *StoryHandler) TranslateStory(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid story id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	parallelText, err := h.StoryService.TranslateStory(id, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrStoryNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "story not found"})
+		}
+		if errors.Is(err, service.ErrGenerationLimitExceeded) {
+			return c.JSON(http.StatusTooManyRequests, map[string]string{"error": "You have reached your daily story generation limit."})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to translate story"})
+	}
+
+	return c.JSON(http.StatusCreated, TranslationResponse{StoryID: id, ParallelText: parallelText})
+}
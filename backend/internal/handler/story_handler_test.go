@@ -13,9 +13,11 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
 	"github.com/shuheikomatsuki/readoku/backend/internal/service"
 	"github.com/shuheikomatsuki/readoku/backend/internal/timeutil"
 )
@@ -41,12 +43,13 @@ func setupTestHandler(t *testing.T) (*MockStoryService, *echo.Echo, *jwt.Token)
 
 func TestStoryHandler_GetStory(t *testing.T) {
 	mockStoryService, e, token := setupTestHandler(t)
-	h := NewStoryHandler(mockStoryService)
+	h := NewStoryHandler(mockStoryService, new(MockTemplateService))
 
 	t.Run("success: should return a story", func(t *testing.T) {
 		expectedDetail := &service.StoryDetail{
 			Story:     *testStory,
 			ReadCount: 5,
+			Tags:      []*model.Tag{{ID: 1, UserID: testUserID, Name: "science"}},
 		}
 
 		mockStoryService.On("GetStory", testStoryID, testUserID).Return(expectedDetail, nil).Once()
@@ -66,6 +69,8 @@ func TestStoryHandler_GetStory(t *testing.T) {
 		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &receivedResponse))
 		assert.Equal(t, expectedDetail.Title, receivedResponse.Title)
 		assert.Equal(t, expectedDetail.ReadCount, receivedResponse.ReadCount)
+		require.Len(t, receivedResponse.Tags, 1)
+		assert.Equal(t, "science", receivedResponse.Tags[0].Name)
 
 		mockStoryService.AssertExpectations(t)
 	})
@@ -90,19 +95,19 @@ func TestStoryHandler_GetStory(t *testing.T) {
 
 func TestStoryHandler_GetStories(t *testing.T) {
 	mockStoryService, e, token := setupTestHandler(t)
-	h := NewStoryHandler(mockStoryService)
+	h := NewStoryHandler(mockStoryService, new(MockTemplateService))
 
 	t.Run("success: should return a list of story", func(t *testing.T) {
 		page, limit := 1, 10
 
 		expectedResult := &service.PaginatedStories{
-			Stories:     []*model.Story{testStory},
+			Stories:     []*model.StoryListItem{{Story: *testStory}},
 			TotalCount:  1,
 			TotalPages:  1,
 			CurrentPage: page,
 		}
 
-		mockStoryService.On("GetStories", testUserID, page, limit).Return(expectedResult, nil).Once()
+		mockStoryService.On("GetStories", testUserID, service.StoryListQuery{Page: page, Limit: limit}).Return(expectedResult, nil).Once()
 
 		reqURL := fmt.Sprintf("/stories?page=%d&limit=%d", page, limit)
 		req := httptest.NewRequest(http.MethodGet, reqURL, nil)
@@ -122,11 +127,383 @@ func TestStoryHandler_GetStories(t *testing.T) {
 
 		mockStoryService.AssertExpectations(t)
 	})
+
+	t.Run("success: should pass the search query and return snippets", func(t *testing.T) {
+		snippet := "a <mark>volcano</mark> erupts"
+		expectedResult := &service.PaginatedStories{
+			Stories:     []*model.StoryListItem{{Story: *testStory, Snippet: &snippet}},
+			TotalCount:  1,
+			TotalPages:  1,
+			CurrentPage: 1,
+		}
+		expectedQuery := service.StoryListQuery{Page: 1, Limit: 10}
+		expectedQuery.Query = "volcano eruption"
+		mockStoryService.On("GetStories", testUserID, expectedQuery).Return(expectedResult, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/stories?q=+volcano+eruption+", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.GetStories(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response GetStoriesResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.Len(t, response.Stories, 1)
+		assert.Equal(t, snippet, *response.Stories[0].Snippet)
+
+		mockStoryService.AssertExpectations(t)
+	})
+}
+
+func TestStoryHandler_GetStories_ReadStats(t *testing.T) {
+	mockStoryService, e, token := setupTestHandler(t)
+	h := NewStoryHandler(mockStoryService, new(MockTemplateService))
+
+	t.Run("success: should include word count and read stats in list items", func(t *testing.T) {
+		lastReadAt := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+		unread := *testStory
+		unread.ID = testStoryID + 1
+		expectedResult := &service.PaginatedStories{
+			Stories: []*model.StoryListItem{
+				{Story: *testStory, ReadCount: 2, LastReadAt: &lastReadAt},
+				{Story: unread},
+			},
+			TotalCount:  2,
+			TotalPages:  1,
+			CurrentPage: 1,
+		}
+		mockStoryService.On("GetStories", testUserID, service.StoryListQuery{Page: 1, Limit: 10}).Return(expectedResult, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/stories", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.GetStories(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response struct {
+			Stories []map[string]any `json:"stories"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.Len(t, response.Stories, 2)
+
+		read := response.Stories[0]
+		assert.EqualValues(t, testStory.WordCount, read["word_count"])
+		assert.EqualValues(t, 2, read["read_count"])
+		assert.Equal(t, lastReadAt.Format(time.RFC3339), read["last_read_at"])
+
+		// 未読でもキーは省略せず 0 / null を返す
+		assert.EqualValues(t, 0, response.Stories[1]["read_count"])
+		assert.Contains(t, response.Stories[1], "last_read_at")
+		assert.Nil(t, response.Stories[1]["last_read_at"])
+
+		mockStoryService.AssertExpectations(t)
+	})
+}
+
+func TestStoryHandler_GetStories_Cursor(t *testing.T) {
+	mockStoryService, e, token := setupTestHandler(t)
+	h := NewStoryHandler(mockStoryService, new(MockTemplateService))
+
+	t.Run("success: should pass the cursor and return next/prev cursors", func(t *testing.T) {
+		next, prev := "next-cursor", "prev-cursor"
+		expectedResult := &service.PaginatedStories{
+			Stories:    []*model.StoryListItem{{Story: *testStory}},
+			TotalCount: 30,
+			TotalPages: 3,
+			NextCursor: &next,
+			PrevCursor: &prev,
+		}
+		mockStoryService.On("GetStories", testUserID, service.StoryListQuery{Page: 1, Limit: 10, Cursor: "abc"}).Return(expectedResult, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/stories?cursor=abc", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.GetStories(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response GetStoriesResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, next, *response.NextCursor)
+		assert.Equal(t, prev, *response.PrevCursor)
+
+		mockStoryService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 400 for an invalid cursor", func(t *testing.T) {
+		mockStoryService.On("GetStories", testUserID, service.StoryListQuery{Page: 1, Limit: 10, Cursor: "broken"}).Return(nil, service.ErrInvalidCursor).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/stories?cursor=broken", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.GetStories(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockStoryService.AssertExpectations(t)
+	})
+}
+
+func TestStoryHandler_GetStories_FiltersAndSort(t *testing.T) {
+	mockStoryService, e, token := setupTestHandler(t)
+	h := NewStoryHandler(mockStoryService, new(MockTemplateService))
+
+	t.Run("success: should parse filters and sort", func(t *testing.T) {
+		minWords, maxWords := 100, 500
+		createdFrom := time.Date(2025, 4, 1, 0, 0, 0, 0, timeutil.Tokyo())
+		// 日付指定の created_to はその日を含む
+		createdTo := time.Date(2025, 5, 1, 0, 0, 0, 0, timeutil.Tokyo())
+
+		mockStoryService.On("GetStories", testUserID, mock.MatchedBy(func(q service.StoryListQuery) bool {
+			return q.Sort == repository.StorySortLongest &&
+				*q.MinWordCount == minWords && *q.MaxWordCount == maxWords &&
+				q.CreatedFrom.Equal(createdFrom) && q.CreatedTo.Equal(createdTo) &&
+				q.Level == "B1" && q.ReadStatus == repository.ReadStatusUnread && *q.TagID == 7 && q.FavoritesOnly
+		})).Return(&service.PaginatedStories{Stories: []*model.StoryListItem{}}, nil).Once()
+
+		reqURL := "/stories?min_words=100&max_words=500&created_from=2025-04-01&created_to=2025-04-30&level=B1&status=unread&tag_id=7&favorites=true&sort=longest"
+		req := httptest.NewRequest(http.MethodGet, reqURL, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.GetStories(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockStoryService.AssertExpectations(t)
+	})
+
+	for _, reqURL := range []string{
+		"/stories?sort=random",
+		"/stories?status=skimmed",
+		"/stories?level=Z9",
+		"/stories?min_words=-1",
+		"/stories?min_words=500&max_words=100",
+		"/stories?created_from=yesterday",
+		"/stories?tag_id=science",
+		"/stories?favorites=maybe",
+	} {
+		t.Run("fail: should return 400 for "+reqURL, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, reqURL, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("user", token)
+
+			require.NoError(t, h.GetStories(c))
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+	}
+}
+
+func TestStoryHandler_Trash(t *testing.T) {
+	mockStoryService, e, token := setupTestHandler(t)
+	h := NewStoryHandler(mockStoryService, new(MockTemplateService))
+
+	t.Run("success: should list trashed stories", func(t *testing.T) {
+		deletedAt := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+		trashed := *testStory
+		trashed.DeletedAt = &deletedAt
+		mockStoryService.On("GetTrash", testUserID).Return([]*model.Story{&trashed}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/stories/trash", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.GetTrash(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response TrashResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.Len(t, response.Stories, 1)
+		require.NotNil(t, response.Stories[0].DeletedAt)
+		assert.True(t, deletedAt.Equal(*response.Stories[0].DeletedAt))
+
+		mockStoryService.AssertExpectations(t)
+	})
+
+	newRestoreContext := func() (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/stories/:id/restore")
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.Itoa(testStoryID))
+		return c, rec
+	}
+
+	t.Run("success: should restore a trashed story", func(t *testing.T) {
+		mockStoryService.On("RestoreStory", testStoryID, testUserID).Return(testStory, nil).Once()
+
+		c, rec := newRestoreContext()
+		require.NoError(t, h.RestoreStory(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		mockStoryService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 404 when the story is not in the trash", func(t *testing.T) {
+		mockStoryService.On("RestoreStory", testStoryID, testUserID).Return(nil, service.ErrStoryNotFound).Once()
+
+		c, rec := newRestoreContext()
+		require.NoError(t, h.RestoreStory(c))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		mockStoryService.AssertExpectations(t)
+	})
+}
+
+func TestStoryHandler_BulkUpdateStories(t *testing.T) {
+	mockStoryService, e, token := setupTestHandler(t)
+	h := NewStoryHandler(mockStoryService, new(MockTemplateService))
+
+	newBulkContext := func(body string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPost, "/stories/bulk", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		return c, rec
+	}
+
+	t.Run("success: should return per-item results", func(t *testing.T) {
+		op := repository.BulkOperation{Action: repository.BulkActionDelete, StoryIDs: []int{10, 11, 12}}
+		results := []*model.BulkStoryResult{
+			{StoryID: 10, Status: model.BulkStatusOK},
+			{StoryID: 11, Status: model.BulkStatusSkipped, Reason: "favorite"},
+			{StoryID: 12, Status: model.BulkStatusNotFound},
+		}
+		mockStoryService.On("BulkUpdateStories", testUserID, op).Return(results, nil).Once()
+
+		c, rec := newBulkContext(`{"ids": [10, 11, 12], "action": "delete"}`)
+		require.NoError(t, h.BulkUpdateStories(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response BulkStoriesResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, results, response.Results)
+
+		mockStoryService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should require tag_id for the tag action", func(t *testing.T) {
+		c, rec := newBulkContext(`{"ids": [10], "action": "tag"}`)
+		require.NoError(t, h.BulkUpdateStories(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("fail: should return 404 when the shelf is not found", func(t *testing.T) {
+		op := repository.BulkOperation{Action: repository.BulkActionMoveToShelf, StoryIDs: []int{10}, ShelfID: 4}
+		mockStoryService.On("BulkUpdateStories", testUserID, op).Return(nil, service.ErrShelfNotFound).Once()
+
+		c, rec := newBulkContext(`{"ids": [10], "action": "move_to_shelf", "shelf_id": 4}`)
+		require.NoError(t, h.BulkUpdateStories(c))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		mockStoryService.AssertExpectations(t)
+	})
+}
+
+func TestStoryHandler_GetDuplicateClusters(t *testing.T) {
+	mockStoryService, e, token := setupTestHandler(t)
+	h := NewStoryHandler(mockStoryService, new(MockTemplateService))
+
+	newContext := func() (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodGet, "/stories/duplicates", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		return c, rec
+	}
+
+	t.Run("success: should return clusters without the fingerprints", func(t *testing.T) {
+		clusters := []*model.DuplicateCluster{{
+			Stories: []*model.StoryFingerprint{
+				{ID: 10, Title: "Harbor", Simhash: 42},
+				{ID: 11, Title: "Harbor again", Simhash: 43},
+			},
+			MinSimilarity: 0.984375,
+		}}
+		mockStoryService.On("GetDuplicateClusters", testUserID).Return(clusters, nil).Once()
+
+		c, rec := newContext()
+		require.NoError(t, h.GetDuplicateClusters(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response map[string][]map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.Len(t, response["clusters"], 1)
+		assert.Equal(t, 0.984375, response["clusters"][0]["min_similarity"])
+		assert.NotContains(t, rec.Body.String(), "simhash")
+
+		mockStoryService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 500 on a database error", func(t *testing.T) {
+		mockStoryService.On("GetDuplicateClusters", testUserID).Return(nil, fmt.Errorf("db error")).Once()
+
+		c, rec := newContext()
+		require.NoError(t, h.GetDuplicateClusters(c))
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+		mockStoryService.AssertExpectations(t)
+	})
+}
+
+func TestStoryHandler_FavoriteStory(t *testing.T) {
+	mockStoryService, e, token := setupTestHandler(t)
+	h := NewStoryHandler(mockStoryService, new(MockTemplateService))
+
+	newContext := func(method string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(method, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/stories/:id/favorite")
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.Itoa(testStoryID))
+		return c, rec
+	}
+
+	t.Run("success: should add the story to favorites", func(t *testing.T) {
+		mockStoryService.On("SetFavorite", testStoryID, testUserID, true).Return(nil).Once()
+
+		c, rec := newContext(http.MethodPut)
+		require.NoError(t, h.FavoriteStory(c))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+
+		mockStoryService.AssertExpectations(t)
+	})
+
+	t.Run("success: should remove the story from favorites", func(t *testing.T) {
+		mockStoryService.On("SetFavorite", testStoryID, testUserID, false).Return(nil).Once()
+
+		c, rec := newContext(http.MethodDelete)
+		require.NoError(t, h.UnfavoriteStory(c))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+
+		mockStoryService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 404 for a story of another user", func(t *testing.T) {
+		mockStoryService.On("SetFavorite", testStoryID, testUserID, true).Return(service.ErrStoryNotFound).Once()
+
+		c, rec := newContext(http.MethodPut)
+		require.NoError(t, h.FavoriteStory(c))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		mockStoryService.AssertExpectations(t)
+	})
 }
 
 func TestStoryHandler_GenerateStory(t *testing.T) {
 	mockStoryService, e, token := setupTestHandler(t)
-	h := NewStoryHandler(mockStoryService)
+	h := NewStoryHandler(mockStoryService, new(MockTemplateService))
 
 	t.Run("success: should generate and save a story", func(t *testing.T) {
 		prompt := "A story abount Go"
@@ -135,7 +512,7 @@ func TestStoryHandler_GenerateStory(t *testing.T) {
 		generatedStory := *testStory
 		generatedStory.Title = prompt
 
-		mockStoryService.On("GenerateStory", testUserID, prompt).Return(&generatedStory, nil).Once()
+		mockStoryService.On("GenerateStory", testUserID, service.GenerateStoryInput{Prompt: prompt}).Return(&generatedStory, nil).Once()
 
 		req := httptest.NewRequest(http.MethodPost, "/stories", strings.NewReader(requestBody))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -157,7 +534,7 @@ func TestStoryHandler_GenerateStory(t *testing.T) {
 		prompt := "A story that should fail"
 		requestBody := fmt.Sprintf(`{"prompt": "%s"}`, prompt)
 
-		mockStoryService.On("GenerateStory", testUserID, prompt).Return(nil, service.ErrGenerationLimitExceeded).Once()
+		mockStoryService.On("GenerateStory", testUserID, service.GenerateStoryInput{Prompt: prompt}).Return(nil, service.ErrGenerationLimitExceeded).Once()
 
 		req := httptest.NewRequest(http.MethodPost, "/stories", strings.NewReader(requestBody))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -177,9 +554,55 @@ func TestStoryHandler_GenerateStory(t *testing.T) {
 	})
 }
 
+func TestStoryHandler_GenerateStory_WithTemplate(t *testing.T) {
+	mockStoryService, e, token := setupTestHandler(t)
+	mockTemplateService := new(MockTemplateService)
+	h := NewStoryHandler(mockStoryService, mockTemplateService)
+
+	t.Run("success: should render the template and apply its defaults", func(t *testing.T) {
+		variables := map[string]string{"topic": "space"}
+		rendered := &service.RenderedTemplate{Prompt: "Explain space", Level: "A2", WordCount: 300}
+		mockTemplateService.On("RenderTemplate", 3, testUserID, variables).Return(rendered, nil).Once()
+
+		// リクエストで指定した word_count はテンプレートの既定値より優先される
+		expectedInput := service.GenerateStoryInput{Prompt: "Explain space", Level: "A2", WordCount: 150}
+		mockStoryService.On("GenerateStory", testUserID, expectedInput).Return(testStory, nil).Once()
+
+		requestBody := `{"template_id": 3, "variables": {"topic": "space"}, "word_count": 150}`
+		req := httptest.NewRequest(http.MethodPost, "/stories", strings.NewReader(requestBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.GenerateStory(c))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		mockTemplateService.AssertExpectations(t)
+		mockStoryService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 400 when variables are missing", func(t *testing.T) {
+		mockTemplateService.On("RenderTemplate", 3, testUserID, map[string]string(nil)).
+			Return(nil, fmt.Errorf("%w: topic", service.ErrMissingTemplateVariables)).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/stories", strings.NewReader(`{"template_id": 3}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.GenerateStory(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "topic")
+
+		mockTemplateService.AssertExpectations(t)
+	})
+}
+
 func TestStoryHandler_DeleteStory(t *testing.T) {
 	mockStoryService, e, token := setupTestHandler(t)
-	h := NewStoryHandler(mockStoryService)
+	h := NewStoryHandler(mockStoryService, new(MockTemplateService))
 
 	t.Run("success: should delete a story", func(t *testing.T) {
 		mockStoryService.On("DeleteStory", testStoryID, testUserID).Return(nil).Once()
@@ -200,7 +623,7 @@ func TestStoryHandler_DeleteStory(t *testing.T) {
 
 func TestStoryHandler_UpdateStory(t *testing.T) {
 	mockStoryService, e, token := setupTestHandler(t)
-	h := NewStoryHandler(mockStoryService)
+	h := NewStoryHandler(mockStoryService, new(MockTemplateService))
 
 	t.Run("success: should update a story title", func(t *testing.T) {
 		newTitle := "Updated Story Title"
@@ -231,11 +654,102 @@ func TestStoryHandler_UpdateStory(t *testing.T) {
 
 		mockStoryService.AssertExpectations(t)
 	})
+
+	newUpdateContext := func(body string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/stories/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.Itoa(testStoryID))
+		return c, rec
+	}
+
+	t.Run("success: should update the content and return the recounted word count", func(t *testing.T) {
+		content := "A fixed sentence without typos."
+		updatedStory := *testStory
+		updatedStory.Content = content
+		updatedStory.WordCount = 5
+		mockStoryService.On("UpdateStoryContent", testStoryID, testUserID, (*string)(nil), content).Return(&updatedStory, nil).Once()
+
+		c, rec := newUpdateContext(`{"content": "A fixed sentence without typos."}`)
+		require.NoError(t, h.UpdateStory(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var receivedStory model.Story
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &receivedStory))
+		assert.Equal(t, 5, receivedStory.WordCount)
+
+		mockStoryService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 400 when neither title nor content is given", func(t *testing.T) {
+		c, rec := newUpdateContext(`{}`)
+		require.NoError(t, h.UpdateStory(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestStoryHandler_Revisions(t *testing.T) {
+	mockStoryService, e, token := setupTestHandler(t)
+	h := NewStoryHandler(mockStoryService, new(MockTemplateService))
+
+	newRevisionContext := func(method, revision string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(method, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/stories/:id/revisions/:revision")
+		c.SetParamNames("id", "revision")
+		c.SetParamValues(strconv.Itoa(testStoryID), revision)
+		return c, rec
+	}
+
+	t.Run("success: should return the revision with its diff", func(t *testing.T) {
+		diff := &service.RevisionDiff{
+			Revision: &model.StoryRevision{StoryID: testStoryID, RevisionNumber: 2, Content: "Old text."},
+			Changes: []service.DiffChange{
+				{Op: service.DiffDelete, Text: "Old"},
+				{Op: service.DiffInsert, Text: "New"},
+				{Op: service.DiffEqual, Text: " text."},
+			},
+		}
+		mockStoryService.On("GetRevisionDiff", testStoryID, testUserID, 2).Return(diff, nil).Once()
+
+		c, rec := newRevisionContext(http.MethodGet, "2")
+		require.NoError(t, h.GetRevisionDiff(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response RevisionDiffResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, 2, response.Revision.RevisionNumber)
+		assert.Equal(t, diff.Changes, response.Changes)
+
+		mockStoryService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 400 for an invalid revision number", func(t *testing.T) {
+		c, rec := newRevisionContext(http.MethodPost, "latest")
+		require.NoError(t, h.RevertStory(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("fail: should return 404 when reverting to an unknown revision", func(t *testing.T) {
+		mockStoryService.On("RevertStory", testStoryID, testUserID, 9).Return(nil, service.ErrRevisionNotFound).Once()
+
+		c, rec := newRevisionContext(http.MethodPost, "9")
+		require.NoError(t, h.RevertStory(c))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		mockStoryService.AssertExpectations(t)
+	})
 }
 
 func TestStoryHandler_MarkStoryAsRead(t *testing.T) {
 	mockStoryService, e, token := setupTestHandler(t)
-	h := NewStoryHandler(mockStoryService)
+	h := NewStoryHandler(mockStoryService, new(MockTemplateService))
 
 	t.Run("success: should mark a story as read", func(t *testing.T) {
 		mockStoryService.On("MarkStoryAsRead", testStoryID, testUserID).Return(nil).Once()
@@ -276,3 +790,199 @@ func TestStoryHandler_MarkStoryAsRead(t *testing.T) {
 		mockStoryService.AssertExpectations(t)
 	})
 }
+
+func TestStoryHandler_ContinueStory(t *testing.T) {
+	mockStoryService, e, token := setupTestHandler(t)
+	h := NewStoryHandler(mockStoryService, new(MockTemplateService))
+
+	t.Run("success: should generate the next chapter", func(t *testing.T) {
+		seriesID, chapterNumber := 1, 2
+		nextChapter := *testStory
+		nextChapter.ID = testStoryID + 1
+		nextChapter.SeriesID = &seriesID
+		nextChapter.ChapterNumber = &chapterNumber
+
+		mockStoryService.On("ContinueStory", testStoryID, testUserID).Return(&nextChapter, nil).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/stories/:id/continue")
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.Itoa(testStoryID))
+
+		require.NoError(t, h.ContinueStory(c))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		var responseBody model.Story
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &responseBody))
+		require.NotNil(t, responseBody.ChapterNumber)
+		assert.Equal(t, chapterNumber, *responseBody.ChapterNumber)
+
+		mockStoryService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 429 Too Many Requests if limit exceeded", func(t *testing.T) {
+		mockStoryService.On("ContinueStory", testStoryID, testUserID).Return(nil, service.ErrGenerationLimitExceeded).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/stories/:id/continue")
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.Itoa(testStoryID))
+
+		require.NoError(t, h.ContinueStory(c))
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+		mockStoryService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 409 Conflict if the chapter was generated concurrently", func(t *testing.T) {
+		mockStoryService.On("ContinueStory", testStoryID, testUserID).Return(nil, service.ErrChapterAlreadyExists).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/stories/:id/continue")
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.Itoa(testStoryID))
+
+		require.NoError(t, h.ContinueStory(c))
+		assert.Equal(t, http.StatusConflict, rec.Code)
+
+		mockStoryService.AssertExpectations(t)
+	})
+}
+
+func TestStoryHandler_RewriteStory(t *testing.T) {
+	mockStoryService, e, token := setupTestHandler(t)
+	h := NewStoryHandler(mockStoryService, new(MockTemplateService))
+
+	t.Run("success: should create a rewritten story", func(t *testing.T) {
+		level := "A2"
+		parentID := testStoryID
+		rewritten := *testStory
+		rewritten.ID = testStoryID + 1
+		rewritten.Level = &level
+		rewritten.ParentStoryID = &parentID
+
+		mockStoryService.On("RewriteStory", testStoryID, testUserID, level).Return(&rewritten, nil).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"level": "A2"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/stories/:id/rewrite")
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.Itoa(testStoryID))
+
+		require.NoError(t, h.RewriteStory(c))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		var responseBody model.Story
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &responseBody))
+		require.NotNil(t, responseBody.ParentStoryID)
+		assert.Equal(t, testStoryID, *responseBody.ParentStoryID)
+
+		mockStoryService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should reject an unknown level", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"level": "Z9"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/stories/:id/rewrite")
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.Itoa(testStoryID))
+
+		err := h.RewriteStory(c)
+
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+		mockStoryService.AssertNotCalled(t, "RewriteStory", testStoryID, testUserID, "Z9")
+	})
+}
+
+func TestStoryHandler_TranslateStory(t *testing.T) {
+	mockStoryService, e, token := setupTestHandler(t)
+	h := NewStoryHandler(mockStoryService, new(MockTemplateService))
+
+	t.Run("success: should return aligned paragraphs", func(t *testing.T) {
+		pairs := []service.ParallelParagraph{{English: "Hello.", Japanese: "こんにちは。"}}
+		mockStoryService.On("TranslateStory", testStoryID, testUserID).Return(pairs, nil).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/stories/:id/translation")
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.Itoa(testStoryID))
+
+		require.NoError(t, h.TranslateStory(c))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		var response TranslationResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.Len(t, response.ParallelText, 1)
+		assert.Equal(t, "こんにちは。", response.ParallelText[0].Japanese)
+
+		mockStoryService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 429 Too Many Requests if limit exceeded", func(t *testing.T) {
+		mockStoryService.On("TranslateStory", testStoryID, testUserID).Return(nil, service.ErrGenerationLimitExceeded).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/stories/:id/translation")
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.Itoa(testStoryID))
+
+		require.NoError(t, h.TranslateStory(c))
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+		mockStoryService.AssertExpectations(t)
+	})
+}
+
+func TestStoryHandler_GenerateStory_WithTranslation(t *testing.T) {
+	mockStoryService, e, token := setupTestHandler(t)
+	h := NewStoryHandler(mockStoryService, new(MockTemplateService))
+
+	t.Run("success: should still return the story when translation fails", func(t *testing.T) {
+		prompt := "A bilingual story"
+		generatedStory := *testStory
+		generatedStory.Title = prompt
+
+		mockStoryService.On("GenerateStory", testUserID, service.GenerateStoryInput{Prompt: prompt}).Return(&generatedStory, nil).Once()
+		mockStoryService.On("TranslateStory", testStoryID, testUserID).Return(nil, fmt.Errorf("llm error")).Once()
+
+		requestBody := fmt.Sprintf(`{"prompt": "%s", "with_translation": true}`, prompt)
+		req := httptest.NewRequest(http.MethodPost, "/stories", strings.NewReader(requestBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.GenerateStory(c))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		var response GenerateStoryResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, prompt, response.Title)
+		assert.Empty(t, response.ParallelText)
+
+		mockStoryService.AssertExpectations(t)
+	})
+}
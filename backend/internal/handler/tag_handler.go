@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
+	"github.com/shuheikomatsuki/readoku/backend/internal/service"
+)
+
+type ITagHandler interface {
+	GetTags(e echo.Context) error
+	CreateTag(e echo.Context) error
+	RenameTag(e echo.Context) error
+	DeleteTag(e echo.Context) error
+	AttachTag(e echo.Context) error
+	DetachTag(e echo.Context) error
+}
+
+type TagHandler struct {
+	TagService service.ITagService
+}
+
+type TagRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=50"`
+}
+
+type GetTagsResponse struct {
+	Tags []*model.Tag `json:"tags"`
+}
+
+func NewTagHandler(tagService service.ITagService) ITagHandler {
+	return &TagHandler{
+		TagService: tagService,
+	}
+}
+
+func (h *TagHandler) GetTags(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	tags, err := h.TagService.ListTags(userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "database error"})
+	}
+
+	return c.JSON(http.StatusOK, GetTagsResponse{Tags: tags})
+}
+
+func (h *TagHandler) CreateTag(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	req, err := bindTagRequest(c)
+	if err != nil {
+		return err
+	}
+
+	tag, err := h.TagService.CreateTag(userID, req.Name)
+	if err != nil {
+		if errors.Is(err, repository.ErrTagAlreadyExists) {
+			return c.JSON(http.StatusConflict, map[string]string{"error": "tag already exists"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to create tag"})
+	}
+
+	return c.JSON(http.StatusCreated, tag)
+}
+
+func (h *TagHandler) RenameTag(c echo.Context) error {
+	tagID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid tag id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	req, err := bindTagRequest(c)
+	if err != nil {
+		return err
+	}
+
+	tag, err := h.TagService.RenameTag(tagID, userID, req.Name)
+	if err != nil {
+		if errors.Is(err, service.ErrTagNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "tag not found"})
+		}
+		if errors.Is(err, repository.ErrTagAlreadyExists) {
+			return c.JSON(http.StatusConflict, map[string]string{"error": "tag already exists"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to rename tag"})
+	}
+
+	return c.JSON(http.StatusOK, tag)
+}
+
+func (h *TagHandler) DeleteTag(c echo.Context) error {
+	tagID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid tag id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	if err := h.TagService.DeleteTag(tagID, userID); err != nil {
+		if errors.Is(err, service.ErrTagNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "tag not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to delete tag"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *TagHandler) AttachTag(c echo.Context) error {
+	return h.updateStoryTag(c, h.TagService.AttachTag, "failed to attach tag")
+}
+
+func (h *TagHandler) DetachTag(c echo.Context) error {
+	return h.updateStoryTag(c, h.TagService.DetachTag, "failed to detach tag")
+}
+
+// updateStoryTag は /stories/:id/tags/:tag_id に対するタグの付け外しを共通で処理する
+func (h *TagHandler) updateStoryTag(c echo.Context, update func(storyID, tagID, userID int) error, failure string) error {
+	storyID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid story id"})
+	}
+	tagID, err := strconv.Atoi(c.Param("tag_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid tag id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	if err := update(storyID, tagID, userID); err != nil {
+		if errors.Is(err, service.ErrStoryNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "story not found"})
+		}
+		if errors.Is(err, service.ErrTagNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "tag not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": failure})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// bindTagRequest はリクエストを読み取り、前後の空白を除いたタグ名を検証する
+func bindTagRequest(c echo.Context) (*TagRequest, error) {
+	var req TagRequest
+	if err := c.Bind(&req); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, ValidationErrorResponse{Error: "invalid request body"})
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if err := c.Validate(&req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
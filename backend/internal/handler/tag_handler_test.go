@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
+	"github.com/shuheikomatsuki/readoku/backend/internal/service"
+)
+
+func TestTagHandler_CreateTag(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockTagService := new(MockTagService)
+	h := NewTagHandler(mockTagService)
+
+	t.Run("success: should create a tag with a trimmed name", func(t *testing.T) {
+		created := &model.Tag{ID: 1, UserID: testUserID, Name: "exam prep"}
+		mockTagService.On("CreateTag", testUserID, "exam prep").Return(created, nil).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/tags", strings.NewReader(`{"name": "  exam prep "}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.CreateTag(c))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		var response model.Tag
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "exam prep", response.Name)
+
+		mockTagService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 409 for a duplicate name", func(t *testing.T) {
+		mockTagService.On("CreateTag", testUserID, "travel").Return(nil, repository.ErrTagAlreadyExists).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/tags", strings.NewReader(`{"name": "travel"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.CreateTag(c))
+		assert.Equal(t, http.StatusConflict, rec.Code)
+
+		mockTagService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should reject a blank name", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/tags", strings.NewReader(`{"name": "   "}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		err := h.CreateTag(c)
+
+		require.Error(t, err)
+		mockTagService.AssertNotCalled(t, "CreateTag", testUserID, "")
+	})
+}
+
+func TestTagHandler_RenameTag(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockTagService := new(MockTagService)
+	h := NewTagHandler(mockTagService)
+
+	t.Run("fail: should return 404 for an unknown tag", func(t *testing.T) {
+		mockTagService.On("RenameTag", 5, testUserID, "science").Return(nil, service.ErrTagNotFound).Once()
+
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"name": "science"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/tags/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.Itoa(5))
+
+		require.NoError(t, h.RenameTag(c))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		mockTagService.AssertExpectations(t)
+	})
+}
+
+func TestTagHandler_AttachTag(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockTagService := new(MockTagService)
+	h := NewTagHandler(mockTagService)
+
+	newContext := func(tagID string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPut, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/stories/:id/tags/:tag_id")
+		c.SetParamNames("id", "tag_id")
+		c.SetParamValues(strconv.Itoa(testStoryID), tagID)
+		return c, rec
+	}
+
+	t.Run("success: should attach the tag", func(t *testing.T) {
+		mockTagService.On("AttachTag", testStoryID, 3, testUserID).Return(nil).Once()
+
+		c, rec := newContext("3")
+		require.NoError(t, h.AttachTag(c))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+
+		mockTagService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 404 for a story of another user", func(t *testing.T) {
+		mockTagService.On("AttachTag", testStoryID, 3, testUserID).Return(service.ErrStoryNotFound).Once()
+
+		c, rec := newContext("3")
+		require.NoError(t, h.AttachTag(c))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		mockTagService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should reject an invalid tag id", func(t *testing.T) {
+		c, rec := newContext("abc")
+		require.NoError(t, h.AttachTag(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestTagHandler_CreateTag_InvalidBody(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	h := NewTagHandler(new(MockTagService))
+
+	req := httptest.NewRequest(http.MethodPost, "/tags", strings.NewReader(`{"name": `))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user", token)
+
+	err := h.CreateTag(c)
+
+	var httpErr *echo.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/service"
+)
+
+type ITemplateHandler interface {
+	GetTemplates(e echo.Context) error
+	CreateTemplate(e echo.Context) error
+	UpdateTemplate(e echo.Context) error
+	DeleteTemplate(e echo.Context) error
+}
+
+type TemplateHandler struct {
+	TemplateService service.ITemplateService
+}
+
+type TemplateRequest struct {
+	Name             string  `json:"name" validate:"required,min=1,max=100"`
+	Body             string  `json:"body" validate:"required,min=1,max=2000"`
+	DefaultLevel     *string `json:"default_level" validate:"omitempty,oneof=A1 A2 B1 B2 C1 C2"`
+	DefaultWordCount *int    `json:"default_word_count" validate:"omitempty,min=50,max=2000"`
+	Global           bool    `json:"global"`
+}
+
+type TemplateResponse struct {
+	*model.PromptTemplate
+	Placeholders []string `json:"placeholders"`
+}
+
+type GetTemplatesResponse struct {
+	Templates []TemplateResponse `json:"templates"`
+}
+
+func NewTemplateHandler(templateService service.ITemplateService) ITemplateHandler {
+	return &TemplateHandler{
+		TemplateService: templateService,
+	}
+}
+
+func newTemplateResponse(template *model.PromptTemplate) TemplateResponse {
+	return TemplateResponse{
+		PromptTemplate: template,
+		Placeholders:   service.TemplatePlaceholders(template.Body),
+	}
+}
+
+func (h *TemplateHandler) GetTemplates(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	templates, err := h.TemplateService.ListTemplates(userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "database error"})
+	}
+
+	res := GetTemplatesResponse{Templates: make([]TemplateResponse, 0, len(templates))}
+	for _, t := range templates {
+		res.Templates = append(res.Templates, newTemplateResponse(t))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h *TemplateHandler) CreateTemplate(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	var req TemplateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	template, err := h.TemplateService.CreateTemplate(userID, toTemplateInput(req))
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "only admins can publish global presets"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to create template"})
+	}
+
+	return c.JSON(http.StatusCreated, newTemplateResponse(template))
+}
+
+func (h *TemplateHandler) UpdateTemplate(c echo.Context) error {
+	templateID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid template id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	var req TemplateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	template, err := h.TemplateService.UpdateTemplate(templateID, userID, toTemplateInput(req))
+	if err != nil {
+		if errors.Is(err, service.ErrTemplateNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "template not found"})
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "only admins can edit global presets"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to update template"})
+	}
+
+	return c.JSON(http.StatusOK, newTemplateResponse(template))
+}
+
+func (h *TemplateHandler) DeleteTemplate(c echo.Context) error {
+	templateID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid template id"})
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	if err := h.TemplateService.DeleteTemplate(templateID, userID); err != nil {
+		if errors.Is(err, service.ErrTemplateNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "template not found"})
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "only admins can delete global presets"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to delete template"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func toTemplateInput(req TemplateRequest) service.TemplateInput {
+	return service.TemplateInput{
+		Name:             req.Name,
+		Body:             req.Body,
+		DefaultLevel:     req.DefaultLevel,
+		DefaultWordCount: req.DefaultWordCount,
+		Global:           req.Global,
+	}
+}
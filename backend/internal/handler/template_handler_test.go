@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/service"
+)
+
+func TestTemplateHandler_CreateTemplate(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockTemplateService := new(MockTemplateService)
+	h := NewTemplateHandler(mockTemplateService)
+
+	t.Run("success: should create a template and list its placeholders", func(t *testing.T) {
+		input := service.TemplateInput{Name: "News", Body: "News about {{topic}} in {{place}}"}
+		ownerID := testUserID
+		created := &model.PromptTemplate{ID: 1, UserID: &ownerID, Name: input.Name, Body: input.Body}
+		mockTemplateService.On("CreateTemplate", testUserID, input).Return(created, nil).Once()
+
+		requestBody := `{"name": "News", "body": "News about {{topic}} in {{place}}"}`
+		req := httptest.NewRequest(http.MethodPost, "/templates", strings.NewReader(requestBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.CreateTemplate(c))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		var response TemplateResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, []string{"topic", "place"}, response.Placeholders)
+
+		mockTemplateService.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return 403 when a non-admin publishes a preset", func(t *testing.T) {
+		input := service.TemplateInput{Name: "Preset", Body: "{{topic}}", Global: true}
+		mockTemplateService.On("CreateTemplate", testUserID, input).Return(nil, service.ErrForbidden).Once()
+
+		requestBody := `{"name": "Preset", "body": "{{topic}}", "global": true}`
+		req := httptest.NewRequest(http.MethodPost, "/templates", strings.NewReader(requestBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+
+		require.NoError(t, h.CreateTemplate(c))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+
+		mockTemplateService.AssertExpectations(t)
+	})
+}
+
+func TestTemplateHandler_DeleteTemplate(t *testing.T) {
+	_, e, token := setupTestHandler(t)
+	mockTemplateService := new(MockTemplateService)
+	h := NewTemplateHandler(mockTemplateService)
+
+	t.Run("fail: should return 404 for an unknown template", func(t *testing.T) {
+		mockTemplateService.On("DeleteTemplate", 5, testUserID).Return(service.ErrTemplateNotFound).Once()
+
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", token)
+		c.SetPath("/templates/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.Itoa(5))
+
+		require.NoError(t, h.DeleteTemplate(c))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		mockTemplateService.AssertExpectations(t)
+	})
+}
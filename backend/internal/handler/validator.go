@@ -34,6 +34,8 @@ func (cv *CustomValidator) Validate(i interface{}) error {
 				errorMessage = fmt.Sprintf("%s must be at least %s characters long", fieldError.Field(), fieldError.Param())
 			case "max":
 				errorMessage = fmt.Sprintf("%s must be at most %s characters long", fieldError.Field(), fieldError.Param())
+			case "oneof":
+				errorMessage = fmt.Sprintf("%s must be one of [%s]", fieldError.Field(), fieldError.Param())
 			default:
 				errorMessage = fmt.Sprintf("validation failed on field %s with rule %s", fieldError.Field(), fieldError.Tag())
 			}
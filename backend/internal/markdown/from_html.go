@@ -0,0 +1,231 @@
+// Package markdown はストーリーの本文として扱う Markdown と HTML の相互変換を行う
+package markdown
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// skippedElements は本文に含めない要素。画像・スクリプト・埋め込みや目次は読み物として扱わない
+var skippedElements = map[atom.Atom]bool{
+	atom.Head:     true,
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Img:      true,
+	atom.Picture:  true,
+	atom.Svg:      true,
+	atom.Math:     true,
+	atom.Video:    true,
+	atom.Audio:    true,
+	atom.Iframe:   true,
+	atom.Object:   true,
+	atom.Embed:    true,
+	atom.Canvas:   true,
+	atom.Form:     true,
+	atom.Button:   true,
+	atom.Input:    true,
+	atom.Select:   true,
+	atom.Textarea: true,
+	atom.Nav:      true,
+}
+
+// blockElements は段落を区切る要素。これら以外は段落内のインライン要素として扱う
+var blockElements = map[atom.Atom]bool{
+	atom.Html: true, atom.Body: true, atom.Main: true, atom.Article: true, atom.Section: true,
+	atom.Header: true, atom.Footer: true, atom.Aside: true, atom.Div: true, atom.Address: true,
+	atom.P: true, atom.Blockquote: true, atom.Pre: true, atom.Hr: true, atom.Figure: true, atom.Figcaption: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Ul: true, atom.Ol: true, atom.Li: true, atom.Dl: true, atom.Dt: true, atom.Dd: true,
+	atom.Table: true, atom.Thead: true, atom.Tbody: true, atom.Tfoot: true, atom.Tr: true, atom.Caption: true,
+}
+
+var headingLevels = map[atom.Atom]int{
+	atom.H1: 1, atom.H2: 2, atom.H3: 3, atom.H4: 4, atom.H5: 5, atom.H6: 6,
+}
+
+// FromHTML は HTML / XHTML の本文を Markdown に変換する。見出し・段落・引用・リスト・強調のみを残し、
+// 画像やスクリプトなどは取り除く。リンクはテキストだけを残す
+func FromHTML(r io.Reader) (string, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse html: %w", err)
+	}
+	return FromNode(doc), nil
+}
+
+// FromNode は解析済みの HTML の要素 (本文の部分だけを抜き出したものなど) を Markdown に変換する
+func FromNode(n *html.Node) string {
+	c := &converter{}
+	if n.Type == html.ElementNode && blockElements[n.DataAtom] {
+		c.block(n, "")
+	} else {
+		c.blockChildren(n, "")
+	}
+	return strings.Join(c.blocks, "\n\n")
+}
+
+type converter struct {
+	blocks []string
+}
+
+// blockChildren は子要素を順に処理し、連続するインライン要素を 1 つの段落にまとめる
+func (c *converter) blockChildren(n *html.Node, prefix string) {
+	var paragraph strings.Builder
+	flush := func() {
+		c.addBlock(prefix, paragraph.String())
+		paragraph.Reset()
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && blockElements[child.DataAtom] && !skippedElements[child.DataAtom] {
+			flush()
+			c.block(child, prefix)
+			continue
+		}
+		paragraph.WriteString(c.inline(child))
+	}
+	flush()
+}
+
+func (c *converter) block(n *html.Node, prefix string) {
+	if skippedElements[n.DataAtom] {
+		return
+	}
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		text := collapseSpace(c.inlineChildren(n))
+		if text != "" {
+			c.addBlock(prefix, strings.Repeat("#", headingLevels[n.DataAtom])+" "+text)
+		}
+	case atom.Blockquote:
+		c.blockChildren(n, prefix+"> ")
+	case atom.Ul, atom.Ol:
+		number := 0
+		for li := n.FirstChild; li != nil; li = li.NextSibling {
+			if li.Type != html.ElementNode || li.DataAtom != atom.Li {
+				continue
+			}
+			number++
+			marker := "- "
+			if n.DataAtom == atom.Ol {
+				marker = fmt.Sprintf("%d. ", number)
+			}
+			c.addBlock(prefix, marker+collapseSpace(c.inlineChildren(li)))
+		}
+	case atom.Pre:
+		text := strings.Trim(textContent(n), "\n")
+		if strings.TrimSpace(text) != "" {
+			c.addBlock(prefix, "```\n"+text+"\n```")
+		}
+	case atom.Hr:
+		c.addBlock(prefix, "---")
+	case atom.Tr:
+		var cells []string
+		for cell := n.FirstChild; cell != nil; cell = cell.NextSibling {
+			if cell.Type == html.ElementNode && (cell.DataAtom == atom.Td || cell.DataAtom == atom.Th) {
+				if text := collapseSpace(c.inlineChildren(cell)); text != "" {
+					cells = append(cells, text)
+				}
+			}
+		}
+		c.addBlock(prefix, strings.Join(cells, " | "))
+	default:
+		c.blockChildren(n, prefix)
+	}
+}
+
+// inline は段落内の要素をテキストに変換する。ソース上の改行は空白として扱い、<br> のみ "\n" として残す
+func (c *converter) inline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return ' '
+			}
+			return r
+		}, n.Data)
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	if skippedElements[n.DataAtom] {
+		return ""
+	}
+	switch n.DataAtom {
+	case atom.Br:
+		return "\n"
+	case atom.Em, atom.I, atom.Cite:
+		return wrap("*", c.inlineChildren(n))
+	case atom.Strong, atom.B:
+		return wrap("**", c.inlineChildren(n))
+	case atom.Code:
+		return wrap("`", c.inlineChildren(n))
+	}
+	if blockElements[n.DataAtom] {
+		return " " + c.inlineChildren(n) + " "
+	}
+	return c.inlineChildren(n)
+}
+
+func (c *converter) inlineChildren(n *html.Node) string {
+	var b strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		b.WriteString(c.inline(child))
+	}
+	return b.String()
+}
+
+// addBlock は空白を整えた段落を追加する。prefix は引用の "> " など各行の先頭に付ける記号
+func (c *converter) addBlock(prefix, text string) {
+	var lines []string
+	if strings.HasPrefix(text, "```") {
+		lines = strings.Split(text, "\n")
+	} else {
+		for _, line := range strings.Split(text, "\n") {
+			if line = collapseSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+	c.blocks = append(c.blocks, strings.Join(lines, "\n"))
+}
+
+// wrap は強調の記号で囲む。記号の内側に空白があると Markdown として解釈されないため外側に出す
+func wrap(marker, s string) string {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return s
+	}
+	leading := s[:strings.Index(s, trimmed)]
+	trailing := s[len(leading)+len(trimmed):]
+	return leading + marker + trimmed + marker + trailing
+}
+
+// collapseSpace は連続する空白 (改行・ノーブレークスペースを含む) を 1 つの半角スペースにまとめる
+func collapseSpace(s string) string {
+	return strings.Join(strings.FieldsFunc(s, unicode.IsSpace), " ")
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		b.WriteString(textContent(child))
+	}
+	return b.String()
+}
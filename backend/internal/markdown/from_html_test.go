@@ -0,0 +1,53 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromHTML(t *testing.T) {
+	testCases := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{
+			name: "headings, paragraphs and emphasis",
+			html: `<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml"><head><title>Ch 1</title><style>p { color: red; }</style></head>
+<body>
+  <h1>Chapter  One</h1>
+  <p>It was a <em>very</em> cold
+     morning.</p>
+  <p>Tom said, <strong>"Hello!" </strong>and left.</p>
+</body></html>`,
+			expected: "# Chapter One\n\nIt was a *very* cold morning.\n\nTom said, **\"Hello!\"** and left.",
+		},
+		{
+			name:     "images, scripts and navigation are removed",
+			html:     `<body><nav><a href="#c1">Contents</a></nav><p>Text<img src="a.png" alt="A picture"/> here.</p><script>alert(1)</script><svg><text>x</text></svg></body>`,
+			expected: "Text here.",
+		},
+		{
+			name:     "lists, quotes and line breaks",
+			html:     `<div>Intro<ul><li>one</li><li>two</li></ul><ol><li>first</li></ol><blockquote><p>Quoted</p><p>Twice</p></blockquote><p>Line 1<br/>Line 2</p><hr/></div>`,
+			expected: "Intro\n\n- one\n\n- two\n\n1. first\n\n> Quoted\n\n> Twice\n\nLine 1\nLine 2\n\n---",
+		},
+		{
+			name:     "non-breaking spaces and links",
+			html:     `<p>See&nbsp;&nbsp;<a href="ch2.xhtml">the next chapter</a>.</p>`,
+			expected: "See the next chapter.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := FromHTML(strings.NewReader(tc.html))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	headingPattern       = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	unorderedItem        = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	orderedItem          = regexp.MustCompile(`^\s*\d{1,9}[.)]\s+(.*)$`)
+	thematicBreak        = regexp.MustCompile(`^(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	escapablePunctuation = `\` + "`" + `*_[]()#+-.!>|{}`
+)
+
+// ToXHTML は Markdown を XHTML の断片 (<body> の中身) に変換する。ストーリーで使う見出し・段落・引用・リスト・
+// コードブロック・区切り線と、強調・コード・リンクのみを扱う。生の HTML はエスケープし、常に整形式の XHTML を返す
+func ToXHTML(md string) string {
+	lines := strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+	var b strings.Builder
+	renderBlocks(&b, lines)
+	return b.String()
+}
+
+func renderBlocks(b *strings.Builder, lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			i++
+
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence := trimmed[:3]
+			var code []string
+			i++
+			for i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+				code = append(code, lines[i])
+				i++
+			}
+			i++ // 閉じのフェンス
+			fmt.Fprintf(b, "<pre><code>%s</code></pre>\n", html.EscapeString(strings.Join(code, "\n")))
+
+		case headingPattern.MatchString(trimmed):
+			m := headingPattern.FindStringSubmatch(trimmed)
+			level := len(m[1])
+			fmt.Fprintf(b, "<h%d>%s</h%d>\n", level, renderInline(m[2]), level)
+			i++
+
+		case thematicBreak.MatchString(trimmed):
+			b.WriteString("<hr/>\n")
+			i++
+
+		case strings.HasPrefix(trimmed, ">"):
+			var quoted []string
+			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">") {
+				content := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(content, " "))
+				i++
+			}
+			b.WriteString("<blockquote>\n")
+			renderBlocks(b, quoted)
+			b.WriteString("</blockquote>\n")
+
+		case unorderedItem.MatchString(line) || orderedItem.MatchString(line):
+			pattern, tag := unorderedItem, "ul"
+			if !unorderedItem.MatchString(line) {
+				pattern, tag = orderedItem, "ol"
+			}
+			fmt.Fprintf(b, "<%s>\n", tag)
+			for i < len(lines) {
+				if strings.TrimSpace(lines[i]) == "" {
+					// 項目の間の空行は同じリストとして続ける
+					next := i
+					for next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+						next++
+					}
+					if next == len(lines) || !pattern.MatchString(lines[next]) {
+						break
+					}
+					i = next
+				}
+				if !pattern.MatchString(lines[i]) {
+					break
+				}
+				fmt.Fprintf(b, "<li>%s</li>\n", renderInline(pattern.FindStringSubmatch(lines[i])[1]))
+				i++
+			}
+			fmt.Fprintf(b, "</%s>\n", tag)
+
+		default:
+			// 段落は空行か別のブロックが始まるまで続く。段落内の改行はそのまま残す (表示上は空白になる)
+			var paragraph []string
+			for i < len(lines) && strings.TrimSpace(lines[i]) != "" && (len(paragraph) == 0 || !startsBlock(lines[i])) {
+				paragraph = append(paragraph, strings.TrimSpace(lines[i]))
+				i++
+			}
+			fmt.Fprintf(b, "<p>%s</p>\n", renderInline(strings.Join(paragraph, "\n")))
+		}
+	}
+}
+
+func startsBlock(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") || strings.HasPrefix(trimmed, ">") ||
+		headingPattern.MatchString(trimmed) || thematicBreak.MatchString(trimmed) ||
+		unorderedItem.MatchString(line) || orderedItem.MatchString(line)
+}
+
+// renderInline は強調・コード・リンクを XHTML に変換する。閉じる記号のない強調などは文字として残す
+func renderInline(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte(escapablePunctuation, s[i+1]) >= 0:
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+
+		case c == '`':
+			if end := strings.IndexByte(s[i+1:], '`'); end > 0 {
+				fmt.Fprintf(&b, "<code>%s</code>", html.EscapeString(s[i+1:i+1+end]))
+				i += end + 2
+				continue
+			}
+
+		case c == '*' || c == '_':
+			marker, tag := s[i:i+1], "em"
+			if strings.HasPrefix(s[i:], strings.Repeat(marker, 2)) {
+				marker, tag = strings.Repeat(marker, 2), "strong"
+			}
+			if end := closingMarker(s, i, marker); end > 0 {
+				fmt.Fprintf(&b, "<%s>%s</%s>", tag, renderInline(s[i+len(marker):end]), tag)
+				i = end + len(marker)
+				continue
+			}
+
+		case c == '[':
+			if text, href, n, ok := parseLink(s[i:]); ok {
+				if isWebURL(href) {
+					fmt.Fprintf(&b, `<a href="%s">%s</a>`, html.EscapeString(href), renderInline(text))
+				} else {
+					b.WriteString(renderInline(text))
+				}
+				i += n
+				continue
+			}
+		}
+		b.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+	return b.String()
+}
+
+// closingMarker は start から始まる強調の閉じる記号の位置を返す。見つからない場合は -1。
+// 記号の直後・直前が空白の場合や、単語の途中の "_" は強調として扱わない
+func closingMarker(s string, start int, marker string) int {
+	open := start + len(marker)
+	if open >= len(s) || s[open] == ' ' || s[open] == '\n' {
+		return -1
+	}
+	if marker[0] == '_' && start > 0 && isWordByte(s[start-1]) {
+		return -1
+	}
+	for i := open + 1; i+len(marker) <= len(s); i++ {
+		if !strings.HasPrefix(s[i:], marker) || s[i-1] == ' ' || s[i-1] == '\n' {
+			continue
+		}
+		after := i + len(marker)
+		// "*a **b** c*" の "**" を単独の "*" の閉じとして扱わない
+		if len(marker) == 1 && (after < len(s) && s[after] == marker[0] || s[i-1] == marker[0]) {
+			continue
+		}
+		if marker[0] == '_' && after < len(s) && isWordByte(s[after]) {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+// parseLink は "[text](href)" を解釈し、リンクのテキスト・URL・読み進めたバイト数を返す
+func parseLink(s string) (string, string, int, bool) {
+	closeText := strings.Index(s, "](")
+	if closeText < 0 {
+		return "", "", 0, false
+	}
+	closeHref := strings.IndexByte(s[closeText+2:], ')')
+	if closeHref < 0 {
+		return "", "", 0, false
+	}
+	text := s[1:closeText]
+	href := strings.TrimSpace(s[closeText+2 : closeText+2+closeHref])
+	if strings.ContainsAny(text, "[]\n") || strings.ContainsAny(href, " \n") {
+		return "", "", 0, false
+	}
+	return text, href, closeText + 2 + closeHref + 1, true
+}
+
+func isWebURL(href string) bool {
+	lower := strings.ToLower(href)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
@@ -0,0 +1,67 @@
+package markdown
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToXHTML(t *testing.T) {
+	testCases := []struct {
+		name     string
+		markdown string
+		expected string
+	}{
+		{
+			name:     "headings, paragraphs and emphasis",
+			markdown: "# The Lost Key\n\nTom looked *everywhere* for the **old** key.\nIt was `gone`.",
+			expected: "<h1>The Lost Key</h1>\n<p>Tom looked <em>everywhere</em> for the <strong>old</strong> key.\nIt was <code>gone</code>.</p>\n",
+		},
+		{
+			name:     "lists separated by blank lines stay in one list",
+			markdown: "- one\n\n- two\n\n1. first\n2. second",
+			expected: "<ul>\n<li>one</li>\n<li>two</li>\n</ul>\n<ol>\n<li>first</li>\n<li>second</li>\n</ol>\n",
+		},
+		{
+			name:     "quotes, code blocks and breaks",
+			markdown: "> Quoted *text*\n\n```\nif a < b {\n```\n\n---",
+			expected: "<blockquote>\n<p>Quoted <em>text</em></p>\n</blockquote>\n<pre><code>if a &lt; b {</code></pre>\n<hr/>\n",
+		},
+		{
+			name:     "raw html is escaped and only web links are kept",
+			markdown: `<script>alert("x")</script> [site](https://example.com/?a=1&b=2) [file](file:///etc/passwd)`,
+			expected: "<p>&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; <a href=\"https://example.com/?a=1&amp;b=2\">site</a> file</p>\n",
+		},
+		{
+			name:     "unmatched markers and snake_case are left as text",
+			markdown: "2 * 3 = 6 and snake_case_name, **not closed",
+			expected: "<p>2 * 3 = 6 and snake_case_name, **not closed</p>\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := ToXHTML(tc.markdown)
+
+			assert.Equal(t, tc.expected, actual)
+			assertWellFormed(t, actual)
+		})
+	}
+}
+
+// assertWellFormed は XHTML として (XML として) 解釈できることを確認する
+func assertWellFormed(t *testing.T, fragment string) {
+	t.Helper()
+	decoder := xml.NewDecoder(strings.NewReader("<body>" + fragment + "</body>"))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return
+		}
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// CommunityStory は公開ライブラリのストーリー。公開時点の本文の写しで、公開したユーザーを特定できる情報は返さない。
+// 一覧では Content を含めない
+type CommunityStory struct {
+	ID            int            `json:"id"                db:"id"`
+	StoryID       *int           `json:"-"                 db:"story_id"`
+	UserID        int            `json:"-"                 db:"user_id"`
+	Title         string         `json:"title"             db:"title"`
+	Content       string         `json:"content,omitempty" db:"content"`
+	WordCount     int            `json:"word_count"        db:"word_count"`
+	Level         string         `json:"level"             db:"level"`
+	Tags          pq.StringArray `json:"tags"              db:"tags"`
+	LikeCount     int            `json:"like_count"        db:"like_count"`
+	LikedByMe     bool           `json:"liked_by_me"       db:"liked_by_me"`
+	PublishedByMe bool           `json:"published_by_me"   db:"published_by_me"`
+	Snippet       *string        `json:"snippet,omitempty" db:"snippet"`
+	CreatedAt     time.Time      `json:"created_at"        db:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"        db:"updated_at"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// DailyStoryPreference は「今日の文章」を自動生成するためのユーザーごとの設定
+type DailyStoryPreference struct {
+	UserID          int            `json:"user_id"                     db:"user_id"`
+	Enabled         bool           `json:"enabled"                     db:"enabled"`
+	Topics          pq.StringArray `json:"topics"                      db:"topics"`
+	Level           *string        `json:"level"                       db:"level"`
+	WordCount       *int           `json:"word_count"                  db:"word_count"`
+	DeliveryHour    int            `json:"delivery_hour"               db:"delivery_hour"`
+	TimeZone        string         `json:"time_zone"                   db:"time_zone"`
+	LastDeliveredOn *time.Time     `json:"last_delivered_on,omitempty" db:"last_delivered_on"`
+	CreatedAt       time.Time      `json:"created_at"                  db:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"                  db:"updated_at"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+)
+
+// PromptTemplate は {{name}} 形式のプレースホルダを含むプロンプトのテンプレート。
+// UserID が nil の場合は全ユーザーが利用できるプリセット
+type PromptTemplate struct {
+	ID               int       `json:"id"                 db:"id"`
+	UserID           *int      `json:"user_id"            db:"user_id"`
+	Name             string    `json:"name"               db:"name"`
+	Body             string    `json:"body"               db:"body"`
+	DefaultLevel     *string   `json:"default_level"      db:"default_level"`
+	DefaultWordCount *int      `json:"default_word_count" db:"default_word_count"`
+	CreatedAt        time.Time `json:"created_at"         db:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"         db:"updated_at"`
+}
+
+// IsGlobal はプリセットテンプレートかどうかを返す
+func (t *PromptTemplate) IsGlobal() bool {
+	return t.UserID == nil
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	QuestionTypeMultipleChoice = "multiple_choice"
+	QuestionTypeTrueFalse      = "true_false"
+)
+
+type QuizQuestion struct {
+	ID           int            `json:"id"            db:"id"`
+	StoryID      int            `json:"story_id"      db:"story_id"`
+	QuestionType string         `json:"question_type" db:"question_type"`
+	Question     string         `json:"question"      db:"question"`
+	Choices      pq.StringArray `json:"choices"       db:"choices"`
+	CorrectIndex int            `json:"-"             db:"correct_index"`
+	Position     int            `json:"position"      db:"position"`
+	CreatedAt    time.Time      `json:"created_at"    db:"created_at"`
+}
+
+type QuizAttempt struct {
+	ID        int           `json:"id"         db:"id"`
+	UserID    int           `json:"user_id"    db:"user_id"`
+	StoryID   int           `json:"story_id"   db:"story_id"`
+	Answers   pq.Int64Array `json:"answers"    db:"answers"`
+	Score     int           `json:"score"      db:"score"`
+	Total     int           `json:"total"      db:"total"`
+	Passed    bool          `json:"passed"     db:"passed"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+}
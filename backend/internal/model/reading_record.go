@@ -11,3 +11,13 @@ type ReadingRecord struct {
 	WordCount int       `json:"word_count" db:"word_count"`
 	ReadAt    time.Time `json:"read_at" db:"read_at"`
 }
+
+// ReadingHistoryItem は読了履歴の 1 件。読んだストーリーのタイトルを含む。
+// ストーリーを完全に削除した後も記録は残り、StoryID と Title は nil になる
+type ReadingHistoryItem struct {
+	ID        int       `json:"id"         db:"id"`
+	StoryID   *int      `json:"story_id"   db:"story_id"`
+	Title     *string   `json:"title"      db:"title"`
+	WordCount int       `json:"word_count" db:"word_count"`
+	ReadAt    time.Time `json:"read_at"    db:"read_at"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+)
+
+type Series struct {
+	ID                int       `json:"id"                 db:"id"`
+	UserID            int       `json:"user_id"            db:"user_id"`
+	Title             string    `json:"title"              db:"title"`
+	Summary           string    `json:"-"                  db:"summary"`
+	SummarizedThrough int       `json:"-"                  db:"summarized_through"`
+	CreatedAt         time.Time `json:"created_at"         db:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"         db:"updated_at"`
+}
+
+// SeriesChapter は連載に含まれる各章の概要
+type SeriesChapter struct {
+	StoryID       int    `json:"story_id"       db:"id"`
+	Title         string `json:"title"          db:"title"`
+	ChapterNumber int    `json:"chapter_number" db:"chapter_number"`
+	WordCount     int    `json:"word_count"     db:"word_count"`
+}
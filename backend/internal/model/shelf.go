@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+)
+
+// Shelf はストーリーを順番に並べた読書リスト。進捗は reading_records から集計する
+type Shelf struct {
+	ID                 int       `json:"id"                   db:"id"`
+	UserID             int       `json:"user_id"              db:"user_id"`
+	Name               string    `json:"name"                 db:"name"`
+	StoryCount         int       `json:"story_count"          db:"story_count"`
+	ReadStoryCount     int       `json:"read_story_count"     db:"read_story_count"`
+	TotalWordCount     int       `json:"total_word_count"     db:"total_word_count"`
+	RemainingWordCount int       `json:"remaining_word_count" db:"remaining_word_count"`
+	CreatedAt          time.Time `json:"created_at"           db:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"           db:"updated_at"`
+}
+
+// ShelfItem は棚に並んだストーリーの概要。Position は 1 から始まる
+type ShelfItem struct {
+	Position   int        `json:"position"     db:"position"`
+	StoryID    int        `json:"story_id"     db:"story_id"`
+	Title      string     `json:"title"        db:"title"`
+	WordCount  int        `json:"word_count"   db:"word_count"`
+	Level      *string    `json:"level"        db:"level"`
+	ReadCount  int        `json:"read_count"   db:"read_count"`
+	LastReadAt *time.Time `json:"last_read_at" db:"last_read_at"`
+}
@@ -4,12 +4,45 @@ import (
 	"time"
 )
 
+// CEFRLevels は文章の難易度として扱う CEFR レベル (易しい順)
+var CEFRLevels = []string{"A1", "A2", "B1", "B2", "C1", "C2"}
+
+// ストーリーの出どころ
+const (
+	StorySourceGenerated = "generated"
+	StorySourceImported  = "imported"
+	// StorySourceCommunity は公開ライブラリから複製したストーリー
+	StorySourceCommunity = "community"
+)
+
 type Story struct {
-	ID        int       `json:"id"         db:"id"`
-	UserID    int       `json:"user_id"    db:"user_id"`
-	Title     string    `json:"title"      db:"title"`
-	Content   string    `json:"content"    db:"content"`
-	WordCount int       `json:"word_count" db:"word_count"`
+	ID            int     `json:"id"         db:"id"`
+	UserID        int     `json:"user_id"    db:"user_id"`
+	Title         string  `json:"title"      db:"title"`
+	Content       string  `json:"content"    db:"content"`
+	WordCount     int     `json:"word_count" db:"word_count"`
+	Level         *string `json:"level,omitempty"          db:"level"`
+	SeriesID      *int    `json:"series_id,omitempty"      db:"series_id"`
+	ChapterNumber *int    `json:"chapter_number,omitempty" db:"chapter_number"`
+	ParentStoryID *int    `json:"parent_story_id,omitempty" db:"parent_story_id"`
+	// IsFavorite のストーリーは一括削除などの整理操作の対象外
+	IsFavorite bool `json:"is_favorite" db:"is_favorite"`
+	// Source は生成 (generated)・取り込み (imported)・公開ライブラリからの複製 (community)。空の場合は generated として保存する
+	Source string `json:"source" db:"source"`
+	// SourceURL は URL から取り込んだ場合の取得元
+	SourceURL *string   `json:"source_url,omitempty" db:"source_url"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	// DeletedAt はゴミ箱に移した日時
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	// SimilarStories は生成・取り込みの直後に、本文がほぼ同じ既存のストーリーを警告として返すためのもの
+	SimilarStories []*SimilarStory `json:"similar_stories,omitempty" db:"-"`
+}
+
+// StoryListItem は一覧表示用のストーリー。全文検索時は本文の該当箇所を強調したスニペットを含む
+type StoryListItem struct {
+	Story
+	ReadCount  int        `json:"read_count"        db:"read_count"`
+	LastReadAt *time.Time `json:"last_read_at"      db:"last_read_at"`
+	Snippet    *string    `json:"snippet,omitempty" db:"snippet"`
 }
@@ -0,0 +1,38 @@
+package model
+
+// ArchiveStory はライブラリのアーカイブに書き出すストーリー。タグ名と読了記録を含む
+type ArchiveStory struct {
+	Story
+	Tags           []string
+	ReadingRecords []*ReadingRecord
+}
+
+// アーカイブの取り込みの各ストーリーの結果
+const (
+	ArchiveImportCreated   = "created"
+	ArchiveImportDuplicate = "duplicate"
+	ArchiveImportConflict  = "conflict"
+)
+
+// ArchiveImportResult はアーカイブの取り込みでのストーリーごとの結果。StoryID は作成したストーリーか、本文が同じ既存のストーリー。
+// Conflicts は本文が同じ既存のストーリーと値が異なる項目で、既存の値をそのまま残す
+type ArchiveImportResult struct {
+	File                string   `json:"file"`
+	Title               string   `json:"title"`
+	Status              string   `json:"status"`
+	StoryID             int      `json:"story_id"`
+	Conflicts           []string `json:"conflicts,omitempty"`
+	AddedTags           int      `json:"added_tags"`
+	AddedReadingRecords int      `json:"added_reading_records"`
+	// SimilarStories は作成したストーリーと本文がほぼ同じ既存のストーリー
+	SimilarStories []*SimilarStory `json:"similar_stories,omitempty"`
+}
+
+// ArchiveImport はアーカイブの取り込みの結果。Stories はアーカイブ内の順に並ぶ
+type ArchiveImport struct {
+	Created     int                    `json:"created"`
+	Duplicates  int                    `json:"duplicates"`
+	Conflicts   int                    `json:"conflicts"`
+	CreatedTags []string               `json:"created_tags"`
+	Stories     []*ArchiveImportResult `json:"stories"`
+}
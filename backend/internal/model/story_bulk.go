@@ -0,0 +1,15 @@
+package model
+
+// 一括操作の各ストーリーの結果
+const (
+	BulkStatusOK       = "ok"
+	BulkStatusNotFound = "not_found"
+	BulkStatusSkipped  = "skipped"
+)
+
+// BulkStoryResult は一括操作でのストーリーごとの結果。Reason はスキップした理由
+type BulkStoryResult struct {
+	StoryID int    `json:"id"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+)
+
+// SimilarStory は本文がほぼ同じストーリー。Similarity は本文の SimHash の一致度 (0〜1)
+type SimilarStory struct {
+	StoryID    int     `json:"story_id"`
+	Title      string  `json:"title"`
+	Similarity float64 `json:"similarity"`
+}
+
+// StoryFingerprint は本文の SimHash と、重複の一覧に表示するストーリーの概要
+type StoryFingerprint struct {
+	ID        int       `json:"id"         db:"id"`
+	Title     string    `json:"title"      db:"title"`
+	WordCount int       `json:"word_count" db:"word_count"`
+	Level     *string   `json:"level"      db:"level"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	Simhash   int64     `json:"-"          db:"simhash"`
+}
+
+// DuplicateCluster は本文がほぼ同じストーリーのまとまり。Stories は作成日時の古い順に並び、
+// MinSimilarity はまとまりの中で最も似ていない 2 つの一致度
+type DuplicateCluster struct {
+	Stories       []*StoryFingerprint `json:"stories"`
+	MinSimilarity float64             `json:"min_similarity"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+)
+
+// StoryRevision は本文を編集する前のストーリーの版。一覧では Content を含めない
+type StoryRevision struct {
+	ID             int       `json:"id"                db:"id"`
+	StoryID        int       `json:"story_id"          db:"story_id"`
+	RevisionNumber int       `json:"revision_number"   db:"revision_number"`
+	Title          string    `json:"title"             db:"title"`
+	Content        string    `json:"content,omitempty" db:"content"`
+	WordCount      int       `json:"word_count"        db:"word_count"`
+	CreatedAt      time.Time `json:"created_at"        db:"created_at"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+)
+
+// StoryShare はストーリーの共有リンク。Token を知っていればログインなしで本文を読める。ExpiresAt が nil の場合は無期限
+type StoryShare struct {
+	ID        int        `json:"id"         db:"id"`
+	StoryID   int        `json:"story_id"   db:"story_id"`
+	Token     string     `json:"token"      db:"token"`
+	ExpiresAt *time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// SharedStory は共有リンクで公開するストーリー。所有者を特定できる情報 (ID・ユーザーなど) は含めない
+type SharedStory struct {
+	Title     string `json:"title"      db:"title"`
+	Content   string `json:"content"    db:"content"`
+	WordCount int    `json:"word_count" db:"word_count"`
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// TranslationLanguageJapanese は対訳として扱う言語
+const TranslationLanguageJapanese = "ja"
+
+// StoryTranslation は本文の段落と 1 対 1 に対応する翻訳。読了語数には含めない
+type StoryTranslation struct {
+	StoryID    int            `json:"story_id"   db:"story_id"`
+	Language   string         `json:"language"   db:"language"`
+	Paragraphs pq.StringArray `json:"paragraphs" db:"paragraphs"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,9 @@
+package model
+
+// StoryVariant は同じ内容を別のレベルで書き換えたストーリーの概要
+type StoryVariant struct {
+	StoryID   int     `json:"story_id"   db:"id"`
+	Title     string  `json:"title"      db:"title"`
+	Level     *string `json:"level"      db:"level"`
+	WordCount int     `json:"word_count" db:"word_count"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import (
+	"time"
+)
+
+// Tag はユーザーがストーリーの分類に使うタグ
+type Tag struct {
+	ID         int       `json:"id"          db:"id"`
+	UserID     int       `json:"user_id"     db:"user_id"`
+	Name       string    `json:"name"        db:"name"`
+	StoryCount int       `json:"story_count" db:"story_count"`
+	CreatedAt  time.Time `json:"created_at"  db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"  db:"updated_at"`
+}
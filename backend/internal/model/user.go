@@ -10,6 +10,7 @@ type User struct {
 	PasswordHash     string     `json:"password_hash" db:"password_hash"`
 	GenerationCount  int        `json:"generation_count,omitempty"  db:"generation_count"`
 	LastGenerationAt *time.Time `json:"last_generation_at,omitempty" db:"last_generation_at"`
+	IsAdmin          bool       `json:"is_admin"      db:"is_admin"`
 	CreatedAt        time.Time  `json:"created_at"    db:"created_at"`
 	UpdatedAt        time.Time  `json:"updated_at"    db:"updated_at"`
 }
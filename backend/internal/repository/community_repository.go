@@ -0,0 +1,292 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+)
+
+// ICommunityRepository: community_stories / community_story_likes テーブルの操作インターフェース
+type ICommunityRepository interface {
+	PublishStory(story *model.CommunityStory) error
+	DeleteCommunityStory(communityStoryID, userID int) error
+	GetCommunityStory(communityStoryID, userID int) (*model.CommunityStory, error)
+	ListCommunityStories(userID int, filter CommunityFilter, sort CommunitySort, limit, offset int) ([]*model.CommunityStory, error)
+	CountCommunityStories(filter CommunityFilter) (int, error)
+	LikeCommunityStory(communityStoryID, userID int) (int, error)
+	UnlikeCommunityStory(communityStoryID, userID int) (int, error)
+	CloneCommunityStory(communityStoryID, userID int) (*model.Story, error)
+}
+
+// CommunityFilter は公開ライブラリの絞り込み条件
+type CommunityFilter struct {
+	// Query はタイトル・本文に対する全文検索のクエリ (websearch_to_tsquery の構文)
+	Query string
+	Level string
+	Tag   string
+	// PublishedBy が指定された場合はそのユーザーが公開したものだけにする
+	PublishedBy *int
+}
+
+// CommunitySort は公開ライブラリの並び順
+type CommunitySort string
+
+const (
+	// CommunitySortDefault は全文検索時は関連度順、それ以外は新しい順
+	CommunitySortDefault CommunitySort = ""
+	CommunitySortNewest  CommunitySort = "newest"
+	CommunitySortPopular CommunitySort = "popular"
+)
+
+// communitySortOrders は並び順ごとの ORDER BY 句。id で順序を確定させる
+var communitySortOrders = map[CommunitySort]string{
+	CommunitySortNewest:  "created_at DESC, id DESC",
+	CommunitySortPopular: "like_count DESC, created_at DESC, id DESC",
+}
+
+func (s CommunitySort) IsValid() bool {
+	if s == CommunitySortDefault {
+		return true
+	}
+	_, ok := communitySortOrders[s]
+	return ok
+}
+
+type sqlxCommunityRepository struct {
+	DB *sqlx.DB
+}
+
+func NewCommunityRepository(db *sqlx.DB) ICommunityRepository {
+	return &sqlxCommunityRepository{DB: db}
+}
+
+// PublishStory はストーリーの写しを公開する。既に公開済みのストーリーの場合は内容を置き換え、いいねの数は残す
+func (r *sqlxCommunityRepository) PublishStory(story *model.CommunityStory) error {
+	query := `
+		INSERT INTO community_stories(story_id, user_id, title, content, word_count, level, tags)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (story_id) DO UPDATE
+		SET title = EXCLUDED.title, content = EXCLUDED.content, word_count = EXCLUDED.word_count,
+			level = EXCLUDED.level, tags = EXCLUDED.tags
+		RETURNING id, like_count, created_at, updated_at
+	`
+	err := r.DB.QueryRowx(query, story.StoryID, story.UserID, story.Title, story.Content, story.WordCount, story.Level, story.Tags).
+		Scan(&story.ID, &story.LikeCount, &story.CreatedAt, &story.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to publish story: %w", err)
+	}
+	story.PublishedByMe = true
+	return nil
+}
+
+// DeleteCommunityStory は公開を取り消す。ユーザーが公開したものでない場合は sql.ErrNoRows を返す
+func (r *sqlxCommunityRepository) DeleteCommunityStory(communityStoryID, userID int) error {
+	var id int
+	err := r.DB.Get(&id, `DELETE FROM community_stories WHERE id = $1 AND user_id = $2 RETURNING id`, communityStoryID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete community story: %w", err)
+	}
+	return nil
+}
+
+// GetCommunityStory は本文を含めて公開ライブラリのストーリーを返す。userID は liked_by_me / published_by_me の判定に使う
+func (r *sqlxCommunityRepository) GetCommunityStory(communityStoryID, userID int) (*model.CommunityStory, error) {
+	query := `
+		SELECT id, title, content, word_count, level, tags, like_count, created_at, updated_at,
+			user_id = $2 AS published_by_me,
+			EXISTS (SELECT 1 FROM community_story_likes l WHERE l.community_story_id = community_stories.id AND l.user_id = $2) AS liked_by_me
+		FROM community_stories
+		WHERE id = $1
+	`
+	var story model.CommunityStory
+	if err := r.DB.Get(&story, query, communityStoryID, userID); err != nil {
+		return nil, fmt.Errorf("failed to get community story: %w", err)
+	}
+	return &story, nil
+}
+
+// ListCommunityStories は公開ライブラリの一覧を本文なしで返す。
+// 並び順の指定がない場合、全文検索時は関連度順、それ以外は新しい順
+func (r *sqlxCommunityRepository) ListCommunityStories(userID int, filter CommunityFilter, sort CommunitySort, limit, offset int) ([]*model.CommunityStory, error) {
+	where, args := buildCommunityFilter(filter)
+
+	args = append(args, userID)
+	userArg := len(args)
+	columns := fmt.Sprintf(`id, title, word_count, level, tags, like_count, created_at, updated_at,
+		user_id = $%[1]d AS published_by_me,
+		EXISTS (SELECT 1 FROM community_story_likes l WHERE l.community_story_id = community_stories.id AND l.user_id = $%[1]d) AS liked_by_me`, userArg)
+	orderBy, ok := communitySortOrders[sort]
+	if !ok {
+		orderBy = communitySortOrders[CommunitySortNewest]
+	}
+	if filter.Query != "" {
+		// buildCommunityFilter が検索クエリを $1 に割り当てている
+		columns += fmt.Sprintf(", ts_headline('english', content, websearch_to_tsquery('english', $1), '%s') AS snippet", snippetOptions)
+		if sort == CommunitySortDefault {
+			orderBy = "ts_rank(search_vector, websearch_to_tsquery('english', $1)) DESC, " + orderBy
+		}
+	}
+
+	args = append(args, limit, offset)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM community_stories
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, columns, where, orderBy, len(args)-1, len(args))
+
+	var stories []*model.CommunityStory
+	if err := r.DB.Select(&stories, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list community stories: %w", err)
+	}
+	for _, story := range stories {
+		story.Snippet = escapeSnippet(story.Snippet)
+	}
+	return stories, nil
+}
+
+func (r *sqlxCommunityRepository) CountCommunityStories(filter CommunityFilter) (int, error) {
+	where, args := buildCommunityFilter(filter)
+
+	var total int
+	if err := r.DB.Get(&total, `SELECT COUNT(*) FROM community_stories WHERE `+where, args...); err != nil {
+		return 0, fmt.Errorf("failed to count community stories: %w", err)
+	}
+	return total, nil
+}
+
+// buildCommunityFilter は絞り込み条件の WHERE 句と引数を返す。検索クエリがある場合は必ず $1 に割り当てる
+func buildCommunityFilter(filter CommunityFilter) (string, []any) {
+	conditions := []string{"TRUE"}
+	var args []any
+
+	addCondition := func(format string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+
+	if filter.Query != "" {
+		addCondition("search_vector @@ websearch_to_tsquery('english', $%d)", filter.Query)
+	}
+	if filter.Level != "" {
+		addCondition("level = $%d", filter.Level)
+	}
+	if filter.Tag != "" {
+		// idx_community_stories_tags (GIN) を使う
+		addCondition("tags @> ARRAY[$%d]::text[]", filter.Tag)
+	}
+	if filter.PublishedBy != nil {
+		addCondition("user_id = $%d", *filter.PublishedBy)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// LikeCommunityStory は「いいね」を付け、付けた後のいいねの数を返す。既に付けている場合は数を変えない。
+// 公開ライブラリにない場合は sql.ErrNoRows を返す
+func (r *sqlxCommunityRepository) LikeCommunityStory(communityStoryID, userID int) (int, error) {
+	return r.updateLike(communityStoryID, userID, `
+		INSERT INTO community_story_likes(community_story_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (community_story_id, user_id) DO NOTHING
+	`, 1)
+}
+
+// UnlikeCommunityStory は「いいね」を取り消し、取り消した後のいいねの数を返す。付けていない場合は数を変えない
+func (r *sqlxCommunityRepository) UnlikeCommunityStory(communityStoryID, userID int) (int, error) {
+	return r.updateLike(communityStoryID, userID, `
+		DELETE FROM community_story_likes
+		WHERE community_story_id = $1 AND user_id = $2
+	`, -1)
+}
+
+// updateLike は community_story_likes を変更し、変更した場合だけ like_count を delta だけ増減する。
+// 並行した変更で数がずれないよう、公開ライブラリの行をロックしてから行う
+func (r *sqlxCommunityRepository) updateLike(communityStoryID, userID int, query string, delta int) (int, error) {
+	tx, err := r.DB.Beginx()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var likeCount int
+	if err := tx.Get(&likeCount, `SELECT like_count FROM community_stories WHERE id = $1 FOR UPDATE`, communityStoryID); err != nil {
+		return 0, fmt.Errorf("failed to get community story: %w", err)
+	}
+
+	res, err := tx.Exec(query, communityStoryID, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update like: %w", err)
+	}
+	changed, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to update like: %w", err)
+	}
+	if changed > 0 {
+		err := tx.Get(&likeCount, `UPDATE community_stories SET like_count = like_count + $2 WHERE id = $1 RETURNING like_count`, communityStoryID, delta)
+		if err != nil {
+			return 0, fmt.Errorf("failed to update like count: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return likeCount, nil
+}
+
+// CloneCommunityStory は公開ライブラリのストーリーをユーザーのライブラリに複製し、公開時のタグを付ける。
+// 複製は新しいストーリーのため、読了記録は複製したユーザーのものだけになる。公開ライブラリにない場合は sql.ErrNoRows を返す
+func (r *sqlxCommunityRepository) CloneCommunityStory(communityStoryID, userID int) (*model.Story, error) {
+	tx, err := r.DB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var source model.CommunityStory
+	query := `SELECT title, content, word_count, level, tags FROM community_stories WHERE id = $1`
+	if err := tx.Get(&source, query, communityStoryID); err != nil {
+		return nil, fmt.Errorf("failed to get community story: %w", err)
+	}
+
+	story := &model.Story{
+		UserID:    userID,
+		Title:     source.Title,
+		Content:   source.Content,
+		WordCount: source.WordCount,
+		Level:     &source.Level,
+		Source:    model.StorySourceCommunity,
+	}
+	if err := insertStory(tx, story); err != nil {
+		return nil, err
+	}
+
+	if len(source.Tags) > 0 {
+		createTagsQuery := `
+			INSERT INTO tags(user_id, name)
+			SELECT $1, name FROM unnest($2::text[]) AS t(name)
+			ON CONFLICT (user_id, name) DO NOTHING
+		`
+		if _, err := tx.Exec(createTagsQuery, userID, pq.Array(source.Tags)); err != nil {
+			return nil, fmt.Errorf("failed to create tags: %w", err)
+		}
+		attachTagsQuery := `
+			INSERT INTO story_tags(story_id, tag_id)
+			SELECT $1, id FROM tags WHERE user_id = $2 AND name = ANY($3::text[])
+			ON CONFLICT (story_id, tag_id) DO NOTHING
+		`
+		if _, err := tx.Exec(attachTagsQuery, story.ID, userID, pq.Array(source.Tags)); err != nil {
+			return nil, fmt.Errorf("failed to attach tags: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return story, nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommunityRepository(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCommunityRepository(db)
+	storyRepo := NewStoryRepository(db)
+	tagRepo := NewTagRepository(db)
+
+	publish := func(t *testing.T, story *model.Story, level string, tags ...string) *model.CommunityStory {
+		t.Helper()
+		published := &model.CommunityStory{
+			StoryID:   &story.ID,
+			UserID:    story.UserID,
+			Title:     story.Title,
+			Content:   story.Content,
+			WordCount: story.WordCount,
+			Level:     level,
+			Tags:      pq.StringArray(tags),
+		}
+		require.NoError(t, repo.PublishStory(published))
+		return published
+	}
+
+	t.Run("PublishStory replaces the copy when republished", func(t *testing.T) {
+		user := createTestUser(t, db)
+		story := createTestStory(t, db, user.ID, "Republished", 3)
+
+		first := publish(t, story, "A1", "old")
+		story, err := storyRepo.UpdateStoryTitle(story.ID, user.ID, "Renamed")
+		require.NoError(t, err)
+		second := publish(t, story, "A2", "new")
+
+		assert.Equal(t, first.ID, second.ID)
+		got, err := repo.GetCommunityStory(first.ID, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Renamed", got.Title)
+		assert.Equal(t, "A2", got.Level)
+		assert.Equal(t, pq.StringArray{"new"}, got.Tags)
+		assert.True(t, got.PublishedByMe)
+	})
+
+	t.Run("ListCommunityStories filters by level and tag and sorts by likes", func(t *testing.T) {
+		publisher := createTestUser(t, db)
+		reader := createTestUser(t, db)
+		quiet := publish(t, createTestStory(t, db, publisher.ID, "Quiet harbor", 3), "C2", "sea")
+		popular := publish(t, createTestStory(t, db, publisher.ID, "Popular harbor", 3), "C2", "sea")
+		publish(t, createTestStory(t, db, publisher.ID, "Other level", 3), "C1", "sea")
+
+		_, err := repo.LikeCommunityStory(popular.ID, reader.ID)
+		require.NoError(t, err)
+
+		filter := CommunityFilter{Level: "C2", Tag: "sea"}
+		total, err := repo.CountCommunityStories(filter)
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+
+		stories, err := repo.ListCommunityStories(reader.ID, filter, CommunitySortPopular, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, stories, 2)
+		assert.Equal(t, popular.ID, stories[0].ID)
+		assert.True(t, stories[0].LikedByMe)
+		assert.False(t, stories[0].PublishedByMe)
+		assert.Empty(t, stories[0].Content)
+		assert.Equal(t, quiet.ID, stories[1].ID)
+		assert.False(t, stories[1].LikedByMe)
+	})
+
+	t.Run("Like and unlike count each user once", func(t *testing.T) {
+		publisher := createTestUser(t, db)
+		reader := createTestUser(t, db)
+		published := publish(t, createTestStory(t, db, publisher.ID, "Liked", 3), "B1")
+
+		count, err := repo.LikeCommunityStory(published.ID, reader.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		count, err = repo.LikeCommunityStory(published.ID, reader.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		count, err = repo.UnlikeCommunityStory(published.ID, reader.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+		count, err = repo.UnlikeCommunityStory(published.ID, reader.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+
+		_, err = repo.LikeCommunityStory(-1, reader.ID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("CloneCommunityStory copies the story and its tags into the user's library", func(t *testing.T) {
+		publisher := createTestUser(t, db)
+		reader := createTestUser(t, db)
+		original := createTestStory(t, db, publisher.ID, "Cloned", 3)
+		published := publish(t, original, "B2", "travel", "food")
+
+		clone, err := repo.CloneCommunityStory(published.ID, reader.ID)
+		require.NoError(t, err)
+		assert.NotEqual(t, original.ID, clone.ID)
+		assert.Equal(t, model.StorySourceCommunity, clone.Source)
+
+		got, err := storyRepo.GetUserStory(clone.ID, reader.ID)
+		require.NoError(t, err)
+		assert.Equal(t, original.Content, got.Content)
+		require.NotNil(t, got.Level)
+		assert.Equal(t, "B2", *got.Level)
+
+		tags, err := tagRepo.GetStoryTags(clone.ID)
+		require.NoError(t, err)
+		assert.Len(t, tags, 2)
+		for _, tag := range tags {
+			assert.Equal(t, reader.ID, tag.UserID)
+		}
+
+		_, err = repo.CloneCommunityStory(-1, reader.ID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("DeleteCommunityStory only deletes the user's own publication", func(t *testing.T) {
+		publisher := createTestUser(t, db)
+		other := createTestUser(t, db)
+		published := publish(t, createTestStory(t, db, publisher.ID, "Unpublished", 3), "A1")
+
+		assert.ErrorIs(t, repo.DeleteCommunityStory(published.ID, other.ID), sql.ErrNoRows)
+		require.NoError(t, repo.DeleteCommunityStory(published.ID, publisher.ID))
+
+		_, err := repo.GetCommunityStory(published.ID, publisher.ID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+)
+
+// IDailyStoryRepository: daily_story_preferences テーブルの操作インターフェース
+type IDailyStoryRepository interface {
+	GetPreference(userID int) (*model.DailyStoryPreference, error)
+	UpsertPreference(preference *model.DailyStoryPreference) error
+	ListEnabledPreferences() ([]*model.DailyStoryPreference, error)
+	ClaimDelivery(userID int, deliveredOn time.Time) (bool, error)
+	ReleaseDelivery(userID int, deliveredOn time.Time, previous *time.Time) error
+}
+
+type sqlxDailyStoryRepository struct {
+	DB *sqlx.DB
+}
+
+func NewDailyStoryRepository(db *sqlx.DB) IDailyStoryRepository {
+	return &sqlxDailyStoryRepository{DB: db}
+}
+
+func (r *sqlxDailyStoryRepository) GetPreference(userID int) (*model.DailyStoryPreference, error) {
+	query := `
+		SELECT user_id, enabled, topics, level, word_count, delivery_hour, time_zone, last_delivered_on, created_at, updated_at
+		FROM daily_story_preferences
+		WHERE user_id = $1
+	`
+	var preference model.DailyStoryPreference
+	err := r.DB.Get(&preference, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily story preference: %w", err)
+	}
+	return &preference, nil
+}
+
+// UpsertPreference は設定を保存する。配信済みの日付は保持する
+func (r *sqlxDailyStoryRepository) UpsertPreference(preference *model.DailyStoryPreference) error {
+	query := `
+		INSERT INTO daily_story_preferences(user_id, enabled, topics, level, word_count, delivery_hour, time_zone)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id)
+		DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			topics = EXCLUDED.topics,
+			level = EXCLUDED.level,
+			word_count = EXCLUDED.word_count,
+			delivery_hour = EXCLUDED.delivery_hour,
+			time_zone = EXCLUDED.time_zone
+		RETURNING last_delivered_on, created_at, updated_at
+	`
+	err := r.DB.QueryRowx(
+		query,
+		preference.UserID,
+		preference.Enabled,
+		preference.Topics,
+		preference.Level,
+		preference.WordCount,
+		preference.DeliveryHour,
+		preference.TimeZone,
+	).Scan(&preference.LastDeliveredOn, &preference.CreatedAt, &preference.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save daily story preference: %w", err)
+	}
+	return nil
+}
+
+func (r *sqlxDailyStoryRepository) ListEnabledPreferences() ([]*model.DailyStoryPreference, error) {
+	query := `
+		SELECT user_id, enabled, topics, level, word_count, delivery_hour, time_zone, last_delivered_on, created_at, updated_at
+		FROM daily_story_preferences
+		WHERE enabled
+		ORDER BY user_id ASC
+	`
+	var preferences []*model.DailyStoryPreference
+	err := r.DB.Select(&preferences, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daily story preferences: %w", err)
+	}
+	return preferences, nil
+}
+
+// ClaimDelivery はユーザーのタイムゾーンにおける配信日を、生成の前に記録する。
+// 既にその日以降の配信日が記録されている (並行して実行したジョブが先に記録した) 場合は false を返す
+func (r *sqlxDailyStoryRepository) ClaimDelivery(userID int, deliveredOn time.Time) (bool, error) {
+	query := `
+		UPDATE daily_story_preferences
+		SET last_delivered_on = $1
+		WHERE user_id = $2 AND enabled AND (last_delivered_on IS NULL OR last_delivered_on < $1)
+	`
+	result, err := r.DB.Exec(query, deliveredOn.Format(time.DateOnly), userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim daily story delivery: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows == 1, nil
+}
+
+// ReleaseDelivery は ClaimDelivery で記録した配信日を previous に戻し、次の実行で再試行できるようにする
+func (r *sqlxDailyStoryRepository) ReleaseDelivery(userID int, deliveredOn time.Time, previous *time.Time) error {
+	var previousDate *string
+	if previous != nil {
+		date := previous.Format(time.DateOnly)
+		previousDate = &date
+	}
+	query := `UPDATE daily_story_preferences SET last_delivered_on = $3 WHERE user_id = $1 AND last_delivered_on = $2`
+	_, err := r.DB.Exec(query, userID, deliveredOn.Format(time.DateOnly), previousDate)
+	if err != nil {
+		return fmt.Errorf("failed to release daily story delivery: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDailyStoryRepository(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewDailyStoryRepository(db)
+
+	t.Run("UpsertPreference keeps the last delivery date", func(t *testing.T) {
+		user := createTestUser(t, db)
+
+		preference := &model.DailyStoryPreference{
+			UserID:       user.ID,
+			Enabled:      true,
+			Topics:       pq.StringArray{"science"},
+			DeliveryHour: 7,
+			TimeZone:     "Asia/Tokyo",
+		}
+		require.NoError(t, repo.UpsertPreference(preference))
+		claimed, err := repo.ClaimDelivery(user.ID, time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		require.True(t, claimed)
+
+		preference.Topics = pq.StringArray{"science", "history"}
+		preference.DeliveryHour = 8
+		require.NoError(t, repo.UpsertPreference(preference))
+
+		fetched, err := repo.GetPreference(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, pq.StringArray{"science", "history"}, fetched.Topics)
+		assert.Equal(t, 8, fetched.DeliveryHour)
+		require.NotNil(t, fetched.LastDeliveredOn)
+		assert.Equal(t, "2025-06-02", fetched.LastDeliveredOn.Format(time.DateOnly))
+	})
+
+	t.Run("ClaimDelivery lets only one run deliver per day", func(t *testing.T) {
+		user := createTestUser(t, db)
+		require.NoError(t, repo.UpsertPreference(&model.DailyStoryPreference{UserID: user.ID, Enabled: true, Topics: pq.StringArray{"a"}, TimeZone: "UTC"}))
+		day := time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC)
+
+		claimed, err := repo.ClaimDelivery(user.ID, day)
+		require.NoError(t, err)
+		assert.True(t, claimed)
+
+		claimed, err = repo.ClaimDelivery(user.ID, day)
+		require.NoError(t, err)
+		assert.False(t, claimed, "the same day must not be claimed twice")
+
+		// 解放すると元の配信日 (未配信) に戻り、次の実行で再び割り当てられる
+		require.NoError(t, repo.ReleaseDelivery(user.ID, day, nil))
+		fetched, err := repo.GetPreference(user.ID)
+		require.NoError(t, err)
+		assert.Nil(t, fetched.LastDeliveredOn)
+
+		claimed, err = repo.ClaimDelivery(user.ID, day)
+		require.NoError(t, err)
+		assert.True(t, claimed)
+	})
+
+	t.Run("ListEnabledPreferences excludes disabled preferences", func(t *testing.T) {
+		enabledUser := createTestUser(t, db)
+		disabledUser := createTestUser(t, db)
+
+		require.NoError(t, repo.UpsertPreference(&model.DailyStoryPreference{UserID: enabledUser.ID, Enabled: true, Topics: pq.StringArray{"a"}, TimeZone: "UTC"}))
+		require.NoError(t, repo.UpsertPreference(&model.DailyStoryPreference{UserID: disabledUser.ID, Enabled: false, Topics: pq.StringArray{"b"}, TimeZone: "UTC"}))
+
+		preferences, err := repo.ListEnabledPreferences()
+		require.NoError(t, err)
+
+		userIDs := []int{}
+		for _, p := range preferences {
+			userIDs = append(userIDs, p.UserID)
+		}
+		assert.Contains(t, userIDs, enabledUser.ID)
+		assert.NotContains(t, userIDs, disabledUser.ID)
+	})
+}
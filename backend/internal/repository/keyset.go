@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"time"
+)
+
+// Keyset はキーセットページネーションの基準位置。並び順のキーとなる時刻と、同時刻の順序を確定させる id を持つ
+type Keyset struct {
+	Time time.Time
+	ID   int
+	// Backward が true の場合は基準位置より前のページを取得する
+	Backward bool
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeysetPagination(t *testing.T) {
+	db := setupTestDB(t)
+	storyRepo := NewStoryRepository(db)
+	readingRepo := NewReadingRecordRepository(db)
+
+	t.Run("GetUserStoriesByKeyset walks forward and backward", func(t *testing.T) {
+		user := createTestUser(t, db)
+
+		// 同じ作成日時のストーリーも id で順序が確定する
+		createdAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+		ids := make([]int, 5)
+		for i := range ids {
+			story := createTestStory(t, db, user.ID, "Story", 10)
+			_, err := db.Exec("UPDATE stories SET created_at = $1 WHERE id = $2", createdAt.Add(time.Duration(i/2)*time.Minute), story.ID)
+			require.NoError(t, err)
+			ids[i] = story.ID
+		}
+		// 新しい順: ids[4], ids[3], ids[2], ids[1], ids[0]
+
+		first, err := storyRepo.GetUserStoriesByKeyset(user.ID, StoryFilter{}, StorySortNewest, nil, 2)
+		require.NoError(t, err)
+		require.Len(t, first, 2)
+		assert.Equal(t, []int{ids[4], ids[3]}, []int{first[0].ID, first[1].ID})
+
+		last := first[len(first)-1]
+		second, err := storyRepo.GetUserStoriesByKeyset(user.ID, StoryFilter{}, StorySortNewest, &Keyset{Time: last.CreatedAt, ID: last.ID}, 2)
+		require.NoError(t, err)
+		require.Len(t, second, 2)
+		assert.Equal(t, []int{ids[2], ids[1]}, []int{second[0].ID, second[1].ID})
+
+		// 前のページは表示順 (新しい順) で返る
+		back, err := storyRepo.GetUserStoriesByKeyset(user.ID, StoryFilter{}, StorySortNewest, &Keyset{Time: second[0].CreatedAt, ID: second[0].ID, Backward: true}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []int{ids[4], ids[3]}, []int{back[0].ID, back[1].ID})
+
+		oldest, err := storyRepo.GetUserStoriesByKeyset(user.ID, StoryFilter{}, StorySortOldest, nil, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []int{ids[0], ids[1]}, []int{oldest[0].ID, oldest[1].ID})
+	})
+
+	t.Run("GetReadingHistory pages through records newest first", func(t *testing.T) {
+		user := createTestUser(t, db)
+		story := createTestStory(t, db, user.ID, "History story", 100)
+
+		readAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+		older := createTestReadingRecord(t, db, user.ID, story.ID, 100, readAt)
+		middle := createTestReadingRecord(t, db, user.ID, story.ID, 100, readAt.Add(time.Hour))
+		newest := createTestReadingRecord(t, db, user.ID, story.ID, 100, readAt.Add(2*time.Hour))
+
+		page, err := readingRepo.GetReadingHistory(user.ID, nil, 2)
+		require.NoError(t, err)
+		require.Len(t, page, 2)
+		assert.Equal(t, newest.ID, page[0].ID)
+		require.NotNil(t, page[0].Title)
+		assert.Equal(t, "History story", *page[0].Title)
+		assert.Equal(t, middle.ID, page[1].ID)
+
+		rest, err := readingRepo.GetReadingHistory(user.ID, &Keyset{Time: page[1].ReadAt, ID: page[1].ID}, 2)
+		require.NoError(t, err)
+		require.Len(t, rest, 1)
+		assert.Equal(t, older.ID, rest[0].ID)
+	})
+
+	t.Run("GetReadingHistory keeps records of purged stories", func(t *testing.T) {
+		user := createTestUser(t, db)
+		story := createTestStory(t, db, user.ID, "Purged story", 100)
+		record := createTestReadingRecord(t, db, user.ID, story.ID, 100, time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC))
+
+		// ゴミ箱の定期削除と同じく物理削除すると story_id が NULL になる
+		_, err := db.Exec("DELETE FROM stories WHERE id = $1", story.ID)
+		require.NoError(t, err)
+
+		history, err := readingRepo.GetReadingHistory(user.ID, nil, 10)
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.Equal(t, record.ID, history[0].ID)
+		assert.Nil(t, history[0].StoryID)
+		assert.Nil(t, history[0].Title)
+		assert.Equal(t, 100, history[0].WordCount)
+	})
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+)
+
+// IQuizRepository: quiz_questions / quiz_attempts テーブルの操作インターフェース
+type IQuizRepository interface {
+	ReplaceQuizQuestions(storyID int, questions []*model.QuizQuestion) error
+	GetQuizQuestions(storyID int) ([]*model.QuizQuestion, error)
+	CreateQuizAttempt(attempt *model.QuizAttempt) error
+}
+
+type sqlxQuizRepository struct {
+	DB *sqlx.DB
+}
+
+func NewQuizRepository(db *sqlx.DB) IQuizRepository {
+	return &sqlxQuizRepository{DB: db}
+}
+
+// ReplaceQuizQuestions はストーリーの既存の問題を削除し、新しい問題に置き換える
+func (r *sqlxQuizRepository) ReplaceQuizQuestions(storyID int, questions []*model.QuizQuestion) error {
+	tx, err := r.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`DELETE FROM quiz_questions WHERE story_id = $1`, storyID); err != nil {
+		return fmt.Errorf("failed to delete quiz questions: %w", err)
+	}
+
+	query := `
+		INSERT INTO quiz_questions(story_id, question_type, question, choices, correct_index, position)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	for _, q := range questions {
+		q.StoryID = storyID
+		err := tx.QueryRowx(query, storyID, q.QuestionType, q.Question, q.Choices, q.CorrectIndex, q.Position).Scan(&q.ID, &q.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create quiz question: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit quiz questions: %w", err)
+	}
+	return nil
+}
+
+func (r *sqlxQuizRepository) GetQuizQuestions(storyID int) ([]*model.QuizQuestion, error) {
+	query := `
+		SELECT id, story_id, question_type, question, choices, correct_index, position, created_at
+		FROM quiz_questions
+		WHERE story_id = $1
+		ORDER BY position ASC
+	`
+	var questions []*model.QuizQuestion
+	err := r.DB.Select(&questions, query, storyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiz questions: %w", err)
+	}
+	return questions, nil
+}
+
+func (r *sqlxQuizRepository) CreateQuizAttempt(attempt *model.QuizAttempt) error {
+	query := `
+		INSERT INTO quiz_attempts(user_id, story_id, answers, score, total, passed)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	err := r.DB.QueryRowx(query, attempt.UserID, attempt.StoryID, attempt.Answers, attempt.Score, attempt.Total, attempt.Passed).Scan(&attempt.ID, &attempt.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create quiz attempt: %w", err)
+	}
+	return nil
+}
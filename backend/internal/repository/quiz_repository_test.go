@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuizRepository(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewQuizRepository(db)
+
+	t.Run("ReplaceQuizQuestions and GetQuizQuestions", func(t *testing.T) {
+		user := createTestUser(t, db)
+		story := createTestStory(t, db, user.ID, "Quiz Story", 100)
+
+		first := []*model.QuizQuestion{
+			{QuestionType: model.QuestionTypeTrueFalse, Question: "Old question", Choices: pq.StringArray{"True", "False"}, CorrectIndex: 0, Position: 1},
+		}
+		require.NoError(t, repo.ReplaceQuizQuestions(story.ID, first))
+
+		second := []*model.QuizQuestion{
+			{QuestionType: model.QuestionTypeMultipleChoice, Question: "Q1", Choices: pq.StringArray{"A", "B", "C", "D"}, CorrectIndex: 3, Position: 1},
+			{QuestionType: model.QuestionTypeTrueFalse, Question: "Q2", Choices: pq.StringArray{"True", "False"}, CorrectIndex: 1, Position: 2},
+		}
+		require.NoError(t, repo.ReplaceQuizQuestions(story.ID, second))
+		assert.NotZero(t, second[0].ID)
+
+		questions, err := repo.GetQuizQuestions(story.ID)
+		require.NoError(t, err)
+		require.Len(t, questions, 2, "old questions should be replaced")
+		assert.Equal(t, "Q1", questions[0].Question)
+		assert.Equal(t, pq.StringArray{"A", "B", "C", "D"}, questions[0].Choices)
+		assert.Equal(t, 3, questions[0].CorrectIndex)
+		assert.Equal(t, "Q2", questions[1].Question)
+	})
+
+	t.Run("CreateQuizAttempt", func(t *testing.T) {
+		user := createTestUser(t, db)
+		story := createTestStory(t, db, user.ID, "Attempt Story", 100)
+
+		attempt := &model.QuizAttempt{
+			UserID:  user.ID,
+			StoryID: story.ID,
+			Answers: pq.Int64Array{1, 0},
+			Score:   1,
+			Total:   2,
+			Passed:  false,
+		}
+		require.NoError(t, repo.CreateQuizAttempt(attempt))
+		assert.NotZero(t, attempt.ID)
+		assert.NotZero(t, attempt.CreatedAt)
+
+		var count int
+		require.NoError(t, db.Get(&count, "SELECT COUNT(*) FROM quiz_attempts WHERE user_id = $1 AND story_id = $2", user.ID, story.ID))
+		assert.Equal(t, 1, count)
+	})
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -15,6 +16,7 @@ type IReadingRecordRepository interface {
 	CountReadingRecords(userID, storyID int) (int, error)
 	GetLatestReadingRecord(userID, storyID int) (*model.ReadingRecord, error)
 	DeleteReadingRecord(recordID int, userID int) error
+	GetReadingHistory(userID int, keyset *Keyset, limit int) ([]*model.ReadingHistoryItem, error)
 
 	GetUserTotalWordCount(userID int) (int, error)
 	GetWordCountInDateRange(userID int, start, end time.Time) (int, error)
@@ -75,6 +77,44 @@ func (r *sqlxReadingRecordRepository) DeleteReadingRecord(recordID int, userID i
 	return nil
 }
 
+// GetReadingHistory は読了履歴を新しい順に返す。(read_at, id) を基準にしたキーセットページネーションで、
+// idx_reading_records_user_id_read_at を利用する。結果は keyset の向きにかかわらず新しい順で返す。
+// 完全に削除したストーリーの記録も語数の集計と揃うよう含める (story_id と title は NULL)
+func (r *sqlxReadingRecordRepository) GetReadingHistory(userID int, keyset *Keyset, limit int) ([]*model.ReadingHistoryItem, error) {
+	where := "rr.user_id = $1"
+	args := []any{userID}
+
+	direction, operator := "DESC", "<"
+	if keyset != nil && keyset.Backward {
+		direction, operator = "ASC", ">"
+	}
+	if keyset != nil {
+		args = append(args, keyset.Time, keyset.ID)
+		where += fmt.Sprintf(" AND (rr.read_at, rr.id) %s ($2, $3)", operator)
+	}
+
+	args = append(args, limit)
+	query := fmt.Sprintf(`
+		SELECT rr.id, rr.story_id, s.title, rr.word_count, rr.read_at
+		FROM reading_records rr
+		LEFT JOIN stories s ON s.id = rr.story_id
+		WHERE %s
+		ORDER BY rr.read_at %s, rr.id %s
+		LIMIT $%d
+	`, where, direction, direction, len(args))
+
+	var history []*model.ReadingHistoryItem
+	err := r.DB.Select(&history, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reading history: %w", err)
+	}
+
+	if keyset != nil && keyset.Backward {
+		slices.Reverse(history)
+	}
+	return history, nil
+}
+
 // --- UserRepository から移管されたメソッド ---
 
 func (r *sqlxReadingRecordRepository) GetUserTotalWordCount(userID int) (int, error) {
@@ -35,6 +35,12 @@ func setupTestDB(t *testing.T) *sqlx.DB {
 		_, err = db.Exec("DELETE FROM stories")
 		require.NoError(t, err, "failed to cleanup stories table")
 
+		_, err = db.Exec("DELETE FROM series")
+		require.NoError(t, err, "failed to cleanup series table")
+
+		_, err = db.Exec("DELETE FROM prompt_templates")
+		require.NoError(t, err, "failed to cleanup prompt_templates table")
+
 		_, err = db.Exec("DELETE FROM users")
 		require.NoError(t, err, "failed to cleanup users table")
 
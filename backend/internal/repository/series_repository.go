@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+)
+
+// ISeriesRepository: series テーブルの操作インターフェース
+type ISeriesRepository interface {
+	CreateSeries(series *model.Series) error
+	CreateSeriesWithChapters(series *model.Series, chapters []*model.Story) error
+	GetUserSeries(seriesID, userID int) (*model.Series, error)
+	UpdateSeriesSummary(seriesID int, summary string, summarizedThrough int) error
+	GetSeriesChapters(seriesID int) ([]*model.SeriesChapter, error)
+	GetLastChapterNumber(seriesID int) (int, error)
+}
+
+type sqlxSeriesRepository struct {
+	DB *sqlx.DB
+}
+
+func NewSeriesRepository(db *sqlx.DB) ISeriesRepository {
+	return &sqlxSeriesRepository{DB: db}
+}
+
+func (r *sqlxSeriesRepository) CreateSeries(series *model.Series) error {
+	query := `
+		INSERT INTO series(user_id, title)
+		VALUES ($1, $2)
+		RETURNING id, summary, summarized_through, created_at, updated_at
+	`
+	err := r.DB.QueryRowx(query, series.UserID, series.Title).Scan(&series.ID, &series.Summary, &series.SummarizedThrough, &series.CreatedAt, &series.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create series: %w", err)
+	}
+	return nil
+}
+
+// CreateSeriesWithChapters は連載と各章のストーリーを 1 つのトランザクションで保存する。章番号は chapters の順に 1 から振る
+func (r *sqlxSeriesRepository) CreateSeriesWithChapters(series *model.Series, chapters []*model.Story) error {
+	tx, err := r.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO series(user_id, title)
+		VALUES ($1, $2)
+		RETURNING id, summary, summarized_through, created_at, updated_at
+	`
+	err = tx.QueryRowx(query, series.UserID, series.Title).Scan(&series.ID, &series.Summary, &series.SummarizedThrough, &series.CreatedAt, &series.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create series: %w", err)
+	}
+
+	for i, story := range chapters {
+		chapterNumber := i + 1
+		story.SeriesID = &series.ID
+		story.ChapterNumber = &chapterNumber
+		if err := insertStory(tx, story); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *sqlxSeriesRepository) GetUserSeries(seriesID, userID int) (*model.Series, error) {
+	query := `
+		SELECT id, user_id, title, summary, summarized_through, created_at, updated_at
+		FROM series
+		WHERE id = $1 AND user_id = $2
+	`
+	var series model.Series
+	err := r.DB.Get(&series, query, seriesID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user series: %w", err)
+	}
+	return &series, nil
+}
+
+func (r *sqlxSeriesRepository) UpdateSeriesSummary(seriesID int, summary string, summarizedThrough int) error {
+	query := `
+		UPDATE series
+		SET summary = $1, summarized_through = $2
+		WHERE id = $3
+	`
+	_, err := r.DB.Exec(query, summary, summarizedThrough, seriesID)
+	if err != nil {
+		return fmt.Errorf("failed to update series summary: %w", err)
+	}
+	return nil
+}
+
+func (r *sqlxSeriesRepository) GetSeriesChapters(seriesID int) ([]*model.SeriesChapter, error) {
+	query := `
+		SELECT id, title, chapter_number, word_count
+		FROM stories
+		WHERE series_id = $1 AND deleted_at IS NULL
+		ORDER BY chapter_number ASC
+	`
+	var chapters []*model.SeriesChapter
+	err := r.DB.Select(&chapters, query, seriesID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get series chapters: %w", err)
+	}
+	return chapters, nil
+}
+
+// GetLastChapterNumber はゴミ箱の章も含めた最大の章番号を返す。
+// ゴミ箱の章は復元できるため、新しい章の番号と重ならないようにする
+func (r *sqlxSeriesRepository) GetLastChapterNumber(seriesID int) (int, error) {
+	var last int
+	err := r.DB.Get(&last, `SELECT COALESCE(MAX(chapter_number), 0) FROM stories WHERE series_id = $1`, seriesID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last chapter number: %w", err)
+	}
+	return last, nil
+}
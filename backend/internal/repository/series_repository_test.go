@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeriesRepository(t *testing.T) {
+	db := setupTestDB(t)
+
+	seriesRepo := NewSeriesRepository(db)
+	storyRepo := NewStoryRepository(db)
+
+	t.Run("CreateSeries and GetUserSeries", func(t *testing.T) {
+		user := createTestUser(t, db)
+		series := &model.Series{UserID: user.ID, Title: "A Series"}
+
+		err := seriesRepo.CreateSeries(series)
+		require.NoError(t, err)
+		assert.NotZero(t, series.ID)
+
+		fetched, err := seriesRepo.GetUserSeries(series.ID, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "A Series", fetched.Title)
+		assert.Equal(t, 0, fetched.SummarizedThrough)
+
+		// 他のユーザーからは取得できない
+		other := createTestUser(t, db)
+		_, err = seriesRepo.GetUserSeries(series.ID, other.ID)
+		assert.Error(t, err)
+	})
+
+	t.Run("UpdateSeriesSummary", func(t *testing.T) {
+		user := createTestUser(t, db)
+		series := &model.Series{UserID: user.ID, Title: "Summarized"}
+		require.NoError(t, seriesRepo.CreateSeries(series))
+
+		err := seriesRepo.UpdateSeriesSummary(series.ID, "So far...", 2)
+		require.NoError(t, err)
+
+		fetched, err := seriesRepo.GetUserSeries(series.ID, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "So far...", fetched.Summary)
+		assert.Equal(t, 2, fetched.SummarizedThrough)
+	})
+
+	t.Run("GetSeriesChapters", func(t *testing.T) {
+		user := createTestUser(t, db)
+		series := &model.Series{UserID: user.ID, Title: "Chapters"}
+		require.NoError(t, seriesRepo.CreateSeries(series))
+
+		first := createTestStory(t, db, user.ID, "Chapter 1", 100)
+		require.NoError(t, storyRepo.SetStorySeries(first.ID, series.ID, 1))
+
+		chapterTwo := 2
+		second := &model.Story{
+			UserID:        user.ID,
+			Title:         "Chapter 2",
+			Content:       "Content for Chapter 2",
+			WordCount:     200,
+			SeriesID:      &series.ID,
+			ChapterNumber: &chapterTwo,
+		}
+		require.NoError(t, storyRepo.CreateStory(second))
+
+		// 連載に属さないストーリーは含まれない
+		createTestStory(t, db, user.ID, "Standalone", 50)
+
+		chapters, err := seriesRepo.GetSeriesChapters(series.ID)
+		require.NoError(t, err)
+		require.Len(t, chapters, 2)
+		assert.Equal(t, first.ID, chapters[0].StoryID)
+		assert.Equal(t, 1, chapters[0].ChapterNumber)
+		assert.Equal(t, second.ID, chapters[1].StoryID)
+		assert.Equal(t, 200, chapters[1].WordCount)
+
+		fetched, err := storyRepo.GetUserStory(first.ID, user.ID)
+		require.NoError(t, err)
+		require.NotNil(t, fetched.SeriesID)
+		assert.Equal(t, series.ID, *fetched.SeriesID)
+	})
+
+	t.Run("CreateSeriesWithChapters", func(t *testing.T) {
+		user := createTestUser(t, db)
+		series := &model.Series{UserID: user.ID, Title: "Imported Book"}
+		chapters := []*model.Story{
+			{UserID: user.ID, Title: "One", Content: "First chapter.", WordCount: 2, Source: model.StorySourceImported},
+			{UserID: user.ID, Title: "Two", Content: "Second chapter.", WordCount: 2, Source: model.StorySourceImported},
+		}
+
+		err := seriesRepo.CreateSeriesWithChapters(series, chapters)
+		require.NoError(t, err)
+		assert.NotZero(t, series.ID)
+
+		fetched, err := seriesRepo.GetSeriesChapters(series.ID)
+		require.NoError(t, err)
+		require.Len(t, fetched, 2)
+		assert.Equal(t, chapters[0].ID, fetched[0].StoryID)
+		assert.Equal(t, 2, fetched[1].ChapterNumber)
+
+		story, err := storyRepo.GetUserStory(chapters[1].ID, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.StorySourceImported, story.Source)
+	})
+}
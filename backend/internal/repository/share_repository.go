@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+)
+
+// IShareRepository: story_shares テーブルの操作インターフェース
+type IShareRepository interface {
+	CreateShare(share *model.StoryShare) error
+	ListShares(storyID int) ([]*model.StoryShare, error)
+	DeleteShare(shareID, storyID int) error
+	GetSharedStory(token string) (*model.SharedStory, error)
+}
+
+type sqlxShareRepository struct {
+	DB *sqlx.DB
+}
+
+func NewShareRepository(db *sqlx.DB) IShareRepository {
+	return &sqlxShareRepository{DB: db}
+}
+
+func (r *sqlxShareRepository) CreateShare(share *model.StoryShare) error {
+	query := `
+		INSERT INTO story_shares(story_id, token, expires_at)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+	err := r.DB.QueryRowx(query, share.StoryID, share.Token, share.ExpiresAt).Scan(&share.ID, &share.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create share: %w", err)
+	}
+	return nil
+}
+
+// ListShares はストーリーの共有リンクを新しい順に返す。期限切れのものも含める
+func (r *sqlxShareRepository) ListShares(storyID int) ([]*model.StoryShare, error) {
+	query := `
+		SELECT id, story_id, token, expires_at, created_at
+		FROM story_shares
+		WHERE story_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+	var shares []*model.StoryShare
+	if err := r.DB.Select(&shares, query, storyID); err != nil {
+		return nil, fmt.Errorf("failed to list shares: %w", err)
+	}
+	return shares, nil
+}
+
+// DeleteShare は共有リンクを無効にする。ストーリーの共有リンクでない場合は sql.ErrNoRows を返す
+func (r *sqlxShareRepository) DeleteShare(shareID, storyID int) error {
+	var id int
+	err := r.DB.Get(&id, `DELETE FROM story_shares WHERE id = $1 AND story_id = $2 RETURNING id`, shareID, storyID)
+	if err != nil {
+		return fmt.Errorf("failed to delete share: %w", err)
+	}
+	return nil
+}
+
+// GetSharedStory は共有リンクのストーリーを返す。期限切れ・ゴミ箱のストーリーの場合は sql.ErrNoRows を返す
+func (r *sqlxShareRepository) GetSharedStory(token string) (*model.SharedStory, error) {
+	query := `
+		SELECT s.title, s.content, s.word_count
+		FROM story_shares sh
+		JOIN stories s ON s.id = sh.story_id AND s.deleted_at IS NULL
+		WHERE sh.token = $1 AND (sh.expires_at IS NULL OR sh.expires_at > NOW())
+	`
+	var story model.SharedStory
+	if err := r.DB.Get(&story, query, token); err != nil {
+		return nil, fmt.Errorf("failed to get shared story: %w", err)
+	}
+	return &story, nil
+}
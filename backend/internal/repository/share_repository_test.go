@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareRepository(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewShareRepository(db)
+	storyRepo := NewStoryRepository(db)
+
+	t.Run("GetSharedStory returns the story for a valid token", func(t *testing.T) {
+		user := createTestUser(t, db)
+		story := createTestStory(t, db, user.ID, "Shared", 3)
+
+		share := &model.StoryShare{StoryID: story.ID, Token: "valid-token"}
+		require.NoError(t, repo.CreateShare(share))
+		assert.NotZero(t, share.ID)
+
+		shared, err := repo.GetSharedStory("valid-token")
+		require.NoError(t, err)
+		assert.Equal(t, &model.SharedStory{Title: "Shared", Content: story.Content, WordCount: 3}, shared)
+
+		shares, err := repo.ListShares(story.ID)
+		require.NoError(t, err)
+		require.Len(t, shares, 1)
+		assert.Equal(t, "valid-token", shares[0].Token)
+	})
+
+	t.Run("GetSharedStory hides expired, revoked and trashed shares", func(t *testing.T) {
+		user := createTestUser(t, db)
+		story := createTestStory(t, db, user.ID, "Hidden", 3)
+
+		past := time.Now().Add(-time.Hour)
+		require.NoError(t, repo.CreateShare(&model.StoryShare{StoryID: story.ID, Token: "expired-token", ExpiresAt: &past}))
+		_, err := repo.GetSharedStory("expired-token")
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		revoked := &model.StoryShare{StoryID: story.ID, Token: "revoked-token"}
+		require.NoError(t, repo.CreateShare(revoked))
+		require.NoError(t, repo.DeleteShare(revoked.ID, story.ID))
+		_, err = repo.GetSharedStory("revoked-token")
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		require.NoError(t, repo.CreateShare(&model.StoryShare{StoryID: story.ID, Token: "trashed-token"}))
+		require.NoError(t, storyRepo.DeleteStory(story.ID))
+		_, err = repo.GetSharedStory("trashed-token")
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("DeleteShare only deletes shares of the given story", func(t *testing.T) {
+		user := createTestUser(t, db)
+		story := createTestStory(t, db, user.ID, "Mine", 3)
+		other := createTestStory(t, db, user.ID, "Other", 3)
+
+		share := &model.StoryShare{StoryID: story.ID, Token: "mine-token"}
+		require.NoError(t, repo.CreateShare(share))
+
+		assert.ErrorIs(t, repo.DeleteShare(share.ID, other.ID), sql.ErrNoRows)
+		_, err := repo.GetSharedStory("mine-token")
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+)
+
+// IShelfRepository: shelves / shelf_stories テーブルの操作インターフェース
+type IShelfRepository interface {
+	CreateShelf(shelf *model.Shelf) error
+	GetUserShelf(shelfID, userID int) (*model.Shelf, error)
+	ListShelves(userID int) ([]*model.Shelf, error)
+	RenameShelf(shelf *model.Shelf) error
+	DeleteShelf(shelfID int) error
+	GetShelfItems(shelfID int) ([]*model.ShelfItem, error)
+	AddStory(shelfID, storyID int) error
+	RemoveStory(shelfID, storyID int) error
+	ReorderStories(shelfID int, storyIDs []int) error
+}
+
+type sqlxShelfRepository struct {
+	DB *sqlx.DB
+}
+
+func NewShelfRepository(db *sqlx.DB) IShelfRepository {
+	return &sqlxShelfRepository{DB: db}
+}
+
+// shelfProgressQuery は棚ごとに読了済みのストーリー数と残りの語数を集計する。ゴミ箱のストーリーは数えない
+const shelfProgressQuery = `
+	SELECT sh.id, sh.user_id, sh.name, sh.created_at, sh.updated_at,
+		COUNT(ss.story_id) AS story_count,
+		COUNT(ss.story_id) FILTER (WHERE reads.is_read) AS read_story_count,
+		COALESCE(SUM(st.word_count), 0) AS total_word_count,
+		COALESCE(SUM(st.word_count) FILTER (WHERE NOT reads.is_read), 0) AS remaining_word_count
+	FROM shelves sh
+	LEFT JOIN (
+		shelf_stories ss
+		JOIN stories st ON st.id = ss.story_id AND st.deleted_at IS NULL
+	) ON ss.shelf_id = sh.id
+	LEFT JOIN LATERAL (
+		SELECT EXISTS (
+			SELECT 1 FROM reading_records rr
+			WHERE rr.user_id = sh.user_id AND rr.story_id = ss.story_id
+		) AS is_read
+	) reads ON TRUE
+`
+
+func (r *sqlxShelfRepository) CreateShelf(shelf *model.Shelf) error {
+	query := `
+		INSERT INTO shelves(user_id, name)
+		VALUES ($1, $2)
+		RETURNING id, created_at, updated_at
+	`
+	err := r.DB.QueryRowx(query, shelf.UserID, shelf.Name).Scan(&shelf.ID, &shelf.CreatedAt, &shelf.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create shelf: %w", err)
+	}
+	return nil
+}
+
+func (r *sqlxShelfRepository) GetUserShelf(shelfID, userID int) (*model.Shelf, error) {
+	query := shelfProgressQuery + `
+		WHERE sh.id = $1 AND sh.user_id = $2
+		GROUP BY sh.id
+	`
+	var shelf model.Shelf
+	err := r.DB.Get(&shelf, query, shelfID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shelf: %w", err)
+	}
+	return &shelf, nil
+}
+
+func (r *sqlxShelfRepository) ListShelves(userID int) ([]*model.Shelf, error) {
+	query := shelfProgressQuery + `
+		WHERE sh.user_id = $1
+		GROUP BY sh.id
+		ORDER BY sh.created_at ASC, sh.id ASC
+	`
+	var shelves []*model.Shelf
+	err := r.DB.Select(&shelves, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shelves: %w", err)
+	}
+	return shelves, nil
+}
+
+func (r *sqlxShelfRepository) RenameShelf(shelf *model.Shelf) error {
+	query := `
+		UPDATE shelves
+		SET name = $1
+		WHERE id = $2
+		RETURNING updated_at
+	`
+	err := r.DB.QueryRowx(query, shelf.Name, shelf.ID).Scan(&shelf.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to rename shelf: %w", err)
+	}
+	return nil
+}
+
+func (r *sqlxShelfRepository) DeleteShelf(shelfID int) error {
+	_, err := r.DB.Exec(`DELETE FROM shelves WHERE id = $1`, shelfID)
+	if err != nil {
+		return fmt.Errorf("failed to delete shelf: %w", err)
+	}
+	return nil
+}
+
+// GetShelfItems は棚のストーリーを並び順に、読了回数とともに返す。
+// ゴミ箱のストーリーは除き、位置は表示されるものだけで 1 から振り直す
+func (r *sqlxShelfRepository) GetShelfItems(shelfID int) ([]*model.ShelfItem, error) {
+	query := `
+		SELECT ROW_NUMBER() OVER (ORDER BY ss.position) AS position, st.id AS story_id, st.title, st.word_count, st.level,
+			reads.read_count, reads.last_read_at
+		FROM shelf_stories ss
+		JOIN stories st ON st.id = ss.story_id AND st.deleted_at IS NULL
+		LEFT JOIN LATERAL (
+			SELECT COUNT(*) AS read_count, MAX(rr.read_at) AS last_read_at
+			FROM reading_records rr
+			WHERE rr.user_id = st.user_id AND rr.story_id = st.id
+		) reads ON TRUE
+		WHERE ss.shelf_id = $1
+		ORDER BY ss.position ASC
+	`
+	var items []*model.ShelfItem
+	err := r.DB.Select(&items, query, shelfID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shelf items: %w", err)
+	}
+	return items, nil
+}
+
+// AddStory はストーリーを棚の末尾に追加する。既に棚にある場合は何もしない
+func (r *sqlxShelfRepository) AddStory(shelfID, storyID int) error {
+	query := `
+		INSERT INTO shelf_stories(shelf_id, story_id, position)
+		SELECT $1, $2, COALESCE(MAX(position), 0) + 1
+		FROM shelf_stories
+		WHERE shelf_id = $1
+		ON CONFLICT (shelf_id, story_id) DO NOTHING
+	`
+	tx, err := r.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := lockShelf(tx, shelfID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(query, shelfID, storyID); err != nil {
+		return fmt.Errorf("failed to add story to shelf: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// lockShelf は棚の行をロックし、同じ棚への追加・削除・並べ替えが同時に位置を計算しないようにする
+func lockShelf(tx *sqlx.Tx, shelfID int) error {
+	if err := tx.Get(new(int), `SELECT id FROM shelves WHERE id = $1 FOR UPDATE`, shelfID); err != nil {
+		return fmt.Errorf("failed to lock shelf: %w", err)
+	}
+	return nil
+}
+
+// RemoveStory はストーリーを棚から外し、後ろのストーリーを詰める。棚にない場合は sql.ErrNoRows を返す
+func (r *sqlxShelfRepository) RemoveStory(shelfID, storyID int) error {
+	tx, err := r.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := lockShelf(tx, shelfID); err != nil {
+		return err
+	}
+
+	var position int
+	err = tx.Get(&position, `DELETE FROM shelf_stories WHERE shelf_id = $1 AND story_id = $2 RETURNING position`, shelfID, storyID)
+	if err != nil {
+		return fmt.Errorf("failed to remove story from shelf: %w", err)
+	}
+
+	_, err = tx.Exec(`UPDATE shelf_stories SET position = position - 1 WHERE shelf_id = $1 AND position > $2`, shelfID, position)
+	if err != nil {
+		return fmt.Errorf("failed to shift shelf positions: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ReorderStories は storyIDs の順に位置を振り直す。storyIDs は棚のストーリー (ゴミ箱のものを除く) をすべて含む必要がある。
+// ゴミ箱のストーリーは復元に備えて元の並び順のまま末尾に回す
+func (r *sqlxShelfRepository) ReorderStories(shelfID int, storyIDs []int) error {
+	query := `
+		UPDATE shelf_stories ss
+		SET position = o.position
+		FROM unnest($2::int[]) WITH ORDINALITY AS o(story_id, position)
+		WHERE ss.shelf_id = $1 AND ss.story_id = o.story_id
+	`
+	restQuery := `
+		UPDATE shelf_stories ss
+		SET position = rest.position
+		FROM (
+			SELECT story_id, $3 + ROW_NUMBER() OVER (ORDER BY position) AS position
+			FROM shelf_stories
+			WHERE shelf_id = $1 AND story_id <> ALL($2::int[])
+		) rest
+		WHERE ss.shelf_id = $1 AND ss.story_id = rest.story_id
+	`
+	ids := make(pq.Int64Array, len(storyIDs))
+	for i, id := range storyIDs {
+		ids[i] = int64(id)
+	}
+
+	tx, err := r.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := lockShelf(tx, shelfID); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(query, shelfID, ids)
+	if err != nil {
+		return fmt.Errorf("failed to reorder shelf: %w", err)
+	}
+	// 途中で棚の内容が変わっていた場合は位置が欠けるため取り消す
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to reorder shelf: %w", err)
+	}
+	if int(n) != len(storyIDs) {
+		return fmt.Errorf("failed to reorder shelf: %w", sql.ErrNoRows)
+	}
+
+	if _, err := tx.Exec(restQuery, shelfID, ids, len(storyIDs)); err != nil {
+		return fmt.Errorf("failed to reorder shelf: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
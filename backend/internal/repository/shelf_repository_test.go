@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShelfRepository(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewShelfRepository(db)
+
+	storyIDs := func(items []*model.ShelfItem) []int {
+		ids := []int{}
+		for _, item := range items {
+			ids = append(ids, item.StoryID)
+		}
+		return ids
+	}
+
+	t.Run("AddStory, progress and ReorderStories", func(t *testing.T) {
+		user := createTestUser(t, db)
+		first := createTestStory(t, db, user.ID, "First", 100)
+		second := createTestStory(t, db, user.ID, "Second", 200)
+		third := createTestStory(t, db, user.ID, "Third", 300)
+		createTestReadingRecord(t, db, user.ID, second.ID, 200, time.Now())
+
+		shelf := &model.Shelf{UserID: user.ID, Name: "Week 1"}
+		require.NoError(t, repo.CreateShelf(shelf))
+		for _, story := range []*model.Story{first, second, third} {
+			require.NoError(t, repo.AddStory(shelf.ID, story.ID))
+		}
+		// 既に棚にあるストーリーは位置を変えない
+		require.NoError(t, repo.AddStory(shelf.ID, first.ID))
+
+		fetched, err := repo.GetUserShelf(shelf.ID, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 3, fetched.StoryCount)
+		assert.Equal(t, 1, fetched.ReadStoryCount)
+		assert.Equal(t, 600, fetched.TotalWordCount)
+		assert.Equal(t, 400, fetched.RemainingWordCount)
+
+		items, err := repo.GetShelfItems(shelf.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []int{first.ID, second.ID, third.ID}, storyIDs(items))
+		assert.Equal(t, 1, items[1].ReadCount)
+
+		require.NoError(t, repo.ReorderStories(shelf.ID, []int{third.ID, first.ID, second.ID}))
+		items, err = repo.GetShelfItems(shelf.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []int{third.ID, first.ID, second.ID}, storyIDs(items))
+		assert.Equal(t, []int{1, 2, 3}, []int{items[0].Position, items[1].Position, items[2].Position})
+
+		require.NoError(t, repo.RemoveStory(shelf.ID, third.ID))
+		items, err = repo.GetShelfItems(shelf.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []int{first.ID, second.ID}, storyIDs(items))
+		assert.Equal(t, 1, items[0].Position)
+
+		assert.ErrorIs(t, repo.RemoveStory(shelf.ID, third.ID), sql.ErrNoRows)
+	})
+
+	t.Run("the same story can be on multiple shelves", func(t *testing.T) {
+		user := createTestUser(t, db)
+		story := createTestStory(t, db, user.ID, "Shared", 100)
+
+		weekOne := &model.Shelf{UserID: user.ID, Name: "Week 1"}
+		review := &model.Shelf{UserID: user.ID, Name: "Review"}
+		require.NoError(t, repo.CreateShelf(weekOne))
+		require.NoError(t, repo.CreateShelf(review))
+		require.NoError(t, repo.AddStory(weekOne.ID, story.ID))
+		require.NoError(t, repo.AddStory(review.ID, story.ID))
+
+		shelves, err := repo.ListShelves(user.ID)
+		require.NoError(t, err)
+		require.Len(t, shelves, 2)
+		assert.Equal(t, 1, shelves[0].StoryCount)
+		assert.Equal(t, 1, shelves[1].StoryCount)
+
+		// 空の棚も 0 件として集計される
+		empty := &model.Shelf{UserID: user.ID, Name: "Empty"}
+		require.NoError(t, repo.CreateShelf(empty))
+		fetched, err := repo.GetUserShelf(empty.ID, user.ID)
+		require.NoError(t, err)
+		assert.Zero(t, fetched.StoryCount)
+		assert.Zero(t, fetched.RemainingWordCount)
+
+		_, err = repo.GetUserShelf(empty.ID, user.ID+1)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("trashed stories are hidden and kept after reordering", func(t *testing.T) {
+		user := createTestUser(t, db)
+		storyRepo := NewStoryRepository(db)
+		first := createTestStory(t, db, user.ID, "First", 100)
+		trashed := createTestStory(t, db, user.ID, "Trashed", 200)
+		last := createTestStory(t, db, user.ID, "Last", 300)
+
+		shelf := &model.Shelf{UserID: user.ID, Name: "Week 1"}
+		require.NoError(t, repo.CreateShelf(shelf))
+		for _, story := range []*model.Story{first, trashed, last} {
+			require.NoError(t, repo.AddStory(shelf.ID, story.ID))
+		}
+		require.NoError(t, storyRepo.DeleteStory(trashed.ID))
+
+		fetched, err := repo.GetUserShelf(shelf.ID, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, fetched.StoryCount)
+		assert.Equal(t, 400, fetched.TotalWordCount)
+
+		items, err := repo.GetShelfItems(shelf.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []int{first.ID, last.ID}, storyIDs(items))
+		assert.Equal(t, 2, items[1].Position)
+
+		require.NoError(t, repo.ReorderStories(shelf.ID, []int{last.ID, first.ID}))
+
+		// 復元するとゴミ箱に移す前の棚に末尾で戻る
+		_, err = storyRepo.RestoreStory(trashed.ID, user.ID)
+		require.NoError(t, err)
+		items, err = repo.GetShelfItems(shelf.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []int{last.ID, first.ID, trashed.ID}, storyIDs(items))
+	})
+
+	t.Run("concurrent AddStory calls get distinct positions", func(t *testing.T) {
+		user := createTestUser(t, db)
+		shelf := &model.Shelf{UserID: user.ID, Name: "Concurrent"}
+		require.NoError(t, repo.CreateShelf(shelf))
+
+		const count = 8
+		stories := make([]*model.Story, count)
+		for i := range stories {
+			stories[i] = createTestStory(t, db, user.ID, "Story", 100)
+		}
+
+		var wg sync.WaitGroup
+		errs := make(chan error, count)
+		for _, story := range stories {
+			wg.Add(1)
+			go func(storyID int) {
+				defer wg.Done()
+				errs <- repo.AddStory(shelf.ID, storyID)
+			}(story.ID)
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		items, err := repo.GetShelfItems(shelf.ID)
+		require.NoError(t, err)
+		require.Len(t, items, count)
+		for i, item := range items {
+			assert.Equal(t, i+1, item.Position)
+		}
+	})
+}
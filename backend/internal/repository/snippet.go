@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// ts_headline は本文をそのまま返すため、一致箇所には本文に現れない私用領域の文字で目印を付けておき、
+// HTML としてエスケープしてから <mark> に置き換える
+const (
+	snippetStartSel = "\uE000"
+	snippetStopSel  = "\uE001"
+)
+
+// snippetOptions は ts_headline で本文から抜き出すスニペットの設定
+var snippetOptions = fmt.Sprintf("StartSel=%s, StopSel=%s, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=\" … \"", snippetStartSel, snippetStopSel)
+
+var snippetMarkReplacer = strings.NewReplacer(snippetStartSel, "<mark>", snippetStopSel, "</mark>")
+
+// escapeSnippet は ts_headline の結果を HTML としてエスケープし、一致箇所だけを <mark> で囲む。
+// 取り込んだ本文に含まれるタグなどがそのまま HTML として解釈されないようにする
+func escapeSnippet(snippet *string) *string {
+	if snippet == nil {
+		return nil
+	}
+	escaped := snippetMarkReplacer.Replace(html.EscapeString(*snippet))
+	return &escaped
+}
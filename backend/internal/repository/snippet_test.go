@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeSnippet(t *testing.T) {
+	snippet := "a <script>alert(1)</script> & " + snippetStartSel + "volcano" + snippetStopSel + " erupts"
+
+	escaped := escapeSnippet(&snippet)
+
+	assert.Equal(t, "a &lt;script&gt;alert(1)&lt;/script&gt; &amp; <mark>volcano</mark> erupts", *escaped)
+	assert.Nil(t, escapeSnippet(nil))
+}
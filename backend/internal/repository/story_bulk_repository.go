@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+)
+
+// BulkAction はストーリーの一括操作の種類
+type BulkAction string
+
+const (
+	BulkActionDelete      BulkAction = "delete"
+	BulkActionTag         BulkAction = "tag"
+	BulkActionUntag       BulkAction = "untag"
+	BulkActionMarkRead    BulkAction = "mark_read"
+	BulkActionMoveToShelf BulkAction = "move_to_shelf"
+)
+
+// BulkOperation は一括操作の内容。TagID は tag / untag、ShelfID は move_to_shelf の場合のみ使う
+type BulkOperation struct {
+	Action   BulkAction
+	StoryIDs []int
+	TagID    int
+	ShelfID  int
+}
+
+// bulkTarget は一括操作の対象として所有権を確認したストーリー
+type bulkTarget struct {
+	ID         int  `db:"id"`
+	IsFavorite bool `db:"is_favorite"`
+}
+
+// bulkActionQueries は一括操作ごとの更新クエリ。$1 は対象ストーリーの ID 配列、$2 はユーザー・タグ・本棚の ID
+var bulkActionQueries = map[BulkAction]string{
+	BulkActionDelete: `UPDATE stories SET deleted_at = NOW() WHERE id = ANY($1::int[]) AND user_id = $2`,
+	BulkActionTag: `
+		INSERT INTO story_tags(story_id, tag_id)
+		SELECT id, $2::int FROM unnest($1::int[]) AS t(id)
+		ON CONFLICT (story_id, tag_id) DO NOTHING
+	`,
+	BulkActionUntag: `DELETE FROM story_tags WHERE story_id = ANY($1::int[]) AND tag_id = $2`,
+	BulkActionMarkRead: `
+		INSERT INTO reading_records(user_id, story_id, word_count)
+		SELECT user_id, id, word_count FROM stories WHERE id = ANY($1::int[]) AND user_id = $2
+	`,
+	// 指定順に末尾へ追加する。既に棚にあるストーリーはそのままの位置に残す
+	BulkActionMoveToShelf: `
+		INSERT INTO shelf_stories(shelf_id, story_id, position)
+		SELECT $2::int, t.id,
+			(SELECT COALESCE(MAX(position), 0) FROM shelf_stories WHERE shelf_id = $2) + ROW_NUMBER() OVER (ORDER BY t.ord)
+		FROM unnest($1::int[]) WITH ORDINALITY AS t(id, ord)
+		WHERE NOT EXISTS (SELECT 1 FROM shelf_stories ss WHERE ss.shelf_id = $2 AND ss.story_id = t.id)
+	`,
+}
+
+// IsValid は定義済みの一括操作かどうかを返す
+func (a BulkAction) IsValid() bool {
+	_, ok := bulkActionQueries[a]
+	return ok
+}
+
+// BulkUpdateStories はユーザーのストーリーに一括操作を 1 つのトランザクションで適用し、指定順にストーリーごとの結果を返す。
+// ユーザーのストーリーでないものやゴミ箱のものは not_found、お気に入りは削除の対象外として skipped になる。
+// 操作先のタグ・本棚がユーザーのものでない場合は sql.ErrNoRows を返す
+func (r *sqlxStoryRepository) BulkUpdateStories(userID int, op BulkOperation) ([]*model.BulkStoryResult, error) {
+	tx, err := r.DB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	param := userID
+	switch op.Action {
+	case BulkActionTag, BulkActionUntag:
+		param = op.TagID
+		if err := tx.Get(new(int), `SELECT id FROM tags WHERE id = $1 AND user_id = $2`, op.TagID, userID); err != nil {
+			return nil, fmt.Errorf("failed to get tag: %w", err)
+		}
+	case BulkActionMoveToShelf:
+		param = op.ShelfID
+		// 末尾の位置を確定させるため棚をロックする
+		if err := tx.Get(new(int), `SELECT id FROM shelves WHERE id = $1 AND user_id = $2 FOR UPDATE`, op.ShelfID, userID); err != nil {
+			return nil, fmt.Errorf("failed to get shelf: %w", err)
+		}
+	}
+
+	ids := make(pq.Int64Array, len(op.StoryIDs))
+	for i, id := range op.StoryIDs {
+		ids[i] = int64(id)
+	}
+	var targets []bulkTarget
+	query := `
+		SELECT id, is_favorite
+		FROM stories
+		WHERE user_id = $1 AND deleted_at IS NULL AND id = ANY($2::int[])
+		FOR UPDATE
+	`
+	if err := tx.Select(&targets, query, userID, ids); err != nil {
+		return nil, fmt.Errorf("failed to get bulk target stories: %w", err)
+	}
+	owned := make(map[int]bool, len(targets))
+	for _, target := range targets {
+		owned[target.ID] = target.IsFavorite
+	}
+
+	results := make([]*model.BulkStoryResult, 0, len(op.StoryIDs))
+	applied := make(pq.Int64Array, 0, len(targets))
+	seen := make(map[int]bool, len(op.StoryIDs))
+	for _, id := range op.StoryIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		isFavorite, ok := owned[id]
+		switch {
+		case !ok:
+			results = append(results, &model.BulkStoryResult{StoryID: id, Status: model.BulkStatusNotFound})
+		case op.Action == BulkActionDelete && isFavorite:
+			results = append(results, &model.BulkStoryResult{StoryID: id, Status: model.BulkStatusSkipped, Reason: "favorite"})
+		default:
+			results = append(results, &model.BulkStoryResult{StoryID: id, Status: model.BulkStatusOK})
+			applied = append(applied, int64(id))
+		}
+	}
+
+	if len(applied) > 0 {
+		if _, err := tx.Exec(bulkActionQueries[op.Action], applied, param); err != nil {
+			return nil, fmt.Errorf("failed to apply bulk %s: %w", op.Action, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return results, nil
+}
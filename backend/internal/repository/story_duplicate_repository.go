@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+)
+
+// ListStoryFingerprints はユーザーのストーリー (ゴミ箱のものを除く) の SimHash を作成日時の古い順に返す。
+// SimHash をまだ計算していないストーリーは含めない
+func (r *sqlxStoryRepository) ListStoryFingerprints(userID int) ([]*model.StoryFingerprint, error) {
+	query := `
+		SELECT id, title, word_count, level, created_at, simhash
+		FROM stories
+		WHERE user_id = $1 AND deleted_at IS NULL AND simhash IS NOT NULL
+		ORDER BY created_at ASC, id ASC
+	`
+	var fingerprints []*model.StoryFingerprint
+	if err := r.DB.Select(&fingerprints, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to list story fingerprints: %w", err)
+	}
+	return fingerprints, nil
+}
+
+// BackfillSimhashes は SimHash を計算していない (simhash 列の追加前に作成した) ストーリーを最大 limit 件計算して保存し、件数を返す。
+// 並行して実行された場合は互いに別のストーリーを処理する
+func (r *sqlxStoryRepository) BackfillSimhashes(limit int) (int, error) {
+	tx, err := r.DB.Beginx()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var missing []struct {
+		ID      int64  `db:"id"`
+		Content string `db:"content"`
+	}
+	query := `
+		SELECT id, content
+		FROM stories
+		WHERE simhash IS NULL
+		ORDER BY id ASC
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`
+	if err := tx.Select(&missing, query, limit); err != nil {
+		return 0, fmt.Errorf("failed to get stories without simhash: %w", err)
+	}
+	if len(missing) == 0 {
+		return 0, nil
+	}
+
+	ids := make(pq.Int64Array, len(missing))
+	hashes := make(pq.Int64Array, len(missing))
+	for i, story := range missing {
+		ids[i] = story.ID
+		hashes[i] = contentSimhash(story.Content)
+	}
+	updateQuery := `
+		UPDATE stories s
+		SET simhash = v.simhash
+		FROM unnest($1::int[], $2::bigint[]) AS v(id, simhash)
+		WHERE s.id = v.id
+	`
+	if _, err := tx.Exec(updateQuery, ids, hashes); err != nil {
+		return 0, fmt.Errorf("failed to backfill simhash: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return len(missing), nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/simhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoryDuplicateRepository(t *testing.T) {
+	db := setupTestDB(t)
+	storyRepo := NewStoryRepository(db)
+
+	fingerprintIDs := func(fingerprints []*model.StoryFingerprint) []int {
+		var ids []int
+		for _, fingerprint := range fingerprints {
+			ids = append(ids, fingerprint.ID)
+		}
+		return ids
+	}
+
+	t.Run("CreateStory stores the simhash of the content", func(t *testing.T) {
+		user := createTestUser(t, db)
+		story := createTestStory(t, db, user.ID, "Fingerprinted", 3)
+
+		fingerprints, err := storyRepo.ListStoryFingerprints(user.ID)
+		require.NoError(t, err)
+		require.Len(t, fingerprints, 1)
+		assert.Equal(t, story.ID, fingerprints[0].ID)
+		assert.Equal(t, int64(simhash.Fingerprint(story.Content)), fingerprints[0].Simhash)
+	})
+
+	t.Run("ListStoryFingerprints skips trashed stories and stories without a simhash", func(t *testing.T) {
+		user := createTestUser(t, db)
+		kept := createTestStory(t, db, user.ID, "Kept", 3)
+		trashed := createTestStory(t, db, user.ID, "Trashed", 3)
+		missing := createTestStory(t, db, user.ID, "Missing", 3)
+
+		require.NoError(t, storyRepo.DeleteStory(trashed.ID))
+		_, err := db.Exec(`UPDATE stories SET simhash = NULL WHERE id = $1`, missing.ID)
+		require.NoError(t, err)
+
+		fingerprints, err := storyRepo.ListStoryFingerprints(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []int{kept.ID}, fingerprintIDs(fingerprints))
+	})
+
+	t.Run("BackfillSimhashes fills stories created before the column existed", func(t *testing.T) {
+		user := createTestUser(t, db)
+		story := createTestStory(t, db, user.ID, "Backfilled", 3)
+		_, err := db.Exec(`UPDATE stories SET simhash = NULL WHERE id = $1`, story.ID)
+		require.NoError(t, err)
+
+		filled, err := storyRepo.BackfillSimhashes(1000)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, filled, 1)
+
+		fingerprints, err := storyRepo.ListStoryFingerprints(user.ID)
+		require.NoError(t, err)
+		require.Len(t, fingerprints, 1)
+		assert.Equal(t, int64(simhash.Fingerprint(story.Content)), fingerprints[0].Simhash)
+
+		filled, err = storyRepo.BackfillSimhashes(1000)
+		require.NoError(t, err)
+		assert.Equal(t, 0, filled)
+	})
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+)
+
+// StorySelection はエクスポートするストーリーの選び方。StoryIDs・TagID・ShelfID のいずれか 1 つを指定する
+type StorySelection struct {
+	StoryIDs []int
+	TagID    *int
+	ShelfID  *int
+}
+
+// exportStoryColumns はエクスポートで使うストーリーの列。s は stories の別名
+const exportStoryColumns = `s.id, s.user_id, s.title, s.content, s.word_count, s.level, s.series_id, s.chapter_number, s.parent_story_id,
+	s.is_favorite, s.source, s.source_url, s.created_at, s.updated_at`
+
+// GetStoriesBySelection はユーザーのストーリーを本文付きで返す。ゴミ箱のものは含めない。
+// 並び順は StoryIDs なら指定した順、タグなら作成日時の古い順、本棚なら棚の並び順
+func (r *sqlxStoryRepository) GetStoriesBySelection(userID int, selection StorySelection) ([]*model.Story, error) {
+	var (
+		query string
+		args  []any
+	)
+	switch {
+	case selection.TagID != nil:
+		query = `
+			SELECT ` + exportStoryColumns + `
+			FROM stories s
+			JOIN story_tags st ON st.story_id = s.id
+			WHERE s.user_id = $1 AND st.tag_id = $2 AND s.deleted_at IS NULL
+			ORDER BY s.created_at ASC, s.id ASC
+		`
+		args = []any{userID, *selection.TagID}
+	case selection.ShelfID != nil:
+		query = `
+			SELECT ` + exportStoryColumns + `
+			FROM stories s
+			JOIN shelf_stories ss ON ss.story_id = s.id
+			WHERE s.user_id = $1 AND ss.shelf_id = $2 AND s.deleted_at IS NULL
+			ORDER BY ss.position ASC
+		`
+		args = []any{userID, *selection.ShelfID}
+	default:
+		query = `
+			SELECT ` + exportStoryColumns + `
+			FROM stories s
+			WHERE s.user_id = $1 AND s.id = ANY($2::int[]) AND s.deleted_at IS NULL
+			ORDER BY array_position($2::int[], s.id)
+		`
+		args = []any{userID, pq.Array(selection.StoryIDs)}
+	}
+
+	var stories []*model.Story
+	if err := r.DB.Select(&stories, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get stories for export: %w", err)
+	}
+	return stories, nil
+}
+
+// GetArchiveStories はアーカイブに書き出すユーザーのストーリーを、afterID より大きい ID から limit 件ずつ ID 順に返す。
+// 各ストーリーにはタグ名 (名前順) と読了記録 (古い順) を含める。ゴミ箱のものは含めない
+func (r *sqlxStoryRepository) GetArchiveStories(userID, afterID, limit int) ([]*model.ArchiveStory, error) {
+	query := `
+		SELECT ` + exportStoryColumns + `
+		FROM stories s
+		WHERE s.user_id = $1 AND s.id > $2 AND s.deleted_at IS NULL
+		ORDER BY s.id ASC
+		LIMIT $3
+	`
+	var rows []model.Story
+	if err := r.DB.Select(&rows, query, userID, afterID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get archive stories: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	stories := make([]*model.ArchiveStory, len(rows))
+	byID := make(map[int]*model.ArchiveStory, len(rows))
+	ids := make([]int, len(rows))
+	for i := range rows {
+		stories[i] = &model.ArchiveStory{Story: rows[i], Tags: []string{}, ReadingRecords: []*model.ReadingRecord{}}
+		byID[rows[i].ID] = stories[i]
+		ids[i] = rows[i].ID
+	}
+
+	var tags []struct {
+		StoryID int    `db:"story_id"`
+		Name    string `db:"name"`
+	}
+	tagQuery := `
+		SELECT st.story_id, t.name
+		FROM story_tags st
+		JOIN tags t ON t.id = st.tag_id
+		WHERE st.story_id = ANY($1::int[])
+		ORDER BY t.name ASC
+	`
+	if err := r.DB.Select(&tags, tagQuery, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("failed to get archive story tags: %w", err)
+	}
+	for _, tag := range tags {
+		byID[tag.StoryID].Tags = append(byID[tag.StoryID].Tags, tag.Name)
+	}
+
+	var records []*model.ReadingRecord
+	recordQuery := `
+		SELECT id, user_id, story_id, word_count, read_at
+		FROM reading_records
+		WHERE user_id = $1 AND story_id = ANY($2::int[])
+		ORDER BY read_at ASC, id ASC
+	`
+	if err := r.DB.Select(&records, recordQuery, userID, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("failed to get archive reading records: %w", err)
+	}
+	for _, record := range records {
+		byID[record.StoryID].ReadingRecords = append(byID[record.StoryID].ReadingRecords, record)
+	}
+
+	return stories, nil
+}
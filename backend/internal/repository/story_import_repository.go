@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+)
+
+// importedStory は取り込み先のライブラリにある、本文が同じストーリー
+type importedStory struct {
+	ID          int     `db:"id"`
+	Title       string  `db:"title"`
+	Level       *string `db:"level"`
+	ContentHash string  `db:"content_hash"`
+}
+
+// ImportArchiveStories はアーカイブのタグとストーリーを 1 つのトランザクションで取り込み、入力順にストーリーごとの結果を返す。
+// 本文が同じストーリー (ゴミ箱のものを除く) が既にある場合は作成せず、足りないタグと読了記録だけを追加する。
+// タイトル・レベルが異なる場合は conflict として既存の値を残す。読了記録は同じ日時のものを追加しないため、再実行しても増えない
+func (r *sqlxStoryRepository) ImportArchiveStories(userID int, tags []string, stories []*model.ArchiveStory) (*model.ArchiveImport, error) {
+	tx, err := r.DB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// 同じユーザーの取り込みが並行して同じ本文のストーリーを作らないよう、ユーザーの行をロックする
+	if err := tx.Get(new(int), `SELECT id FROM users WHERE id = $1 FOR UPDATE`, userID); err != nil {
+		return nil, fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	result := &model.ArchiveImport{CreatedTags: []string{}, Stories: make([]*model.ArchiveImportResult, 0, len(stories))}
+	createTagsQuery := `
+		INSERT INTO tags(user_id, name)
+		SELECT $1, name FROM unnest($2::text[]) AS t(name)
+		ON CONFLICT (user_id, name) DO NOTHING
+		RETURNING name
+	`
+	if err := tx.Select(&result.CreatedTags, createTagsQuery, userID, pq.Array(tags)); err != nil {
+		return nil, fmt.Errorf("failed to create tags: %w", err)
+	}
+	var tagRows []struct {
+		ID   int    `db:"id"`
+		Name string `db:"name"`
+	}
+	if err := tx.Select(&tagRows, `SELECT id, name FROM tags WHERE user_id = $1 AND name = ANY($2::text[])`, userID, pq.Array(tags)); err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	tagIDs := make(map[string]int, len(tagRows))
+	for _, tag := range tagRows {
+		tagIDs[tag.Name] = tag.ID
+	}
+
+	hashes := make([]string, len(stories))
+	for i, story := range stories {
+		hashes[i] = contentHash(story.Content)
+	}
+	var existing []*importedStory
+	existingQuery := `
+		SELECT id, title, level, content_hash
+		FROM stories
+		WHERE user_id = $1 AND deleted_at IS NULL AND content_hash = ANY($2::text[])
+		ORDER BY id ASC
+	`
+	if err := tx.Select(&existing, existingQuery, userID, pq.Array(hashes)); err != nil {
+		return nil, fmt.Errorf("failed to get stories by content hash: %w", err)
+	}
+	byHash := make(map[string]*importedStory, len(existing))
+	for _, story := range existing {
+		// 同じ本文が複数ある場合は最も古いものに揃える
+		if _, ok := byHash[story.ContentHash]; !ok {
+			byHash[story.ContentHash] = story
+		}
+	}
+
+	for i, story := range stories {
+		item := &model.ArchiveImportResult{Title: story.Title, Status: model.ArchiveImportCreated}
+		if found, ok := byHash[hashes[i]]; ok {
+			item.StoryID = found.ID
+			item.Status = model.ArchiveImportDuplicate
+			if found.Title != story.Title {
+				item.Conflicts = append(item.Conflicts, "title")
+			}
+			if !equalLevel(found.Level, story.Level) {
+				item.Conflicts = append(item.Conflicts, "level")
+			}
+			if len(item.Conflicts) > 0 {
+				item.Status = model.ArchiveImportConflict
+			}
+		} else {
+			if err := insertArchiveStory(tx, userID, hashes[i], story); err != nil {
+				return nil, err
+			}
+			item.StoryID = story.ID
+			byHash[hashes[i]] = &importedStory{ID: story.ID, Title: story.Title, Level: story.Level, ContentHash: hashes[i]}
+		}
+
+		if item.AddedTags, err = addArchiveStoryTags(tx, item.StoryID, story.Tags, tagIDs); err != nil {
+			return nil, err
+		}
+		if item.AddedReadingRecords, err = addArchiveReadingRecords(tx, userID, item.StoryID, story.ReadingRecords); err != nil {
+			return nil, err
+		}
+
+		switch item.Status {
+		case model.ArchiveImportCreated:
+			result.Created++
+		case model.ArchiveImportDuplicate:
+			result.Duplicates++
+		case model.ArchiveImportConflict:
+			result.Conflicts++
+		}
+		result.Stories = append(result.Stories, item)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return result, nil
+}
+
+// insertArchiveStory は作成日時とお気に入りを含めてストーリーを保存し、採番された ID を story に設定する
+func insertArchiveStory(tx *sqlx.Tx, userID int, hash string, story *model.ArchiveStory) error {
+	query := `
+		INSERT INTO stories(user_id, title, content, content_hash, simhash, word_count, level, source, source_url, is_favorite, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, created_at, updated_at
+	`
+	err := tx.QueryRowx(query, userID, story.Title, story.Content, hash, contentSimhash(story.Content), story.WordCount, story.Level, story.Source, story.SourceURL, story.IsFavorite, story.CreatedAt).
+		Scan(&story.ID, &story.CreatedAt, &story.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create archive story: %w", err)
+	}
+	story.UserID = userID
+	return nil
+}
+
+// addArchiveStoryTags はストーリーにまだ付いていないタグを付け、付けた数を返す
+func addArchiveStoryTags(tx *sqlx.Tx, storyID int, names []string, tagIDs map[string]int) (int, error) {
+	ids := make(pq.Int64Array, 0, len(names))
+	for _, name := range names {
+		if id, ok := tagIDs[name]; ok {
+			ids = append(ids, int64(id))
+		}
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	query := `
+		INSERT INTO story_tags(story_id, tag_id)
+		SELECT $1, tag_id FROM unnest($2::int[]) AS t(tag_id)
+		ON CONFLICT (story_id, tag_id) DO NOTHING
+	`
+	res, err := tx.Exec(query, storyID, ids)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add archive story tags: %w", err)
+	}
+	added, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to add archive story tags: %w", err)
+	}
+	return int(added), nil
+}
+
+// addArchiveReadingRecords はストーリーに同じ日時の記録がない読了記録だけを追加し、追加した数を返す
+func addArchiveReadingRecords(tx *sqlx.Tx, userID, storyID int, records []*model.ReadingRecord) (int, error) {
+	if len(records) == 0 {
+		return 0, nil
+	}
+	readAts := make([]string, len(records))
+	wordCounts := make(pq.Int64Array, len(records))
+	for i, record := range records {
+		readAts[i] = record.ReadAt.UTC().Format(time.RFC3339Nano)
+		wordCounts[i] = int64(record.WordCount)
+	}
+	query := `
+		INSERT INTO reading_records(user_id, story_id, read_at, word_count)
+		SELECT DISTINCT ON (r.read_at) $1::int, $2::int, r.read_at, r.word_count
+		FROM unnest($3::timestamptz[], $4::int[]) AS r(read_at, word_count)
+		WHERE NOT EXISTS (
+			SELECT 1 FROM reading_records rr
+			WHERE rr.user_id = $1 AND rr.story_id = $2 AND rr.read_at = r.read_at
+		)
+	`
+	res, err := tx.Exec(query, userID, storyID, pq.Array(readAts), wordCounts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add archive reading records: %w", err)
+	}
+	added, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to add archive reading records: %w", err)
+	}
+	return int(added), nil
+}
+
+func equalLevel(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
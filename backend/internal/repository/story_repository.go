@@ -1,19 +1,106 @@
 package repository
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/simhash"
 )
 
+// ErrChapterAlreadyExists は連載に同じ番号の章が既にある (続きの章の生成が同時に行われた) 場合のエラー
+var ErrChapterAlreadyExists = errors.New("chapter already exists")
+
 type IStoryRepository interface {
 	CreateStory(story *model.Story) error
-	GetUserStories(userID, limit, offset int) ([]*model.Story, error)
-	CountUserStories(userID int) (int, error)
+	CreateStories(stories []*model.Story) error
+	GetUserStories(userID int, filter StoryFilter, sort StorySort, limit, offset int) ([]*model.StoryListItem, error)
+	GetUserStoriesByKeyset(userID int, filter StoryFilter, sort StorySort, keyset *Keyset, limit int) ([]*model.StoryListItem, error)
+	CountUserStories(userID int, filter StoryFilter) (int, error)
 	GetUserStory(storyID int, userID int) (*model.Story, error)
 	DeleteStory(storyID int) error
+	GetTrashedStories(userID int) ([]*model.Story, error)
+	RestoreStory(storyID, userID int) (*model.Story, error)
+	PurgeDeletedStories(before time.Time) (int64, error)
 	UpdateStoryTitle(storyID int, userID int, newTitle string) (*model.Story, error)
+	UpdateStoryContent(storyID, userID int, title, content string, wordCount int) (*model.Story, error)
+	GetStoryRevisions(storyID int) ([]*model.StoryRevision, error)
+	GetStoryRevision(storyID, revisionNumber int) (*model.StoryRevision, error)
+	SetStorySeries(storyID, seriesID, chapterNumber int) error
+	SetFavorite(storyID, userID int, favorite bool) error
+	GetDerivedStories(storyID, userID int) ([]*model.StoryVariant, error)
+	BulkUpdateStories(userID int, op BulkOperation) ([]*model.BulkStoryResult, error)
+	GetStoriesBySelection(userID int, selection StorySelection) ([]*model.Story, error)
+	GetArchiveStories(userID, afterID, limit int) ([]*model.ArchiveStory, error)
+	ImportArchiveStories(userID int, tags []string, stories []*model.ArchiveStory) (*model.ArchiveImport, error)
+	ListStoryFingerprints(userID int) ([]*model.StoryFingerprint, error)
+	BackfillSimhashes(limit int) (int, error)
+}
+
+// StoryFilter はストーリー一覧の絞り込み条件。ゼロ値は条件なし
+type StoryFilter struct {
+	// Query はタイトル・本文に対する全文検索のクエリ (websearch_to_tsquery の構文)
+	Query        string
+	MinWordCount *int
+	MaxWordCount *int
+	// CreatedFrom 以上 CreatedTo 未満の作成日時で絞り込む
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Level       string
+	ReadStatus  ReadStatus
+	TagID       *int
+	// FavoritesOnly が true の場合はお気に入りのみ
+	FavoritesOnly bool
+}
+
+// ReadStatus は読了記録の有無による絞り込み
+type ReadStatus string
+
+const (
+	ReadStatusAny    ReadStatus = ""
+	ReadStatusRead   ReadStatus = "read"
+	ReadStatusUnread ReadStatus = "unread"
+)
+
+// StorySort はストーリー一覧の並び順
+type StorySort string
+
+const (
+	// StorySortDefault は全文検索時は関連度順、それ以外は新しい順
+	StorySortDefault      StorySort = ""
+	StorySortNewest       StorySort = "newest"
+	StorySortOldest       StorySort = "oldest"
+	StorySortLongest      StorySort = "longest"
+	StorySortShortest     StorySort = "shortest"
+	StorySortTitle        StorySort = "title"
+	StorySortRecentlyRead StorySort = "recently_read"
+)
+
+// storySortOrders は並び順ごとの ORDER BY 句。いずれも (user_id, ...) の複合インデックスに対応し、id で順序を確定させる
+var storySortOrders = map[StorySort]string{
+	StorySortNewest:   "created_at DESC, id DESC",
+	StorySortOldest:   "created_at ASC, id ASC",
+	StorySortLongest:  "word_count DESC, id DESC",
+	StorySortShortest: "word_count ASC, id ASC",
+	StorySortTitle:    "title ASC, id ASC",
+	// 未読のストーリーは末尾に新しい順で並べる。last_read_at は storyListFrom の集計結果
+	StorySortRecentlyRead: "last_read_at DESC NULLS LAST, created_at DESC, id DESC",
+}
+
+// IsValid は定義済みの並び順かどうかを返す
+func (s StorySort) IsValid() bool {
+	if s == StorySortDefault {
+		return true
+	}
+	_, ok := storySortOrders[s]
+	return ok
 }
 
 type sqlxStoryRepository struct {
@@ -25,49 +112,219 @@ func NewStoryRepository(db *sqlx.DB) IStoryRepository {
 }
 
 func (r *sqlxStoryRepository) CreateStory(story *model.Story) error {
+	return insertStory(r.DB, story)
+}
+
+// CreateStories は複数のストーリーを 1 つのトランザクションで保存する。途中で失敗した場合は 1 件も保存しない
+func (r *sqlxStoryRepository) CreateStories(stories []*model.Story) error {
+	tx, err := r.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, story := range stories {
+		if err := insertStory(tx, story); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// insertStory はストーリーを保存し、採番された ID などを story に設定する。トランザクション内からも使う
+func insertStory(q sqlx.Queryer, story *model.Story) error {
 	query := `
-		INSERT INTO stories(user_id, title, content, word_count)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, created_at, updated_at
+		INSERT INTO stories(user_id, title, content, word_count, level, series_id, chapter_number, parent_story_id, source, source_url, content_hash, simhash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE(NULLIF($9, ''), 'generated'), $10, $11, $12)
+		RETURNING id, source, created_at, updated_at
 	`
-	err := r.DB.QueryRowx(query, story.UserID, story.Title, story.Content, story.WordCount).Scan(&story.ID, &story.CreatedAt, &story.UpdatedAt)
+	err := q.QueryRowx(query, story.UserID, story.Title, story.Content, story.WordCount, story.Level, story.SeriesID, story.ChapterNumber, story.ParentStoryID, story.Source, story.SourceURL, contentHash(story.Content), contentSimhash(story.Content)).Scan(&story.ID, &story.Source, &story.CreatedAt, &story.UpdatedAt)
 	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" && pgErr.Constraint == "idx_stories_series_id_chapter_number" {
+			return ErrChapterAlreadyExists
+		}
 		return fmt.Errorf("failed to create story: %w", err)
 	}
 	return nil
 }
 
-func (r *sqlxStoryRepository) GetUserStories(userID, limit, offset int) ([]*model.Story, error) {
-	query := `
-		SELECT id, user_id, title, created_at, updated_at
-		FROM stories
-		WHERE user_id = $1
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
-	`
-	var stories []*model.Story
-	err := r.DB.Select(&stories, query, userID, limit, offset)
+// contentHash は本文の SHA-256 (16 進) を返す。stories.content_hash に保存し、同じ本文のストーリーの判定に使う
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// contentSimhash は本文の SimHash を stories.simhash (BIGINT) に保存する値として返す
+func contentSimhash(content string) int64 {
+	return int64(simhash.Fingerprint(content))
+}
+
+// GetUserStories はユーザーのストーリー一覧を返す。
+// 並び順の指定がない場合、全文検索時は関連度順、それ以外は作成日時の新しい順 (idx_stories_user_id_created_at_desc を利用)
+func (r *sqlxStoryRepository) GetUserStories(userID int, filter StoryFilter, sort StorySort, limit, offset int) ([]*model.StoryListItem, error) {
+	where, args := buildStoryFilter(userID, filter)
+
+	columns := storyListColumns(filter)
+	orderBy, ok := storySortOrders[sort]
+	if !ok {
+		orderBy = storySortOrders[StorySortNewest]
+	}
+	if filter.Query != "" && sort == StorySortDefault {
+		// buildStoryFilter が検索クエリを $2 に割り当てている
+		orderBy = "ts_rank(search_vector, websearch_to_tsquery('english', $2)) DESC, " + orderBy
+	}
+
+	args = append(args, limit, offset)
+	query := fmt.Sprintf(`
+		SELECT %s
+		%s
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, columns, storyListFrom, where, orderBy, len(args)-1, len(args))
+
+	var stories []*model.StoryListItem
+	err := r.DB.Select(&stories, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user stories: %w", err)
 	}
+	for _, story := range stories {
+		story.Snippet = escapeSnippet(story.Snippet)
+	}
 	return stories, nil
 }
 
-func (r *sqlxStoryRepository) CountUserStories(userID int) (int, error) {
+// GetUserStoriesByKeyset は (created_at, id) を基準にしたキーセットページネーションで一覧を返す。
+// idx_stories_user_id_created_at_desc を利用するため、並び順は新しい順・古い順のみ対応する。
+// 結果は keyset の向きにかかわらず表示順で返す
+func (r *sqlxStoryRepository) GetUserStoriesByKeyset(userID int, filter StoryFilter, sort StorySort, keyset *Keyset, limit int) ([]*model.StoryListItem, error) {
+	where, args := buildStoryFilter(userID, filter)
+
+	ascending := sort == StorySortOldest
+	if keyset != nil && keyset.Backward {
+		ascending = !ascending
+	}
+	direction, operator := "DESC", "<"
+	if ascending {
+		direction, operator = "ASC", ">"
+	}
+
+	if keyset != nil {
+		args = append(args, keyset.Time, keyset.ID)
+		where += fmt.Sprintf(" AND (created_at, id) %s ($%d, $%d)", operator, len(args)-1, len(args))
+	}
+
+	args = append(args, limit)
+	query := fmt.Sprintf(`
+		SELECT %s
+		%s
+		WHERE %s
+		ORDER BY created_at %s, id %s
+		LIMIT $%d
+	`, storyListColumns(filter), storyListFrom, where, direction, direction, len(args))
+
+	var stories []*model.StoryListItem
+	err := r.DB.Select(&stories, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user stories by keyset: %w", err)
+	}
+	for _, story := range stories {
+		story.Snippet = escapeSnippet(story.Snippet)
+	}
+
+	if keyset != nil && keyset.Backward {
+		slices.Reverse(stories)
+	}
+	return stories, nil
+}
+
+// storyListFrom は一覧の FROM 句。読了回数と最終読了日時をストーリーごとに集計して結合する
+// (idx_reading_records_user_story_read_at_desc を利用)
+const storyListFrom = `FROM stories
+		LEFT JOIN LATERAL (
+			SELECT COUNT(*) AS read_count, MAX(rr.read_at) AS last_read_at
+			FROM reading_records rr
+			WHERE rr.user_id = stories.user_id AND rr.story_id = stories.id
+		) reads ON TRUE`
+
+// storyListColumns は一覧で取得する列を返す。全文検索時は本文のスニペットを含める
+func storyListColumns(filter StoryFilter) string {
+	columns := "id, user_id, title, word_count, level, series_id, chapter_number, parent_story_id, is_favorite, source, created_at, updated_at, reads.read_count, reads.last_read_at"
+	if filter.Query != "" {
+		// buildStoryFilter が検索クエリを $2 に割り当てている
+		columns += fmt.Sprintf(", ts_headline('english', content, websearch_to_tsquery('english', $2), '%s') AS snippet", snippetOptions)
+	}
+	return columns
+}
+
+func (r *sqlxStoryRepository) CountUserStories(userID int, filter StoryFilter) (int, error) {
+	where, args := buildStoryFilter(userID, filter)
+
 	var total int
-	query := `SELECT COUNT(*) FROM stories WHERE user_id = $1`
-	err := r.DB.Get(&total, query, userID)
+	query := `SELECT COUNT(*) FROM stories WHERE ` + where
+	err := r.DB.Get(&total, query, args...)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count user stories: %w", err)
 	}
 	return total, nil
 }
 
+// buildStoryFilter は絞り込み条件から WHERE 句とプレースホルダの値を組み立てる。
+// $1 は常に user_id、全文検索のクエリがある場合は $2。ゴミ箱のストーリーは含めない
+func buildStoryFilter(userID int, filter StoryFilter) (string, []any) {
+	conditions := []string{"user_id = $1", "deleted_at IS NULL"}
+	args := []any{userID}
+
+	addCondition := func(format string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+
+	if filter.Query != "" {
+		addCondition("search_vector @@ websearch_to_tsquery('english', $%d)", filter.Query)
+	}
+	if filter.MinWordCount != nil {
+		addCondition("word_count >= $%d", *filter.MinWordCount)
+	}
+	if filter.MaxWordCount != nil {
+		addCondition("word_count <= $%d", *filter.MaxWordCount)
+	}
+	if filter.CreatedFrom != nil {
+		addCondition("created_at >= $%d", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		addCondition("created_at < $%d", *filter.CreatedTo)
+	}
+	if filter.Level != "" {
+		addCondition("level = $%d", filter.Level)
+	}
+	if filter.FavoritesOnly {
+		conditions = append(conditions, "is_favorite")
+	}
+	if filter.TagID != nil {
+		addCondition("EXISTS (SELECT 1 FROM story_tags st WHERE st.story_id = stories.id AND st.tag_id = $%d)", *filter.TagID)
+	}
+
+	// idx_reading_records_user_story_read_at_desc を使った存在確認
+	switch filter.ReadStatus {
+	case ReadStatusRead:
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM reading_records rr WHERE rr.user_id = stories.user_id AND rr.story_id = stories.id)")
+	case ReadStatusUnread:
+		conditions = append(conditions, "NOT EXISTS (SELECT 1 FROM reading_records rr WHERE rr.user_id = stories.user_id AND rr.story_id = stories.id)")
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
 func (r *sqlxStoryRepository) GetUserStory(storyID int, userID int) (*model.Story, error) {
 	query := `
-		SELECT id, user_id, title, content, created_at, updated_at, word_count
+		SELECT id, user_id, title, content, created_at, updated_at, word_count, level, series_id, chapter_number, parent_story_id, is_favorite, source, source_url
 		FROM stories
-		WHERE id = $1 AND user_id = $2
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
 	`
 	var story model.Story
 	err := r.DB.Get(&story, query, storyID, userID)
@@ -77,10 +334,12 @@ func (r *sqlxStoryRepository) GetUserStory(storyID int, userID int) (*model.Stor
 	return &story, nil
 }
 
+// DeleteStory はストーリーをゴミ箱に移す。読了記録との紐付けは物理削除されるまで保たれる
 func (r *sqlxStoryRepository) DeleteStory(storyID int) error {
 	query := `
-		DELETE FROM stories
-		WHERE id = $1
+		UPDATE stories
+		SET deleted_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 	_, err := r.DB.Exec(query, storyID)
 	if err != nil {
@@ -89,13 +348,58 @@ func (r *sqlxStoryRepository) DeleteStory(storyID int) error {
 	return nil
 }
 
+// GetTrashedStories はゴミ箱のストーリーを削除日時の新しい順に返す
+func (r *sqlxStoryRepository) GetTrashedStories(userID int) ([]*model.Story, error) {
+	query := `
+		SELECT id, user_id, title, word_count, level, series_id, chapter_number, parent_story_id, is_favorite, source, created_at, updated_at, deleted_at
+		FROM stories
+		WHERE user_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC
+	`
+	var stories []*model.Story
+	err := r.DB.Select(&stories, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trashed stories: %w", err)
+	}
+	return stories, nil
+}
+
+// RestoreStory はゴミ箱のストーリーを元に戻す。ゴミ箱にない場合は sql.ErrNoRows を返す
+func (r *sqlxStoryRepository) RestoreStory(storyID, userID int) (*model.Story, error) {
+	var story model.Story
+	query := `
+		UPDATE stories
+		SET deleted_at = NULL
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+		RETURNING id, user_id, title, content, word_count, level, series_id, chapter_number, parent_story_id, is_favorite, source, source_url, created_at, updated_at
+	`
+	err := r.DB.Get(&story, query, storyID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore story: %w", err)
+	}
+	return &story, nil
+}
+
+// PurgeDeletedStories は before より前にゴミ箱に移したストーリーを物理削除し、削除した件数を返す
+func (r *sqlxStoryRepository) PurgeDeletedStories(before time.Time) (int64, error) {
+	result, err := r.DB.Exec(`DELETE FROM stories WHERE deleted_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted stories: %w", err)
+	}
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count purged stories: %w", err)
+	}
+	return purged, nil
+}
+
 func (r *sqlxStoryRepository) UpdateStoryTitle(storyID int, userID int, newTitle string) (*model.Story, error) {
 	var updatedStory model.Story
 	query := `
 		UPDATE stories
 		SET title = $1, updated_at = NOW()
-		WHERE id = $2 AND user_id = $3
-		RETURNING id, user_id, title, content, created_at, updated_at
+		WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL
+		RETURNING id, user_id, title, content, word_count, level, series_id, chapter_number, parent_story_id, is_favorite, source, source_url, created_at, updated_at
 	`
 	err := r.DB.Get(&updatedStory, query, newTitle, storyID, userID)
 	if err != nil {
@@ -103,3 +407,124 @@ func (r *sqlxStoryRepository) UpdateStoryTitle(storyID int, userID int, newTitle
 	}
 	return &updatedStory, nil
 }
+
+// UpdateStoryContent は編集前の内容を story_revisions に保存してから本文を更新する。
+// 翻訳は段落の対応が崩れるため削除し、読了記録は読んだ時点の語数のまま残す。ユーザーのストーリーでない場合は sql.ErrNoRows を返す
+func (r *sqlxStoryRepository) UpdateStoryContent(storyID, userID int, title, content string, wordCount int) (*model.Story, error) {
+	tx, err := r.DB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// 版番号の採番を直列化するためストーリーの行をロックする
+	lockQuery := `SELECT id FROM stories WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL FOR UPDATE`
+	if err := tx.Get(new(int), lockQuery, storyID, userID); err != nil {
+		return nil, fmt.Errorf("failed to get story: %w", err)
+	}
+
+	revisionQuery := `
+		INSERT INTO story_revisions(story_id, revision_number, title, content, word_count)
+		SELECT id, COALESCE((SELECT MAX(revision_number) FROM story_revisions WHERE story_id = $1), 0) + 1, title, content, word_count
+		FROM stories
+		WHERE id = $1
+	`
+	if _, err := tx.Exec(revisionQuery, storyID); err != nil {
+		return nil, fmt.Errorf("failed to create story revision: %w", err)
+	}
+
+	var story model.Story
+	updateQuery := `
+		UPDATE stories
+		SET title = $1, content = $2, word_count = $3, content_hash = $5, simhash = $6, updated_at = NOW()
+		WHERE id = $4
+		RETURNING id, user_id, title, content, word_count, level, series_id, chapter_number, parent_story_id, is_favorite, source, source_url, created_at, updated_at
+	`
+	if err := tx.Get(&story, updateQuery, title, content, wordCount, storyID, contentHash(content), contentSimhash(content)); err != nil {
+		return nil, fmt.Errorf("failed to update story content: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM story_translations WHERE story_id = $1`, storyID); err != nil {
+		return nil, fmt.Errorf("failed to delete story translations: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return &story, nil
+}
+
+// GetStoryRevisions はストーリーの過去の版を新しい順に返す。本文は含めない
+func (r *sqlxStoryRepository) GetStoryRevisions(storyID int) ([]*model.StoryRevision, error) {
+	query := `
+		SELECT id, story_id, revision_number, title, word_count, created_at
+		FROM story_revisions
+		WHERE story_id = $1
+		ORDER BY revision_number DESC
+	`
+	var revisions []*model.StoryRevision
+	err := r.DB.Select(&revisions, query, storyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get story revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+func (r *sqlxStoryRepository) GetStoryRevision(storyID, revisionNumber int) (*model.StoryRevision, error) {
+	query := `
+		SELECT id, story_id, revision_number, title, content, word_count, created_at
+		FROM story_revisions
+		WHERE story_id = $1 AND revision_number = $2
+	`
+	var revision model.StoryRevision
+	err := r.DB.Get(&revision, query, storyID, revisionNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get story revision: %w", err)
+	}
+	return &revision, nil
+}
+
+func (r *sqlxStoryRepository) SetStorySeries(storyID, seriesID, chapterNumber int) error {
+	query := `
+		UPDATE stories
+		SET series_id = $1, chapter_number = $2
+		WHERE id = $3
+	`
+	_, err := r.DB.Exec(query, seriesID, chapterNumber, storyID)
+	if err != nil {
+		return fmt.Errorf("failed to set story series: %w", err)
+	}
+	return nil
+}
+
+// SetFavorite はお気に入りの状態を設定する。ユーザーのストーリーでない場合は sql.ErrNoRows を返す
+func (r *sqlxStoryRepository) SetFavorite(storyID, userID int, favorite bool) error {
+	query := `
+		UPDATE stories
+		SET is_favorite = $1
+		WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL
+		RETURNING id
+	`
+	var id int
+	err := r.DB.Get(&id, query, favorite, storyID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to set favorite: %w", err)
+	}
+	return nil
+}
+
+// GetDerivedStories は指定したストーリーを元にレベルを書き換えたストーリーを返す
+func (r *sqlxStoryRepository) GetDerivedStories(storyID, userID int) ([]*model.StoryVariant, error) {
+	query := `
+		SELECT id, title, level, word_count
+		FROM stories
+		WHERE parent_story_id = $1 AND user_id = $2 AND deleted_at IS NULL
+		ORDER BY created_at ASC
+	`
+	var variants []*model.StoryVariant
+	err := r.DB.Select(&variants, query, storyID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get derived stories: %w", err)
+	}
+	return variants, nil
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"database/sql"
 	"fmt"
 	"strings"
 	"testing"
@@ -11,6 +12,12 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// search_vector 列は model.Story にないため、SELECT * ではなく列を明示する
+const storySelectForTest = `
+	SELECT id, user_id, title, content, word_count, level, series_id, chapter_number, parent_story_id, is_favorite, source, source_url, created_at, updated_at
+	FROM stories WHERE id = $1
+`
+
 // --- テストケース ---
 
 func TestStoryRepository(t *testing.T) {
@@ -40,7 +47,7 @@ func TestStoryRepository(t *testing.T) {
 		assert.NotZero(t, storyToCreate.ID)
 
 		var fetchedStory model.Story
-		err = db.Get(&fetchedStory, "SELECT * FROM stories WHERE id = $1", storyToCreate.ID)
+		err = db.Get(&fetchedStory, storySelectForTest, storyToCreate.ID)
 		require.NoError(t, err)
 		assert.Equal(t, user.ID, fetchedStory.UserID)
 		assert.Equal(t, title, fetchedStory.Title)
@@ -48,6 +55,55 @@ func TestStoryRepository(t *testing.T) {
 		assert.NotZero(t, fetchedStory.CreatedAt)
 		assert.NotZero(t, fetchedStory.UpdatedAt)
 		assert.Equal(t, storyToCreate.WordCount, fetchedStory.WordCount)
+		assert.Equal(t, model.StorySourceGenerated, fetchedStory.Source)
+		assert.Equal(t, model.StorySourceGenerated, storyToCreate.Source)
+	})
+
+	t.Run("CreateStory keeps the imported source", func(t *testing.T) {
+		user := createTestUser(t, db)
+		story := &model.Story{UserID: user.ID, Title: "Imported", Content: "From a book.", WordCount: 3, Source: model.StorySourceImported}
+
+		require.NoError(t, storyRepo.CreateStory(story))
+
+		fetched, err := storyRepo.GetUserStory(story.ID, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.StorySourceImported, fetched.Source)
+		assert.Nil(t, fetched.SourceURL)
+
+		sourceURL := "https://example.com/article"
+		fromURL := &model.Story{UserID: user.ID, Title: "Article", Content: "From the web.", WordCount: 3, Source: model.StorySourceImported, SourceURL: &sourceURL}
+		require.NoError(t, storyRepo.CreateStory(fromURL))
+
+		fetched, err = storyRepo.GetUserStory(fromURL.ID, user.ID)
+		require.NoError(t, err)
+		require.NotNil(t, fetched.SourceURL)
+		assert.Equal(t, sourceURL, *fetched.SourceURL)
+	})
+
+	t.Run("CreateStories saves every story or none of them", func(t *testing.T) {
+		user := createTestUser(t, db)
+		countStories := func() int {
+			var count int
+			require.NoError(t, db.Get(&count, `SELECT COUNT(*) FROM stories WHERE user_id = $1`, user.ID))
+			return count
+		}
+
+		stories := []*model.Story{
+			{UserID: user.ID, Title: "Chapter 1", Content: "One.", WordCount: 1, Source: model.StorySourceImported},
+			{UserID: user.ID, Title: "Chapter 2", Content: "Two.", WordCount: 1, Source: model.StorySourceImported},
+		}
+		require.NoError(t, storyRepo.CreateStories(stories))
+		assert.NotZero(t, stories[0].ID)
+		assert.NotZero(t, stories[1].ID)
+		assert.Equal(t, 2, countStories())
+
+		// 存在しないユーザーの行で外部キー制約に違反させ、先に挿入した行も残らないことを確かめる
+		failing := []*model.Story{
+			{UserID: user.ID, Title: "Chapter 3", Content: "Three.", WordCount: 1, Source: model.StorySourceImported},
+			{UserID: -1, Title: "Orphan", Content: "Nobody.", WordCount: 1, Source: model.StorySourceImported},
+		}
+		assert.Error(t, storyRepo.CreateStories(failing))
+		assert.Equal(t, 2, countStories())
 	})
 
 	t.Run("GetUserStories", func(t *testing.T) {
@@ -81,7 +137,7 @@ func TestStoryRepository(t *testing.T) {
 				expectedCount := storyCounts[i]
 
 				// ユーザーのストーリを取得
-				fetchedStories, err := storyRepo.GetUserStories(user.ID, limit, offset)
+				fetchedStories, err := storyRepo.GetUserStories(user.ID, StoryFilter{}, StorySortDefault, limit, offset)
 				require.NoError(t, err, "failed to get User %d stories", i+1)
 
 				// 件数、所有者、順序を検証
@@ -101,6 +157,161 @@ func TestStoryRepository(t *testing.T) {
 		}
 	})
 
+	t.Run("GetUserStories with full-text search", func(t *testing.T) {
+		user := createTestUser(t, db)
+		other := createTestUser(t, db)
+
+		volcano := &model.Story{UserID: user.ID, Title: "Volcanoes", Content: "A volcano erupts when magma rises to the surface.", WordCount: 9}
+		require.NoError(t, storyRepo.CreateStory(volcano))
+		mention := &model.Story{UserID: user.ID, Title: "Travel in Japan", Content: "You can see a volcano from the train. Trains are fast.", WordCount: 11}
+		require.NoError(t, storyRepo.CreateStory(mention))
+		unrelated := &model.Story{UserID: user.ID, Title: "Cooking", Content: "Rice is cooked with water.", WordCount: 5}
+		require.NoError(t, storyRepo.CreateStory(unrelated))
+		othersStory := &model.Story{UserID: other.ID, Title: "Volcano", Content: "Another user's volcano.", WordCount: 3}
+		require.NoError(t, storyRepo.CreateStory(othersStory))
+
+		filter := StoryFilter{Query: "volcano"}
+
+		count, err := storyRepo.CountUserStories(user.ID, filter)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		stories, err := storyRepo.GetUserStories(user.ID, filter, StorySortDefault, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, stories, 2)
+		// タイトルに一致するストーリーが上位
+		assert.Equal(t, volcano.ID, stories[0].ID)
+		assert.Equal(t, mention.ID, stories[1].ID)
+		require.NotNil(t, stories[1].Snippet)
+		assert.Contains(t, *stories[1].Snippet, "<mark>volcano</mark>")
+	})
+
+	t.Run("GetUserStories escapes markup in the snippet", func(t *testing.T) {
+		user := createTestUser(t, db)
+		imported := &model.Story{UserID: user.ID, Title: "Imported", Content: `Lava <img src=x onerror="alert(1)"> & ash.`, WordCount: 6}
+		require.NoError(t, storyRepo.CreateStory(imported))
+
+		stories, err := storyRepo.GetUserStories(user.ID, StoryFilter{Query: "lava"}, StorySortDefault, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, stories, 1)
+		require.NotNil(t, stories[0].Snippet)
+		assert.Contains(t, *stories[0].Snippet, "<mark>Lava</mark>")
+		assert.Contains(t, *stories[0].Snippet, "&amp;")
+		assert.NotContains(t, *stories[0].Snippet, "<img")
+	})
+
+	t.Run("GetUserStories with filters and sort", func(t *testing.T) {
+		user := createTestUser(t, db)
+
+		short := createTestStory(t, db, user.ID, "Banana", 100)
+		long := createTestStory(t, db, user.ID, "Apple", 900)
+		medium := createTestStory(t, db, user.ID, "Cherry", 400)
+		_, err := db.Exec("UPDATE stories SET level = 'B1' WHERE id = $1", medium.ID)
+		require.NoError(t, err)
+
+		readingRepo := NewReadingRecordRepository(db)
+		require.NoError(t, readingRepo.CreateReadingRecord(user.ID, short.ID, short.WordCount))
+		time.Sleep(10 * time.Millisecond)
+		require.NoError(t, readingRepo.CreateReadingRecord(user.ID, long.ID, long.WordCount))
+
+		ids := func(stories []*model.StoryListItem) []int {
+			result := []int{}
+			for _, s := range stories {
+				result = append(result, s.ID)
+			}
+			return result
+		}
+
+		minWords, maxWords := 200, 900
+		stories, err := storyRepo.GetUserStories(user.ID, StoryFilter{MinWordCount: &minWords, MaxWordCount: &maxWords}, StorySortShortest, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []int{medium.ID, long.ID}, ids(stories))
+
+		stories, err = storyRepo.GetUserStories(user.ID, StoryFilter{ReadStatus: ReadStatusUnread}, StorySortDefault, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []int{medium.ID}, ids(stories))
+
+		count, err := storyRepo.CountUserStories(user.ID, StoryFilter{ReadStatus: ReadStatusRead})
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		stories, err = storyRepo.GetUserStories(user.ID, StoryFilter{Level: "B1"}, StorySortDefault, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []int{medium.ID}, ids(stories))
+
+		stories, err = storyRepo.GetUserStories(user.ID, StoryFilter{}, StorySortTitle, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []int{long.ID, short.ID, medium.ID}, ids(stories))
+
+		// 最後に読んだものが先頭、未読は末尾
+		stories, err = storyRepo.GetUserStories(user.ID, StoryFilter{}, StorySortRecentlyRead, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []int{long.ID, short.ID, medium.ID}, ids(stories))
+
+		future := time.Now().Add(time.Hour)
+		count, err = storyRepo.CountUserStories(user.ID, StoryFilter{CreatedFrom: &future})
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("GetUserStories includes word count and read stats", func(t *testing.T) {
+		user := createTestUser(t, db)
+		other := createTestUser(t, db)
+		readTwice := createTestStory(t, db, user.ID, "Read twice", 120)
+		unread := createTestStory(t, db, user.ID, "Unread", 80)
+
+		firstRead := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+		lastRead := firstRead.Add(24 * time.Hour)
+		createTestReadingRecord(t, db, user.ID, readTwice.ID, 120, firstRead)
+		createTestReadingRecord(t, db, user.ID, readTwice.ID, 120, lastRead)
+		// 他のユーザーの記録は集計に含めない
+		createTestReadingRecord(t, db, other.ID, readTwice.ID, 120, lastRead.Add(time.Hour))
+
+		stories, err := storyRepo.GetUserStories(user.ID, StoryFilter{}, StorySortTitle, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, stories, 2)
+
+		assert.Equal(t, readTwice.ID, stories[0].ID)
+		assert.Equal(t, 120, stories[0].WordCount)
+		assert.Equal(t, 2, stories[0].ReadCount)
+		require.NotNil(t, stories[0].LastReadAt)
+		assert.True(t, lastRead.Equal(*stories[0].LastReadAt))
+
+		assert.Equal(t, unread.ID, stories[1].ID)
+		assert.Equal(t, 80, stories[1].WordCount)
+		assert.Zero(t, stories[1].ReadCount)
+		assert.Nil(t, stories[1].LastReadAt)
+
+		keysetStories, err := storyRepo.GetUserStoriesByKeyset(user.ID, StoryFilter{}, StorySortOldest, nil, 10)
+		require.NoError(t, err)
+		require.Len(t, keysetStories, 2)
+		assert.Equal(t, 2, keysetStories[0].ReadCount)
+		assert.Equal(t, 120, keysetStories[0].WordCount)
+	})
+
+	t.Run("SetFavorite and the favorites filter", func(t *testing.T) {
+		user := createTestUser(t, db)
+		other := createTestUser(t, db)
+		favorite := createTestStory(t, db, user.ID, "Favorite", 100)
+		createTestStory(t, db, user.ID, "Ordinary", 100)
+
+		require.NoError(t, storyRepo.SetFavorite(favorite.ID, user.ID, true))
+		// 二度登録してもエラーにならない
+		require.NoError(t, storyRepo.SetFavorite(favorite.ID, user.ID, true))
+		assert.ErrorIs(t, storyRepo.SetFavorite(favorite.ID, other.ID, true), sql.ErrNoRows)
+
+		stories, err := storyRepo.GetUserStories(user.ID, StoryFilter{FavoritesOnly: true}, StorySortDefault, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, stories, 1)
+		assert.Equal(t, favorite.ID, stories[0].ID)
+		assert.True(t, stories[0].IsFavorite)
+
+		require.NoError(t, storyRepo.SetFavorite(favorite.ID, user.ID, false))
+		fetched, err := storyRepo.GetUserStory(favorite.ID, user.ID)
+		require.NoError(t, err)
+		assert.False(t, fetched.IsFavorite)
+	})
+
 	t.Run("GetUserStory", func(t *testing.T) {
 		user := createTestUser(t, db)
 		storyToGet := createTestStory(t, db, user.ID, "A story to get", 10)
@@ -124,6 +335,250 @@ func TestStoryRepository(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	t.Run("DeleteStory moves the story to the trash", func(t *testing.T) {
+		user := createTestUser(t, db)
+		trashed := createTestStory(t, db, user.ID, "Trashed", 10)
+		kept := createTestStory(t, db, user.ID, "Kept", 10)
+		record := createTestReadingRecord(t, db, user.ID, trashed.ID, 10, time.Now())
+
+		require.NoError(t, storyRepo.DeleteStory(trashed.ID))
+
+		stories, err := storyRepo.GetUserStories(user.ID, StoryFilter{}, StorySortDefault, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, stories, 1)
+		assert.Equal(t, kept.ID, stories[0].ID)
+
+		trash, err := storyRepo.GetTrashedStories(user.ID)
+		require.NoError(t, err)
+		require.Len(t, trash, 1)
+		assert.Equal(t, trashed.ID, trash[0].ID)
+		assert.NotNil(t, trash[0].DeletedAt)
+
+		// ゴミ箱にある間は読了記録の紐付けが残る
+		var storyID *int
+		require.NoError(t, db.Get(&storyID, "SELECT story_id FROM reading_records WHERE id = $1", record.ID))
+		require.NotNil(t, storyID)
+		assert.Equal(t, trashed.ID, *storyID)
+
+		restored, err := storyRepo.RestoreStory(trashed.ID, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Trashed", restored.Title)
+		_, err = storyRepo.GetUserStory(trashed.ID, user.ID)
+		require.NoError(t, err)
+
+		// ゴミ箱にないストーリーは復元できない
+		_, err = storyRepo.RestoreStory(kept.ID, user.ID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("PurgeDeletedStories removes only stories trashed before the cutoff", func(t *testing.T) {
+		user := createTestUser(t, db)
+		old := createTestStory(t, db, user.ID, "Old", 10)
+		recent := createTestStory(t, db, user.ID, "Recent", 10)
+		live := createTestStory(t, db, user.ID, "Live", 10)
+
+		now := time.Now()
+		_, err := db.Exec("UPDATE stories SET deleted_at = $1 WHERE id = $2", now.Add(-40*24*time.Hour), old.ID)
+		require.NoError(t, err)
+		_, err = db.Exec("UPDATE stories SET deleted_at = $1 WHERE id = $2", now.Add(-time.Hour), recent.ID)
+		require.NoError(t, err)
+
+		purged, err := storyRepo.PurgeDeletedStories(now.Add(-30 * 24 * time.Hour))
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, purged)
+
+		var remaining []int
+		require.NoError(t, db.Select(&remaining, "SELECT id FROM stories WHERE user_id = $1 ORDER BY id", user.ID))
+		assert.Equal(t, []int{recent.ID, live.ID}, remaining)
+	})
+
+	t.Run("BulkUpdateStories", func(t *testing.T) {
+		user := createTestUser(t, db)
+		other := createTestUser(t, db)
+		first := createTestStory(t, db, user.ID, "First", 100)
+		favorite := createTestStory(t, db, user.ID, "Favorite", 200)
+		foreign := createTestStory(t, db, other.ID, "Foreign", 300)
+		require.NoError(t, storyRepo.SetFavorite(favorite.ID, user.ID, true))
+
+		results, err := storyRepo.BulkUpdateStories(user.ID, BulkOperation{
+			Action:   BulkActionDelete,
+			StoryIDs: []int{first.ID, favorite.ID, foreign.ID, first.ID},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.BulkStoryResult{
+			{StoryID: first.ID, Status: model.BulkStatusOK},
+			{StoryID: favorite.ID, Status: model.BulkStatusSkipped, Reason: "favorite"},
+			{StoryID: foreign.ID, Status: model.BulkStatusNotFound},
+		}, results)
+
+		trashed, err := storyRepo.GetTrashedStories(user.ID)
+		require.NoError(t, err)
+		require.Len(t, trashed, 1)
+		assert.Equal(t, first.ID, trashed[0].ID)
+		_, err = storyRepo.GetUserStory(foreign.ID, other.ID)
+		assert.NoError(t, err, "other users' stories must not be touched")
+
+		results, err = storyRepo.BulkUpdateStories(user.ID, BulkOperation{Action: BulkActionMarkRead, StoryIDs: []int{favorite.ID}})
+		require.NoError(t, err)
+		assert.Equal(t, model.BulkStatusOK, results[0].Status)
+		var readWords int
+		require.NoError(t, db.Get(&readWords, "SELECT word_count FROM reading_records WHERE user_id = $1 AND story_id = $2", user.ID, favorite.ID))
+		assert.Equal(t, 200, readWords)
+	})
+
+	t.Run("BulkUpdateStories rejects another user's tag", func(t *testing.T) {
+		user := createTestUser(t, db)
+		other := createTestUser(t, db)
+		story := createTestStory(t, db, user.ID, "Story", 100)
+		tag := &model.Tag{UserID: other.ID, Name: "theirs"}
+		require.NoError(t, NewTagRepository(db).CreateTag(tag))
+
+		_, err := storyRepo.BulkUpdateStories(user.ID, BulkOperation{Action: BulkActionTag, StoryIDs: []int{story.ID}, TagID: tag.ID})
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("GetStoriesBySelection", func(t *testing.T) {
+		user := createTestUser(t, db)
+		other := createTestUser(t, db)
+		first := createTestStory(t, db, user.ID, "First", 100)
+		second := createTestStory(t, db, user.ID, "Second", 200)
+		trashed := createTestStory(t, db, user.ID, "Trashed", 300)
+		foreign := createTestStory(t, db, other.ID, "Foreign", 400)
+		require.NoError(t, storyRepo.DeleteStory(trashed.ID))
+
+		ids := func(stories []*model.Story) []int {
+			result := []int{}
+			for _, s := range stories {
+				result = append(result, s.ID)
+			}
+			return result
+		}
+
+		// 指定した順に、ゴミ箱と他のユーザーのストーリーを除いて返す
+		stories, err := storyRepo.GetStoriesBySelection(user.ID, StorySelection{StoryIDs: []int{second.ID, trashed.ID, foreign.ID, first.ID}})
+		require.NoError(t, err)
+		assert.Equal(t, []int{second.ID, first.ID}, ids(stories))
+		assert.NotEmpty(t, stories[0].Content)
+
+		tagRepo := NewTagRepository(db)
+		tag := &model.Tag{UserID: user.ID, Name: "export"}
+		require.NoError(t, tagRepo.CreateTag(tag))
+		require.NoError(t, tagRepo.AttachTag(second.ID, tag.ID))
+		require.NoError(t, tagRepo.AttachTag(first.ID, tag.ID))
+		stories, err = storyRepo.GetStoriesBySelection(user.ID, StorySelection{TagID: &tag.ID})
+		require.NoError(t, err)
+		assert.Equal(t, []int{first.ID, second.ID}, ids(stories))
+
+		shelfRepo := NewShelfRepository(db)
+		shelf := &model.Shelf{UserID: user.ID, Name: "export"}
+		require.NoError(t, shelfRepo.CreateShelf(shelf))
+		require.NoError(t, shelfRepo.AddStory(shelf.ID, second.ID))
+		require.NoError(t, shelfRepo.AddStory(shelf.ID, first.ID))
+		stories, err = storyRepo.GetStoriesBySelection(user.ID, StorySelection{ShelfID: &shelf.ID})
+		require.NoError(t, err)
+		assert.Equal(t, []int{second.ID, first.ID}, ids(stories))
+	})
+
+	t.Run("GetArchiveStories", func(t *testing.T) {
+		user := createTestUser(t, db)
+		first := createTestStory(t, db, user.ID, "First", 100)
+		second := createTestStory(t, db, user.ID, "Second", 200)
+		third := createTestStory(t, db, user.ID, "Third", 300)
+		trashed := createTestStory(t, db, user.ID, "Trashed", 400)
+		require.NoError(t, storyRepo.DeleteStory(trashed.ID))
+
+		tagRepo := NewTagRepository(db)
+		for _, name := range []string{"b-tag", "a-tag"} {
+			tag := &model.Tag{UserID: user.ID, Name: name}
+			require.NoError(t, tagRepo.CreateTag(tag))
+			require.NoError(t, tagRepo.AttachTag(first.ID, tag.ID))
+		}
+		createTestReadingRecord(t, db, user.ID, first.ID, first.WordCount, time.Now())
+
+		stories, err := storyRepo.GetArchiveStories(user.ID, 0, 2)
+		require.NoError(t, err)
+		require.Len(t, stories, 2)
+		assert.Equal(t, first.ID, stories[0].ID)
+		assert.Equal(t, []string{"a-tag", "b-tag"}, stories[0].Tags)
+		require.Len(t, stories[0].ReadingRecords, 1)
+		assert.Equal(t, 100, stories[0].ReadingRecords[0].WordCount)
+		assert.Empty(t, stories[1].Tags)
+
+		// 続きは最後の ID から取得する。ゴミ箱のストーリーは含まれない
+		stories, err = storyRepo.GetArchiveStories(user.ID, second.ID, 2)
+		require.NoError(t, err)
+		require.Len(t, stories, 1)
+		assert.Equal(t, third.ID, stories[0].ID)
+	})
+
+	t.Run("ImportArchiveStories", func(t *testing.T) {
+		user := createTestUser(t, db)
+		existing := createTestStory(t, db, user.ID, "Existing", 3)
+		renamed := createTestStory(t, db, user.ID, "Renamed Here", 3)
+		tagRepo := NewTagRepository(db)
+		require.NoError(t, tagRepo.CreateTag(&model.Tag{UserID: user.ID, Name: "news"}))
+
+		level := "B1"
+		createdAt := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+		readAt := time.Date(2024, 5, 2, 8, 30, 0, 0, time.UTC)
+		newStories := func() []*model.ArchiveStory {
+			return []*model.ArchiveStory{
+				{Story: model.Story{Title: "Existing", Content: existing.Content, WordCount: 3, Source: model.StorySourceGenerated, CreatedAt: createdAt}},
+				{Story: model.Story{Title: "Renamed There", Content: renamed.Content, WordCount: 3, Source: model.StorySourceGenerated, CreatedAt: createdAt}},
+				{
+					Story:          model.Story{Title: "Rivers", Content: "Heavy rain fell.", WordCount: 3, Level: &level, Source: model.StorySourceImported, IsFavorite: true, CreatedAt: createdAt},
+					Tags:           []string{"news", "weather"},
+					ReadingRecords: []*model.ReadingRecord{{ReadAt: readAt, WordCount: 3}},
+				},
+				// アーカイブ内で本文が重複している場合は、先に作成したものに揃える
+				{Story: model.Story{Title: "Rivers", Content: "Heavy rain fell.", WordCount: 3, Level: &level, Source: model.StorySourceImported, CreatedAt: createdAt}},
+			}
+		}
+
+		result, err := storyRepo.ImportArchiveStories(user.ID, []string{"news", "weather"}, newStories())
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, 2, result.Duplicates)
+		assert.Equal(t, 1, result.Conflicts)
+		assert.Equal(t, []string{"weather"}, result.CreatedTags)
+		require.Len(t, result.Stories, 4)
+		assert.Equal(t, model.ArchiveImportDuplicate, result.Stories[0].Status)
+		assert.Equal(t, existing.ID, result.Stories[0].StoryID)
+		assert.Equal(t, model.ArchiveImportConflict, result.Stories[1].Status)
+		assert.Equal(t, []string{"title"}, result.Stories[1].Conflicts)
+		assert.Equal(t, renamed.ID, result.Stories[1].StoryID)
+		assert.Equal(t, model.ArchiveImportCreated, result.Stories[2].Status)
+		assert.Equal(t, 2, result.Stories[2].AddedTags)
+		assert.Equal(t, 1, result.Stories[2].AddedReadingRecords)
+		assert.Equal(t, result.Stories[2].StoryID, result.Stories[3].StoryID)
+
+		created, err := storyRepo.GetUserStory(result.Stories[2].StoryID, user.ID)
+		require.NoError(t, err)
+		assert.True(t, created.CreatedAt.Equal(createdAt))
+		assert.True(t, created.IsFavorite)
+		assert.Equal(t, model.StorySourceImported, created.Source)
+
+		var renamedTitle string
+		require.NoError(t, db.Get(&renamedTitle, "SELECT title FROM stories WHERE id = $1", renamed.ID))
+		assert.Equal(t, "Renamed Here", renamedTitle, "existing values should be kept on conflict")
+
+		// 同じアーカイブを取り込み直しても何も増えない
+		result, err = storyRepo.ImportArchiveStories(user.ID, []string{"news", "weather"}, newStories())
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Created)
+		assert.Empty(t, result.CreatedTags)
+		for _, item := range result.Stories {
+			assert.Zero(t, item.AddedTags)
+			assert.Zero(t, item.AddedReadingRecords)
+		}
+
+		var storyCount, recordCount int
+		require.NoError(t, db.Get(&storyCount, "SELECT COUNT(*) FROM stories WHERE user_id = $1", user.ID))
+		require.NoError(t, db.Get(&recordCount, "SELECT COUNT(*) FROM reading_records WHERE user_id = $1", user.ID))
+		assert.Equal(t, 3, storyCount)
+		assert.Equal(t, 1, recordCount)
+	})
+
 	t.Run("UpdateStoryTitle", func(t *testing.T) {
 		user := createTestUser(t, db)
 		originalStory := createTestStory(t, db, user.ID, "Original Title", 10)
@@ -140,7 +595,7 @@ func TestStoryRepository(t *testing.T) {
 		assert.True(t, updatedStory.UpdatedAt.After(originalStory.UpdatedAt), "UpdatedAt should be updated to a later time")
 
 		var fetchedStory model.Story
-		err = db.Get(&fetchedStory, "SELECT * FROM stories WHERE id = $1", originalStory.ID)
+		err = db.Get(&fetchedStory, storySelectForTest, originalStory.ID)
 		require.NoError(t, err)
 		assert.Equal(t, newTitle, fetchedStory.Title)
 		assert.Equal(t, originalStory.Content, fetchedStory.Content)
@@ -148,6 +603,75 @@ func TestStoryRepository(t *testing.T) {
 		assert.True(t, fetchedStory.UpdatedAt.After(originalStory.UpdatedAt), "UpdatedAt in DB should be updated to a later time")
 	})
 
+	t.Run("UpdateStoryContent saves the previous revision", func(t *testing.T) {
+		user := createTestUser(t, db)
+		original := createTestStory(t, db, user.ID, "Original Title", 10)
+		createTestReadingRecord(t, db, user.ID, original.ID, original.WordCount, time.Now())
+		translation := &model.StoryTranslation{StoryID: original.ID, Language: model.TranslationLanguageJapanese, Paragraphs: []string{"訳"}}
+		require.NoError(t, NewTranslationRepository(db).UpsertTranslation(translation))
+
+		updated, err := storyRepo.UpdateStoryContent(original.ID, user.ID, "Edited Title", "Edited content.", 2)
+		require.NoError(t, err)
+		assert.Equal(t, "Edited content.", updated.Content)
+		assert.Equal(t, 2, updated.WordCount)
+
+		_, err = storyRepo.UpdateStoryContent(original.ID, user.ID, "Edited Title", "Edited content again.", 3)
+		require.NoError(t, err)
+
+		revisions, err := storyRepo.GetStoryRevisions(original.ID)
+		require.NoError(t, err)
+		require.Len(t, revisions, 2)
+		assert.Equal(t, 2, revisions[0].RevisionNumber)
+		assert.Empty(t, revisions[0].Content, "list should not include the content")
+
+		first, err := storyRepo.GetStoryRevision(original.ID, 1)
+		require.NoError(t, err)
+		assert.Equal(t, original.Title, first.Title)
+		assert.Equal(t, original.Content, first.Content)
+		assert.Equal(t, original.WordCount, first.WordCount)
+
+		// 読了記録は読んだ時点の語数のまま
+		var recordedWords int
+		require.NoError(t, db.Get(&recordedWords, "SELECT word_count FROM reading_records WHERE story_id = $1", original.ID))
+		assert.Equal(t, original.WordCount, recordedWords)
+
+		_, err = NewTranslationRepository(db).GetTranslation(original.ID, model.TranslationLanguageJapanese)
+		assert.ErrorIs(t, err, sql.ErrNoRows, "stale translations should be removed")
+
+		other := createTestUser(t, db)
+		_, err = storyRepo.UpdateStoryContent(original.ID, other.ID, "Hijacked", "Hijacked.", 1)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("GetDerivedStories", func(t *testing.T) {
+		user := createTestUser(t, db)
+		original := createTestStory(t, db, user.ID, "Original", 300)
+
+		level := "A2"
+		derived := &model.Story{
+			UserID:        user.ID,
+			Title:         "Original [A2]",
+			Content:       "Simpler content.",
+			WordCount:     2,
+			Level:         &level,
+			ParentStoryID: &original.ID,
+		}
+		require.NoError(t, storyRepo.CreateStory(derived))
+
+		variants, err := storyRepo.GetDerivedStories(original.ID, user.ID)
+		require.NoError(t, err)
+		require.Len(t, variants, 1)
+		assert.Equal(t, derived.ID, variants[0].StoryID)
+		require.NotNil(t, variants[0].Level)
+		assert.Equal(t, "A2", *variants[0].Level)
+		assert.Equal(t, 2, variants[0].WordCount)
+
+		fetched, err := storyRepo.GetUserStory(derived.ID, user.ID)
+		require.NoError(t, err)
+		require.NotNil(t, fetched.ParentStoryID)
+		assert.Equal(t, original.ID, *fetched.ParentStoryID)
+	})
+
 	// t.Run("CreateReadingRecord", func(t *testing.T) {
 	// 	user := createTestUser(t, db)
 	// 	story := createTestStory(t, storyRepo, user.ID, "This is a test story for reading record")
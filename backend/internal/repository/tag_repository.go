@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+)
+
+var ErrTagAlreadyExists = errors.New("tag already exists")
+
+// ITagRepository: tags / story_tags テーブルの操作インターフェース
+type ITagRepository interface {
+	CreateTag(tag *model.Tag) error
+	GetUserTag(tagID, userID int) (*model.Tag, error)
+	ListTags(userID int) ([]*model.Tag, error)
+	RenameTag(tag *model.Tag) error
+	DeleteTag(tagID int) error
+	AttachTag(storyID, tagID int) error
+	DetachTag(storyID, tagID int) error
+	GetStoryTags(storyID int) ([]*model.Tag, error)
+}
+
+type sqlxTagRepository struct {
+	DB *sqlx.DB
+}
+
+func NewTagRepository(db *sqlx.DB) ITagRepository {
+	return &sqlxTagRepository{DB: db}
+}
+
+func (r *sqlxTagRepository) CreateTag(tag *model.Tag) error {
+	query := `
+		INSERT INTO tags(user_id, name)
+		VALUES ($1, $2)
+		RETURNING id, created_at, updated_at
+	`
+	err := r.DB.QueryRowx(query, tag.UserID, tag.Name).Scan(&tag.ID, &tag.CreatedAt, &tag.UpdatedAt)
+	if err != nil {
+		return wrapTagError("failed to create tag", err)
+	}
+	return nil
+}
+
+func (r *sqlxTagRepository) GetUserTag(tagID, userID int) (*model.Tag, error) {
+	query := `
+		SELECT t.id, t.user_id, t.name, t.created_at, t.updated_at,
+			(
+				SELECT COUNT(*) FROM story_tags st
+				JOIN stories s ON s.id = st.story_id AND s.deleted_at IS NULL
+				WHERE st.tag_id = t.id
+			) AS story_count
+		FROM tags t
+		WHERE t.id = $1 AND t.user_id = $2
+	`
+	var tag model.Tag
+	err := r.DB.Get(&tag, query, tagID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+	return &tag, nil
+}
+
+// ListTags はユーザーのタグを名前順に、付与されているストーリー数 (ゴミ箱を除く) とともに返す
+func (r *sqlxTagRepository) ListTags(userID int) ([]*model.Tag, error) {
+	query := `
+		SELECT t.id, t.user_id, t.name, t.created_at, t.updated_at, COUNT(st.story_id) AS story_count
+		FROM tags t
+		LEFT JOIN (
+			story_tags st
+			JOIN stories s ON s.id = st.story_id AND s.deleted_at IS NULL
+		) ON st.tag_id = t.id
+		WHERE t.user_id = $1
+		GROUP BY t.id
+		ORDER BY t.name ASC, t.id ASC
+	`
+	var tags []*model.Tag
+	err := r.DB.Select(&tags, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	return tags, nil
+}
+
+func (r *sqlxTagRepository) RenameTag(tag *model.Tag) error {
+	query := `
+		UPDATE tags
+		SET name = $1
+		WHERE id = $2
+		RETURNING updated_at
+	`
+	err := r.DB.QueryRowx(query, tag.Name, tag.ID).Scan(&tag.UpdatedAt)
+	if err != nil {
+		return wrapTagError("failed to rename tag", err)
+	}
+	return nil
+}
+
+func (r *sqlxTagRepository) DeleteTag(tagID int) error {
+	_, err := r.DB.Exec(`DELETE FROM tags WHERE id = $1`, tagID)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	return nil
+}
+
+// AttachTag はストーリーにタグを付ける。既に付いている場合は何もしない
+func (r *sqlxTagRepository) AttachTag(storyID, tagID int) error {
+	query := `
+		INSERT INTO story_tags(story_id, tag_id)
+		VALUES ($1, $2)
+		ON CONFLICT (story_id, tag_id) DO NOTHING
+	`
+	_, err := r.DB.Exec(query, storyID, tagID)
+	if err != nil {
+		return fmt.Errorf("failed to attach tag: %w", err)
+	}
+	return nil
+}
+
+func (r *sqlxTagRepository) DetachTag(storyID, tagID int) error {
+	_, err := r.DB.Exec(`DELETE FROM story_tags WHERE story_id = $1 AND tag_id = $2`, storyID, tagID)
+	if err != nil {
+		return fmt.Errorf("failed to detach tag: %w", err)
+	}
+	return nil
+}
+
+// GetStoryTags はストーリーに付いているタグを名前順に返す
+func (r *sqlxTagRepository) GetStoryTags(storyID int) ([]*model.Tag, error) {
+	query := `
+		SELECT t.id, t.user_id, t.name, t.created_at, t.updated_at,
+			(
+				SELECT COUNT(*) FROM story_tags c
+				JOIN stories s ON s.id = c.story_id AND s.deleted_at IS NULL
+				WHERE c.tag_id = t.id
+			) AS story_count
+		FROM story_tags st
+		JOIN tags t ON t.id = st.tag_id
+		WHERE st.story_id = $1
+		ORDER BY t.name ASC, t.id ASC
+	`
+	var tags []*model.Tag
+	err := r.DB.Select(&tags, query, storyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get story tags: %w", err)
+	}
+	return tags, nil
+}
+
+// wrapTagError は (user_id, name) の一意制約違反を ErrTagAlreadyExists に変換する
+func wrapTagError(message string, err error) error {
+	if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
+		return ErrTagAlreadyExists
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
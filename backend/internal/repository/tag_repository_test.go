@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagRepository(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTagRepository(db)
+	storyRepo := NewStoryRepository(db)
+
+	t.Run("CreateTag rejects duplicate names per user", func(t *testing.T) {
+		user := createTestUser(t, db)
+		other := createTestUser(t, db)
+
+		require.NoError(t, repo.CreateTag(&model.Tag{UserID: user.ID, Name: "science"}))
+		assert.ErrorIs(t, repo.CreateTag(&model.Tag{UserID: user.ID, Name: "science"}), ErrTagAlreadyExists)
+		// 別のユーザーは同じ名前を使える
+		assert.NoError(t, repo.CreateTag(&model.Tag{UserID: other.ID, Name: "science"}))
+	})
+
+	t.Run("AttachTag, GetStoryTags and the list filter", func(t *testing.T) {
+		user := createTestUser(t, db)
+		tagged := createTestStory(t, db, user.ID, "Tagged", 100)
+		untagged := createTestStory(t, db, user.ID, "Untagged", 100)
+
+		travel := &model.Tag{UserID: user.ID, Name: "travel"}
+		science := &model.Tag{UserID: user.ID, Name: "science"}
+		require.NoError(t, repo.CreateTag(travel))
+		require.NoError(t, repo.CreateTag(science))
+
+		require.NoError(t, repo.AttachTag(tagged.ID, travel.ID))
+		require.NoError(t, repo.AttachTag(tagged.ID, science.ID))
+		// 二重に付けてもエラーにならない
+		require.NoError(t, repo.AttachTag(tagged.ID, travel.ID))
+
+		tags, err := repo.GetStoryTags(tagged.ID)
+		require.NoError(t, err)
+		require.Len(t, tags, 2)
+		assert.Equal(t, "science", tags[0].Name)
+		assert.Equal(t, "travel", tags[1].Name)
+
+		listed, err := repo.ListTags(user.ID)
+		require.NoError(t, err)
+		require.Len(t, listed, 2)
+		assert.Equal(t, 1, listed[1].StoryCount)
+
+		stories, err := storyRepo.GetUserStories(user.ID, StoryFilter{TagID: &travel.ID}, StorySortDefault, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, stories, 1)
+		assert.Equal(t, tagged.ID, stories[0].ID)
+
+		require.NoError(t, repo.DetachTag(tagged.ID, travel.ID))
+		count, err := storyRepo.CountUserStories(user.ID, StoryFilter{TagID: &travel.ID})
+		require.NoError(t, err)
+		assert.Zero(t, count)
+
+		tags, err = repo.GetStoryTags(untagged.ID)
+		require.NoError(t, err)
+		assert.Empty(t, tags)
+	})
+
+	t.Run("RenameTag and DeleteTag", func(t *testing.T) {
+		user := createTestUser(t, db)
+		story := createTestStory(t, db, user.ID, "Story", 100)
+		tag := &model.Tag{UserID: user.ID, Name: "exam"}
+		require.NoError(t, repo.CreateTag(tag))
+		require.NoError(t, repo.CreateTag(&model.Tag{UserID: user.ID, Name: "taken"}))
+		require.NoError(t, repo.AttachTag(story.ID, tag.ID))
+
+		tag.Name = "exam prep"
+		require.NoError(t, repo.RenameTag(tag))
+		fetched, err := repo.GetUserTag(tag.ID, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "exam prep", fetched.Name)
+		assert.Equal(t, 1, fetched.StoryCount)
+
+		tag.Name = "taken"
+		assert.ErrorIs(t, repo.RenameTag(tag), ErrTagAlreadyExists)
+
+		_, err = repo.GetUserTag(tag.ID, user.ID+1)
+		assert.Error(t, err)
+
+		require.NoError(t, repo.DeleteTag(tag.ID))
+		tags, err := repo.GetStoryTags(story.ID)
+		require.NoError(t, err)
+		assert.Empty(t, tags)
+	})
+}
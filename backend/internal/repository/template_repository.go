@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+)
+
+// ITemplateRepository: prompt_templates テーブルの操作インターフェース
+type ITemplateRepository interface {
+	CreateTemplate(template *model.PromptTemplate) error
+	GetTemplate(templateID int) (*model.PromptTemplate, error)
+	ListTemplates(userID int) ([]*model.PromptTemplate, error)
+	UpdateTemplate(template *model.PromptTemplate) error
+	DeleteTemplate(templateID int) error
+}
+
+type sqlxTemplateRepository struct {
+	DB *sqlx.DB
+}
+
+func NewTemplateRepository(db *sqlx.DB) ITemplateRepository {
+	return &sqlxTemplateRepository{DB: db}
+}
+
+func (r *sqlxTemplateRepository) CreateTemplate(template *model.PromptTemplate) error {
+	query := `
+		INSERT INTO prompt_templates(user_id, name, body, default_level, default_word_count)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+	err := r.DB.QueryRowx(query, template.UserID, template.Name, template.Body, template.DefaultLevel, template.DefaultWordCount).Scan(&template.ID, &template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create template: %w", err)
+	}
+	return nil
+}
+
+func (r *sqlxTemplateRepository) GetTemplate(templateID int) (*model.PromptTemplate, error) {
+	query := `
+		SELECT id, user_id, name, body, default_level, default_word_count, created_at, updated_at
+		FROM prompt_templates
+		WHERE id = $1
+	`
+	var template model.PromptTemplate
+	err := r.DB.Get(&template, query, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+	return &template, nil
+}
+
+// ListTemplates はユーザー自身のテンプレートとプリセットを返す (プリセットが先頭)
+func (r *sqlxTemplateRepository) ListTemplates(userID int) ([]*model.PromptTemplate, error) {
+	query := `
+		SELECT id, user_id, name, body, default_level, default_word_count, created_at, updated_at
+		FROM prompt_templates
+		WHERE user_id = $1 OR user_id IS NULL
+		ORDER BY (user_id IS NULL) DESC, name ASC, id ASC
+	`
+	var templates []*model.PromptTemplate
+	err := r.DB.Select(&templates, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+	return templates, nil
+}
+
+func (r *sqlxTemplateRepository) UpdateTemplate(template *model.PromptTemplate) error {
+	query := `
+		UPDATE prompt_templates
+		SET name = $1, body = $2, default_level = $3, default_word_count = $4
+		WHERE id = $5
+		RETURNING updated_at
+	`
+	err := r.DB.QueryRowx(query, template.Name, template.Body, template.DefaultLevel, template.DefaultWordCount, template.ID).Scan(&template.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update template: %w", err)
+	}
+	return nil
+}
+
+func (r *sqlxTemplateRepository) DeleteTemplate(templateID int) error {
+	_, err := r.DB.Exec(`DELETE FROM prompt_templates WHERE id = $1`, templateID)
+	if err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+	return nil
+}
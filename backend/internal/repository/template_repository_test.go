@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateRepository(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTemplateRepository(db)
+
+	t.Run("ListTemplates returns own templates and global presets", func(t *testing.T) {
+		owner := createTestUser(t, db)
+		other := createTestUser(t, db)
+
+		level := "B1"
+		own := &model.PromptTemplate{UserID: &owner.ID, Name: "Mine", Body: "Explain {{topic}}", DefaultLevel: &level}
+		require.NoError(t, repo.CreateTemplate(own))
+		assert.NotZero(t, own.ID)
+
+		global := &model.PromptTemplate{Name: "Preset", Body: "News about {{topic}}"}
+		require.NoError(t, repo.CreateTemplate(global))
+
+		othersTemplate := &model.PromptTemplate{UserID: &other.ID, Name: "Theirs", Body: "Other"}
+		require.NoError(t, repo.CreateTemplate(othersTemplate))
+
+		templates, err := repo.ListTemplates(owner.ID)
+		require.NoError(t, err)
+		require.Len(t, templates, 2)
+		// プリセットが先頭
+		assert.Equal(t, global.ID, templates[0].ID)
+		assert.True(t, templates[0].IsGlobal())
+		assert.Equal(t, own.ID, templates[1].ID)
+		assert.Equal(t, "B1", *templates[1].DefaultLevel)
+	})
+
+	t.Run("UpdateTemplate and DeleteTemplate", func(t *testing.T) {
+		user := createTestUser(t, db)
+		template := &model.PromptTemplate{UserID: &user.ID, Name: "Before", Body: "Body"}
+		require.NoError(t, repo.CreateTemplate(template))
+
+		wordCount := 300
+		template.Name = "After"
+		template.DefaultWordCount = &wordCount
+		require.NoError(t, repo.UpdateTemplate(template))
+
+		fetched, err := repo.GetTemplate(template.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "After", fetched.Name)
+		assert.Equal(t, 300, *fetched.DefaultWordCount)
+
+		require.NoError(t, repo.DeleteTemplate(template.ID))
+		_, err = repo.GetTemplate(template.ID)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+)
+
+// ITranslationRepository: story_translations テーブルの操作インターフェース
+type ITranslationRepository interface {
+	UpsertTranslation(translation *model.StoryTranslation) error
+	GetTranslation(storyID int, language string) (*model.StoryTranslation, error)
+}
+
+type sqlxTranslationRepository struct {
+	DB *sqlx.DB
+}
+
+func NewTranslationRepository(db *sqlx.DB) ITranslationRepository {
+	return &sqlxTranslationRepository{DB: db}
+}
+
+func (r *sqlxTranslationRepository) UpsertTranslation(translation *model.StoryTranslation) error {
+	query := `
+		INSERT INTO story_translations(story_id, language, paragraphs)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (story_id, language)
+		DO UPDATE SET paragraphs = EXCLUDED.paragraphs
+		RETURNING created_at, updated_at
+	`
+	err := r.DB.QueryRowx(query, translation.StoryID, translation.Language, translation.Paragraphs).Scan(&translation.CreatedAt, &translation.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save translation: %w", err)
+	}
+	return nil
+}
+
+func (r *sqlxTranslationRepository) GetTranslation(storyID int, language string) (*model.StoryTranslation, error) {
+	query := `
+		SELECT story_id, language, paragraphs, created_at, updated_at
+		FROM story_translations
+		WHERE story_id = $1 AND language = $2
+	`
+	var translation model.StoryTranslation
+	err := r.DB.Get(&translation, query, storyID, language)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get translation: %w", err)
+	}
+	return &translation, nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslationRepository(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTranslationRepository(db)
+
+	t.Run("UpsertTranslation and GetTranslation", func(t *testing.T) {
+		user := createTestUser(t, db)
+		story := createTestStory(t, db, user.ID, "Bilingual", 100)
+
+		first := &model.StoryTranslation{
+			StoryID:    story.ID,
+			Language:   model.TranslationLanguageJapanese,
+			Paragraphs: pq.StringArray{"古い訳"},
+		}
+		require.NoError(t, repo.UpsertTranslation(first))
+
+		second := &model.StoryTranslation{
+			StoryID:    story.ID,
+			Language:   model.TranslationLanguageJapanese,
+			Paragraphs: pq.StringArray{"段落1", "段落2"},
+		}
+		require.NoError(t, repo.UpsertTranslation(second))
+
+		fetched, err := repo.GetTranslation(story.ID, model.TranslationLanguageJapanese)
+		require.NoError(t, err)
+		assert.Equal(t, pq.StringArray{"段落1", "段落2"}, fetched.Paragraphs)
+
+		// 翻訳は本文の語数に影響しない
+		var wordCount int
+		require.NoError(t, db.Get(&wordCount, "SELECT word_count FROM stories WHERE id = $1", story.ID))
+		assert.Equal(t, 100, wordCount)
+	})
+
+	t.Run("GetTranslation returns an error when missing", func(t *testing.T) {
+		user := createTestUser(t, db)
+		story := createTestStory(t, db, user.ID, "No translation", 10)
+
+		_, err := repo.GetTranslation(story.ID, model.TranslationLanguageJapanese)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
+)
+
+var (
+	ErrCommunityStoryNotFound  = errors.New("community story not found")
+	ErrCommunityLevelRequired  = errors.New("level is required to publish a story without a level")
+	ErrInvalidCommunityLevel   = errors.New("level must be one of [A1 A2 B1 B2 C1 C2]")
+	ErrTooManyCommunityTags    = fmt.Errorf("a story can have at most %d tags", maxCommunityTags)
+	ErrInvalidCommunityTagName = fmt.Errorf("tag names must be 1 to %d characters", maxCommunityTagLength)
+)
+
+const (
+	// maxCommunityTags は公開するストーリーに付けられるタグの数
+	maxCommunityTags = 10
+	// maxCommunityTagLength はタグ名の最大文字数 (tags.name と同じ)
+	maxCommunityTagLength = 50
+	// maxCommunityPageSize は公開ライブラリの 1 ページの最大件数
+	maxCommunityPageSize = 50
+)
+
+type ICommunityService interface {
+	PublishStory(storyID, userID int, input PublishInput) (*model.CommunityStory, error)
+	UnpublishStory(communityStoryID, userID int) error
+	ListCommunityStories(userID int, query CommunityListQuery) (*PaginatedCommunityStories, error)
+	GetCommunityStory(communityStoryID, userID int) (*model.CommunityStory, error)
+	LikeStory(communityStoryID, userID int) (int, error)
+	UnlikeStory(communityStoryID, userID int) (int, error)
+	CloneStory(communityStoryID, userID int) (*model.Story, error)
+}
+
+// PublishInput は公開時に指定するレベルとタグ。Level が nil の場合はストーリーのレベルを使う
+type PublishInput struct {
+	Level *string
+	Tags  []string
+}
+
+// CommunityListQuery は公開ライブラリの一覧の取得条件
+type CommunityListQuery struct {
+	repository.CommunityFilter
+	Sort  repository.CommunitySort
+	Page  int
+	Limit int
+}
+
+type PaginatedCommunityStories struct {
+	Stories     []*model.CommunityStory
+	TotalCount  int
+	TotalPages  int
+	CurrentPage int
+}
+
+type CommunityService struct {
+	CommunityRepo repository.ICommunityRepository
+	StoryRepo     repository.IStoryRepository
+}
+
+func NewCommunityService(communityRepo repository.ICommunityRepository, storyRepo repository.IStoryRepository) ICommunityService {
+	return &CommunityService{
+		CommunityRepo: communityRepo,
+		StoryRepo:     storyRepo,
+	}
+}
+
+// PublishStory はストーリーの写しを公開ライブラリに公開する。公開済みの場合は現在の内容・レベル・タグで置き換える
+func (s *CommunityService) PublishStory(storyID, userID int, input PublishInput) (*model.CommunityStory, error) {
+	story, err := s.StoryRepo.GetUserStory(storyID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrStoryNotFound
+		}
+		return nil, fmt.Errorf("database error (get story): %w", err)
+	}
+
+	level := input.Level
+	if level == nil {
+		level = story.Level
+	}
+	if level == nil || *level == "" {
+		return nil, ErrCommunityLevelRequired
+	}
+	if !slices.Contains(model.CEFRLevels, *level) {
+		return nil, ErrInvalidCommunityLevel
+	}
+
+	tags, err := normalizeCommunityTags(input.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	published := &model.CommunityStory{
+		StoryID:   &story.ID,
+		UserID:    userID,
+		Title:     story.Title,
+		Content:   story.Content,
+		WordCount: story.WordCount,
+		Level:     *level,
+		Tags:      tags,
+	}
+	if err := s.CommunityRepo.PublishStory(published); err != nil {
+		return nil, fmt.Errorf("failed to publish story: %w", err)
+	}
+	return published, nil
+}
+
+// normalizeCommunityTags はタグ名の前後の空白を除き、重複を除いて入力順に返す
+func normalizeCommunityTags(names []string) ([]string, error) {
+	tags := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || utf8.RuneCountInString(name) > maxCommunityTagLength {
+			return nil, ErrInvalidCommunityTagName
+		}
+		if !slices.Contains(tags, name) {
+			tags = append(tags, name)
+		}
+	}
+	if len(tags) > maxCommunityTags {
+		return nil, ErrTooManyCommunityTags
+	}
+	return tags, nil
+}
+
+// UnpublishStory は公開を取り消す。公開したユーザー以外は ErrCommunityStoryNotFound になる。複製済みのストーリーは残る
+func (s *CommunityService) UnpublishStory(communityStoryID, userID int) error {
+	if err := s.CommunityRepo.DeleteCommunityStory(communityStoryID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCommunityStoryNotFound
+		}
+		return fmt.Errorf("failed to unpublish story: %w", err)
+	}
+	return nil
+}
+
+func (s *CommunityService) ListCommunityStories(userID int, query CommunityListQuery) (*PaginatedCommunityStories, error) {
+	page, limit := query.Page, query.Limit
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > maxCommunityPageSize {
+		limit = maxCommunityPageSize
+	}
+
+	totalCount, err := s.CommunityRepo.CountCommunityStories(query.CommunityFilter)
+	if err != nil {
+		return nil, fmt.Errorf("database error (count): %w", err)
+	}
+
+	totalPages := 0
+	if totalCount > 0 {
+		totalPages = int(math.Ceil(float64(totalCount) / float64(limit)))
+	}
+
+	stories, err := s.CommunityRepo.ListCommunityStories(userID, query.CommunityFilter, query.Sort, limit, (page-1)*limit)
+	if err != nil {
+		return nil, fmt.Errorf("database error (get): %w", err)
+	}
+	if stories == nil {
+		stories = []*model.CommunityStory{}
+	}
+
+	return &PaginatedCommunityStories{
+		Stories:     stories,
+		TotalCount:  totalCount,
+		TotalPages:  totalPages,
+		CurrentPage: page,
+	}, nil
+}
+
+func (s *CommunityService) GetCommunityStory(communityStoryID, userID int) (*model.CommunityStory, error) {
+	story, err := s.CommunityRepo.GetCommunityStory(communityStoryID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCommunityStoryNotFound
+		}
+		return nil, fmt.Errorf("database error (get community story): %w", err)
+	}
+	return story, nil
+}
+
+// LikeStory は「いいね」を付け、いいねの数を返す。同じユーザーが何度付けても 1 回と数える
+func (s *CommunityService) LikeStory(communityStoryID, userID int) (int, error) {
+	likeCount, err := s.CommunityRepo.LikeCommunityStory(communityStoryID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrCommunityStoryNotFound
+		}
+		return 0, fmt.Errorf("failed to like story: %w", err)
+	}
+	return likeCount, nil
+}
+
+func (s *CommunityService) UnlikeStory(communityStoryID, userID int) (int, error) {
+	likeCount, err := s.CommunityRepo.UnlikeCommunityStory(communityStoryID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrCommunityStoryNotFound
+		}
+		return 0, fmt.Errorf("failed to unlike story: %w", err)
+	}
+	return likeCount, nil
+}
+
+// CloneStory は公開ライブラリのストーリーを自分のライブラリに複製する。生成ではないため生成回数には数えない
+func (s *CommunityService) CloneStory(communityStoryID, userID int) (*model.Story, error) {
+	story, err := s.CommunityRepo.CloneCommunityStory(communityStoryID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCommunityStoryNotFound
+		}
+		return nil, fmt.Errorf("failed to clone story: %w", err)
+	}
+	return story, nil
+}
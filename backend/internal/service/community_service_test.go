@@ -0,0 +1,167 @@
+package service
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupCommunityServiceTest(t *testing.T) (*MockCommunityRepository, *MockStoryRepository, ICommunityService) {
+	mockCommunityRepo := new(MockCommunityRepository)
+	mockStoryRepo := new(MockStoryRepository)
+
+	communityService := NewCommunityService(mockCommunityRepo, mockStoryRepo)
+
+	return mockCommunityRepo, mockStoryRepo, communityService
+}
+
+func TestCommunityService_PublishStory(t *testing.T) {
+	mockCommunityRepo, mockStoryRepo, communityService := setupCommunityServiceTest(t)
+
+	t.Run("success: should publish a copy with the given level and normalized tags", func(t *testing.T) {
+		level := "B1"
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Once()
+		mockCommunityRepo.On("PublishStory", mock.MatchedBy(func(story *model.CommunityStory) bool {
+			return *story.StoryID == testStory.ID && story.UserID == testUser.ID &&
+				story.Content == testStory.Content && story.Level == "B1" &&
+				assert.ObjectsAreEqual([]string{"travel", "food"}, []string(story.Tags))
+		})).Return(nil).Once()
+
+		story, err := communityService.PublishStory(testStory.ID, testUser.ID, PublishInput{
+			Level: &level,
+			Tags:  []string{" travel ", "food", "travel"},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "B1", story.Level)
+		mockCommunityRepo.AssertExpectations(t)
+		mockStoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("success: should default to the story's level", func(t *testing.T) {
+		level := "A2"
+		story := &model.Story{ID: 11, UserID: testUser.ID, Title: "Leveled", Content: "Some text.", WordCount: 2, Level: &level}
+		mockStoryRepo.On("GetUserStory", story.ID, testUser.ID).Return(story, nil).Once()
+		mockCommunityRepo.On("PublishStory", mock.MatchedBy(func(published *model.CommunityStory) bool {
+			return published.Level == "A2" && len(published.Tags) == 0
+		})).Return(nil).Once()
+
+		_, err := communityService.PublishStory(story.ID, testUser.ID, PublishInput{})
+
+		require.NoError(t, err)
+		mockCommunityRepo.AssertExpectations(t)
+	})
+
+	t.Run("fail: should require a level when the story has none", func(t *testing.T) {
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Once()
+
+		_, err := communityService.PublishStory(testStory.ID, testUser.ID, PublishInput{})
+
+		assert.ErrorIs(t, err, ErrCommunityLevelRequired)
+	})
+
+	t.Run("fail: should reject blank or too many tags", func(t *testing.T) {
+		level := "B1"
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Twice()
+
+		_, err := communityService.PublishStory(testStory.ID, testUser.ID, PublishInput{Level: &level, Tags: []string{"  "}})
+		assert.ErrorIs(t, err, ErrInvalidCommunityTagName)
+
+		tags := strings.Split("a b c d e f g h i j k", " ")
+		_, err = communityService.PublishStory(testStory.ID, testUser.ID, PublishInput{Level: &level, Tags: tags})
+		assert.ErrorIs(t, err, ErrTooManyCommunityTags)
+	})
+
+	t.Run("fail: should return ErrStoryNotFound for another user's story", func(t *testing.T) {
+		mockStoryRepo.On("GetUserStory", testStory.ID, 999).Return(nil, sql.ErrNoRows).Once()
+
+		_, err := communityService.PublishStory(testStory.ID, 999, PublishInput{})
+
+		assert.ErrorIs(t, err, ErrStoryNotFound)
+	})
+}
+
+func TestCommunityService_UnpublishStory(t *testing.T) {
+	mockCommunityRepo, _, communityService := setupCommunityServiceTest(t)
+
+	t.Run("fail: should return ErrCommunityStoryNotFound when published by another user", func(t *testing.T) {
+		mockCommunityRepo.On("DeleteCommunityStory", 3, testUser.ID).Return(sql.ErrNoRows).Once()
+
+		err := communityService.UnpublishStory(3, testUser.ID)
+
+		assert.ErrorIs(t, err, ErrCommunityStoryNotFound)
+	})
+}
+
+func TestCommunityService_ListCommunityStories(t *testing.T) {
+	mockCommunityRepo, _, communityService := setupCommunityServiceTest(t)
+
+	t.Run("success: should cap the page size and compute pages", func(t *testing.T) {
+		filter := repository.CommunityFilter{Level: "B1"}
+		mockCommunityRepo.On("CountCommunityStories", filter).Return(120, nil).Once()
+		mockCommunityRepo.On("ListCommunityStories", testUser.ID, filter, repository.CommunitySortPopular, maxCommunityPageSize, maxCommunityPageSize).
+			Return(nil, nil).Once()
+
+		result, err := communityService.ListCommunityStories(testUser.ID, CommunityListQuery{
+			CommunityFilter: filter,
+			Sort:            repository.CommunitySortPopular,
+			Page:            2,
+			Limit:           1000,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.TotalPages)
+		assert.Equal(t, 2, result.CurrentPage)
+		assert.NotNil(t, result.Stories)
+		mockCommunityRepo.AssertExpectations(t)
+	})
+}
+
+func TestCommunityService_LikeStory(t *testing.T) {
+	mockCommunityRepo, _, communityService := setupCommunityServiceTest(t)
+
+	t.Run("success: should return the like count", func(t *testing.T) {
+		mockCommunityRepo.On("LikeCommunityStory", 3, testUser.ID).Return(5, nil).Once()
+
+		likeCount, err := communityService.LikeStory(3, testUser.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, 5, likeCount)
+	})
+
+	t.Run("fail: should return ErrCommunityStoryNotFound for an unknown story", func(t *testing.T) {
+		mockCommunityRepo.On("UnlikeCommunityStory", 4, testUser.ID).Return(0, sql.ErrNoRows).Once()
+
+		_, err := communityService.UnlikeStory(4, testUser.ID)
+
+		assert.ErrorIs(t, err, ErrCommunityStoryNotFound)
+	})
+}
+
+func TestCommunityService_CloneStory(t *testing.T) {
+	mockCommunityRepo, _, communityService := setupCommunityServiceTest(t)
+
+	t.Run("success: should return the cloned story", func(t *testing.T) {
+		cloned := &model.Story{ID: 20, UserID: testUser.ID, Source: model.StorySourceCommunity}
+		mockCommunityRepo.On("CloneCommunityStory", 3, testUser.ID).Return(cloned, nil).Once()
+
+		story, err := communityService.CloneStory(3, testUser.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, cloned, story)
+	})
+
+	t.Run("fail: should return ErrCommunityStoryNotFound for an unpublished story", func(t *testing.T) {
+		mockCommunityRepo.On("CloneCommunityStory", 4, testUser.ID).Return(nil, sql.ErrNoRows).Once()
+
+		_, err := communityService.CloneStory(4, testUser.ID)
+
+		assert.ErrorIs(t, err, ErrCommunityStoryNotFound)
+	})
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
+)
+
+var (
+	ErrInvalidCursor      = errors.New("invalid cursor")
+	ErrCursorNotSupported = errors.New("cursor pagination is not supported for this sort order")
+)
+
+// cursorPayload はクライアントに不透明な文字列として渡すカーソルの中身。
+// Kind で一覧の種類・並び順を区別し、別の一覧のカーソルが使われた場合は拒否する
+type cursorPayload struct {
+	Kind     string    `json:"k"`
+	Time     time.Time `json:"t"`
+	ID       int       `json:"i"`
+	Backward bool      `json:"b,omitempty"`
+}
+
+func encodeCursor(kind string, t time.Time, id int, backward bool) *string {
+	data, err := json.Marshal(cursorPayload{Kind: kind, Time: t, ID: id, Backward: backward})
+	if err != nil {
+		// 固定の構造体なので失敗しない
+		panic(err)
+	}
+	cursor := base64.RawURLEncoding.EncodeToString(data)
+	return &cursor
+}
+
+func decodeCursor(kind, cursor string) (*repository.Keyset, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(data, &payload); err != nil || payload.Kind != kind || payload.ID <= 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return &repository.Keyset{Time: payload.Time, ID: payload.ID, Backward: payload.Backward}, nil
+}
+
+// paginateKeyset は limit+1 件取得した結果から表示する範囲と前後のページのカーソルを決める。
+// 余分な 1 件は keyset の進行方向の末尾にあり、その有無で続きのページがあるかを判定する
+func paginateKeyset[T any](items []T, limit int, keyset *repository.Keyset, kind string, key func(T) (time.Time, int)) ([]T, *string, *string) {
+	backward := keyset != nil && keyset.Backward
+
+	hasMore := len(items) > limit
+	if hasMore {
+		if backward {
+			items = items[1:]
+		} else {
+			items = items[:limit]
+		}
+	}
+	if len(items) == 0 {
+		return items, nil, nil
+	}
+
+	firstTime, firstID := key(items[0])
+	lastTime, lastID := key(items[len(items)-1])
+
+	var next, prev *string
+	switch {
+	case keyset == nil:
+		// 最初のページ
+		if hasMore {
+			next = encodeCursor(kind, lastTime, lastID, false)
+		}
+	case backward:
+		next = encodeCursor(kind, lastTime, lastID, false)
+		if hasMore {
+			prev = encodeCursor(kind, firstTime, firstID, true)
+		}
+	default:
+		prev = encodeCursor(kind, firstTime, firstID, true)
+		if hasMore {
+			next = encodeCursor(kind, lastTime, lastID, false)
+		}
+	}
+	return items, next, prev
+}
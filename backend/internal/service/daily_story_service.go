@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
+)
+
+const (
+	// maxDailyDeliveriesPerRun はジョブ 1 回で生成する「今日の文章」の上限。残りは次回の実行に回す
+	maxDailyDeliveriesPerRun = 20
+	// dailyStoryGenerationBudget は 1 件の生成に見込む時間 (LLM 呼び出しのタイムアウト 20 秒と保存)。
+	// 実行の期限までにこれだけ残っていない場合は、途中で打ち切られないよう次回の実行に回す
+	dailyStoryGenerationBudget = 25 * time.Second
+)
+
+var (
+	ErrDailyStoryPreferenceNotFound = errors.New("daily story preference not found")
+	ErrInvalidTimeZone              = errors.New("invalid time zone")
+	ErrNoDailyStoryTopics           = errors.New("at least one topic is required")
+)
+
+// DailyStoryPreferenceInput は「今日の文章」設定の更新内容
+type DailyStoryPreferenceInput struct {
+	Enabled      bool
+	Topics       []string
+	Level        *string
+	WordCount    *int
+	DeliveryHour int
+	TimeZone     string
+}
+
+// DeliveryReport はスケジュールジョブ 1 回分の実行結果
+type DeliveryReport struct {
+	Delivered int
+	// Skipped は生成上限に達していたユーザー数。次回の実行で再試行される
+	Skipped int
+	Failed  int
+	// Deferred は件数の上限や実行の期限のため、次回の実行に回したユーザー数
+	Deferred int
+}
+
+type IDailyStoryService interface {
+	GetPreference(userID int) (*model.DailyStoryPreference, error)
+	UpdatePreference(userID int, input DailyStoryPreferenceInput) (*model.DailyStoryPreference, error)
+	DeliverDueStories(ctx context.Context, now time.Time) (*DeliveryReport, error)
+}
+
+type DailyStoryService struct {
+	DailyStoryRepo repository.IDailyStoryRepository
+	StoryService   IStoryService
+}
+
+func NewDailyStoryService(dailyStoryRepo repository.IDailyStoryRepository, storyService IStoryService) IDailyStoryService {
+	return &DailyStoryService{
+		DailyStoryRepo: dailyStoryRepo,
+		StoryService:   storyService,
+	}
+}
+
+func (s *DailyStoryService) GetPreference(userID int) (*model.DailyStoryPreference, error) {
+	preference, err := s.DailyStoryRepo.GetPreference(userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDailyStoryPreferenceNotFound
+		}
+		return nil, fmt.Errorf("database error (get daily story preference): %w", err)
+	}
+	return preference, nil
+}
+
+func (s *DailyStoryService) UpdatePreference(userID int, input DailyStoryPreferenceInput) (*model.DailyStoryPreference, error) {
+	if _, err := time.LoadLocation(input.TimeZone); err != nil || input.TimeZone == "" {
+		return nil, ErrInvalidTimeZone
+	}
+
+	topics := make(pq.StringArray, 0, len(input.Topics))
+	for _, topic := range input.Topics {
+		if topic = strings.TrimSpace(topic); topic != "" {
+			topics = append(topics, topic)
+		}
+	}
+	if len(topics) == 0 {
+		return nil, ErrNoDailyStoryTopics
+	}
+
+	preference := &model.DailyStoryPreference{
+		UserID:       userID,
+		Enabled:      input.Enabled,
+		Topics:       topics,
+		Level:        input.Level,
+		WordCount:    input.WordCount,
+		DeliveryHour: input.DeliveryHour,
+		TimeZone:     input.TimeZone,
+	}
+	if err := s.DailyStoryRepo.UpsertPreference(preference); err != nil {
+		return nil, fmt.Errorf("failed to save daily story preference: %w", err)
+	}
+	return preference, nil
+}
+
+// DeliverDueStories は配信時刻を過ぎていて本日分が未配信のユーザーに文章を生成する。
+// 生成は StoryService を経由するため、通常の生成と同じく 1 日の生成上限が適用される。
+// 生成の前に配信日を記録するため、並行して実行されたジョブや途中で打ち切られた実行が同じユーザーに重ねて生成することはない。
+// ctx の期限までに生成を終えられない場合や、1 回の上限件数に達した場合は残りを次回の実行に回す
+func (s *DailyStoryService) DeliverDueStories(ctx context.Context, now time.Time) (*DeliveryReport, error) {
+	preferences, err := s.DailyStoryRepo.ListEnabledPreferences()
+	if err != nil {
+		return nil, fmt.Errorf("database error (list daily story preferences): %w", err)
+	}
+
+	report := &DeliveryReport{}
+	attempts := 0
+	for _, preference := range preferences {
+		localNow, due := isDeliveryDue(preference, now)
+		if !due {
+			continue
+		}
+		if attempts >= maxDailyDeliveriesPerRun || !hasTimeFor(ctx, dailyStoryGenerationBudget) {
+			report.Deferred++
+			continue
+		}
+
+		claimed, err := s.DailyStoryRepo.ClaimDelivery(preference.UserID, localNow)
+		if err != nil {
+			log.Printf("failed to claim daily story for user %d: %v", preference.UserID, err)
+			report.Failed++
+			continue
+		}
+		if !claimed {
+			// 並行して実行したジョブが先に配信した
+			continue
+		}
+		attempts++
+
+		input := GenerateStoryInput{Prompt: pickDailyTopic(preference.Topics, localNow)}
+		if preference.Level != nil {
+			input.Level = *preference.Level
+		}
+		if preference.WordCount != nil {
+			input.WordCount = *preference.WordCount
+		}
+
+		if _, err := s.StoryService.GenerateStory(preference.UserID, input); err != nil {
+			// 生成できなかった場合は記録を戻し、次回の実行で再試行する
+			if releaseErr := s.DailyStoryRepo.ReleaseDelivery(preference.UserID, localNow, preference.LastDeliveredOn); releaseErr != nil {
+				log.Printf("failed to release daily story for user %d: %v", preference.UserID, releaseErr)
+			}
+			if errors.Is(err, ErrGenerationLimitExceeded) {
+				report.Skipped++
+				continue
+			}
+			log.Printf("failed to generate daily story for user %d: %v", preference.UserID, err)
+			report.Failed++
+			continue
+		}
+		report.Delivered++
+	}
+
+	return report, nil
+}
+
+// hasTimeFor は ctx が終了しておらず、期限までに budget 以上残っているかどうかを返す
+func hasTimeFor(ctx context.Context, budget time.Duration) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	deadline, ok := ctx.Deadline()
+	return !ok || time.Until(deadline) >= budget
+}
+
+// isDeliveryDue はユーザーのタイムゾーンでの現在時刻と、配信対象かどうかを返す
+func isDeliveryDue(preference *model.DailyStoryPreference, now time.Time) (time.Time, bool) {
+	loc, err := time.LoadLocation(preference.TimeZone)
+	if err != nil {
+		log.Printf("invalid time zone %q for user %d: %v", preference.TimeZone, preference.UserID, err)
+		return time.Time{}, false
+	}
+
+	localNow := now.In(loc)
+	if localNow.Hour() < preference.DeliveryHour {
+		return localNow, false
+	}
+
+	today := localNow.Format(time.DateOnly)
+	if preference.LastDeliveredOn != nil && preference.LastDeliveredOn.Format(time.DateOnly) >= today {
+		return localNow, false
+	}
+	return localNow, true
+}
+
+// pickDailyTopic は日付ごとにトピックを順番に切り替える
+func pickDailyTopic(topics []string, localNow time.Time) string {
+	day := time.Date(localNow.Year(), localNow.Month(), localNow.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(day.Unix() / int64(24*time.Hour/time.Second))
+	return topics[days%len(topics)]
+}
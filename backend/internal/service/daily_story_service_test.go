@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDailyStoryService_DeliverDueStories(t *testing.T) {
+	// 生成は実際の StoryService を経由させ、生成上限の判定も含めて確認する
+	mockStoryRepo, _, _, _, mockUserRepo, _, mockLLM, storyService := setupStoryServiceTest(t)
+	mockDailyRepo := new(MockDailyStoryRepository)
+	dailyStoryService := NewDailyStoryService(mockDailyRepo, storyService)
+
+	// 2025-06-01 22:30 UTC = 2025-06-02 07:30 JST
+	now := time.Date(2025, 6, 1, 22, 30, 0, 0, time.UTC)
+	yesterday := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	today := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	level := "B1"
+
+	due := &model.DailyStoryPreference{UserID: 1, Enabled: true, Topics: pq.StringArray{"space"}, Level: &level, DeliveryHour: 7, TimeZone: "Asia/Tokyo", LastDeliveredOn: &yesterday}
+	notYet := &model.DailyStoryPreference{UserID: 2, Enabled: true, Topics: pq.StringArray{"cooking"}, DeliveryHour: 9, TimeZone: "Asia/Tokyo"}
+	alreadyDelivered := &model.DailyStoryPreference{UserID: 3, Enabled: true, Topics: pq.StringArray{"music"}, DeliveryHour: 6, TimeZone: "Asia/Tokyo", LastDeliveredOn: &today}
+	overQuota := &model.DailyStoryPreference{UserID: 4, Enabled: true, Topics: pq.StringArray{"history"}, DeliveryHour: 0, TimeZone: "UTC"}
+
+	t.Run("success: should deliver only due stories and skip users over quota", func(t *testing.T) {
+		mockDailyRepo.On("ListEnabledPreferences").Return([]*model.DailyStoryPreference{due, notYet, alreadyDelivered, overQuota}, nil).Once()
+
+		isToday := mock.MatchedBy(func(d time.Time) bool {
+			return d.Format(time.DateOnly) == "2025-06-02"
+		})
+		// 生成の前に配信日を記録する
+		mockDailyRepo.On("ClaimDelivery", 1, isToday).Return(true, nil).Once()
+		mockUserRepo.On("GetUserByID", 1).Return(&model.User{ID: 1}, nil).Once()
+		mockLLM.On("GenerateStory", "space", GenerationOptions{Level: "B1"}).Return("Space is big.", nil).Once()
+		mockStoryRepo.On("CreateStory", mock.AnythingOfType("*model.Story")).Return(nil).Once()
+		mockStoryRepo.On("ListStoryFingerprints", 1).Return(nil, nil).Once()
+		mockUserRepo.On("UpdateGenerationStatus", 1, 1, mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+		// 生成上限に達していた場合は記録を戻し、次回の実行で再試行する
+		lastGeneration := time.Now() // 生成上限は実行時点の当日分で判定される
+		mockDailyRepo.On("ClaimDelivery", 4, mock.AnythingOfType("time.Time")).Return(true, nil).Once()
+		mockUserRepo.On("GetUserByID", 4).Return(&model.User{ID: 4, GenerationCount: testDailyLimit, LastGenerationAt: &lastGeneration}, nil).Once()
+		mockDailyRepo.On("ReleaseDelivery", 4, mock.AnythingOfType("time.Time"), (*time.Time)(nil)).Return(nil).Once()
+
+		report, err := dailyStoryService.DeliverDueStories(context.Background(), now)
+
+		require.NoError(t, err)
+		assert.Equal(t, &DeliveryReport{Delivered: 1, Skipped: 1}, report)
+
+		mockDailyRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
+		mockLLM.AssertExpectations(t)
+		mockStoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("success: should not generate when another run already claimed the user", func(t *testing.T) {
+		mockDailyRepo.On("ListEnabledPreferences").Return([]*model.DailyStoryPreference{due}, nil).Once()
+		mockDailyRepo.On("ClaimDelivery", 1, mock.AnythingOfType("time.Time")).Return(false, nil).Once()
+
+		report, err := dailyStoryService.DeliverDueStories(context.Background(), now)
+
+		require.NoError(t, err)
+		assert.Equal(t, &DeliveryReport{}, report)
+		mockDailyRepo.AssertExpectations(t)
+	})
+
+	t.Run("success: should release the claim when generation fails", func(t *testing.T) {
+		mockDailyRepo.On("ListEnabledPreferences").Return([]*model.DailyStoryPreference{due}, nil).Once()
+		mockDailyRepo.On("ClaimDelivery", 1, mock.AnythingOfType("time.Time")).Return(true, nil).Once()
+		mockUserRepo.On("GetUserByID", 1).Return(&model.User{ID: 1}, nil).Once()
+		mockLLM.On("GenerateStory", "space", GenerationOptions{Level: "B1"}).Return("", errors.New("timeout")).Once()
+		mockDailyRepo.On("ReleaseDelivery", 1, mock.AnythingOfType("time.Time"), &yesterday).Return(nil).Once()
+
+		report, err := dailyStoryService.DeliverDueStories(context.Background(), now)
+
+		require.NoError(t, err)
+		assert.Equal(t, &DeliveryReport{Failed: 1}, report)
+		mockDailyRepo.AssertExpectations(t)
+	})
+
+	t.Run("success: should defer users when the run is about to time out", func(t *testing.T) {
+		mockDailyRepo.On("ListEnabledPreferences").Return([]*model.DailyStoryPreference{due, overQuota}, nil).Once()
+
+		// Lambda の残り時間が 1 件の生成に足りない
+		ctx, cancel := context.WithTimeout(context.Background(), dailyStoryGenerationBudget-time.Second)
+		defer cancel()
+
+		report, err := dailyStoryService.DeliverDueStories(ctx, now)
+
+		require.NoError(t, err)
+		// ClaimDelivery の呼び出しを設定していないため、生成を始めていればモックが失敗する
+		assert.Equal(t, &DeliveryReport{Deferred: 2}, report)
+	})
+
+	t.Run("success: should defer users over the per-run limit", func(t *testing.T) {
+		preferences := make([]*model.DailyStoryPreference, 0, maxDailyDeliveriesPerRun+1)
+		for i := 0; i <= maxDailyDeliveriesPerRun; i++ {
+			preferences = append(preferences, &model.DailyStoryPreference{UserID: 100 + i, Enabled: true, Topics: pq.StringArray{"sea"}, TimeZone: "UTC"})
+		}
+		mockDailyRepo.On("ListEnabledPreferences").Return(preferences, nil).Once()
+		mockDailyRepo.On("ClaimDelivery", mock.AnythingOfType("int"), mock.AnythingOfType("time.Time")).Return(true, nil).Times(maxDailyDeliveriesPerRun)
+		mockUserRepo.On("GetUserByID", mock.AnythingOfType("int")).Return(&model.User{}, nil).Times(maxDailyDeliveriesPerRun)
+		mockLLM.On("GenerateStory", "sea", GenerationOptions{}).Return("The sea is wide.", nil).Times(maxDailyDeliveriesPerRun)
+		mockStoryRepo.On("CreateStory", mock.AnythingOfType("*model.Story")).Return(nil).Times(maxDailyDeliveriesPerRun)
+		mockStoryRepo.On("ListStoryFingerprints", mock.AnythingOfType("int")).Return(nil, nil).Times(maxDailyDeliveriesPerRun)
+		mockUserRepo.On("UpdateGenerationStatus", mock.AnythingOfType("int"), 1, mock.AnythingOfType("time.Time")).Return(nil).Times(maxDailyDeliveriesPerRun)
+
+		report, err := dailyStoryService.DeliverDueStories(context.Background(), now)
+
+		require.NoError(t, err)
+		assert.Equal(t, &DeliveryReport{Delivered: maxDailyDeliveriesPerRun, Deferred: 1}, report)
+		mockDailyRepo.AssertExpectations(t)
+		mockLLM.AssertExpectations(t)
+	})
+}
+
+func TestDailyStoryService_UpdatePreference(t *testing.T) {
+	mockDailyRepo := new(MockDailyStoryRepository)
+	dailyStoryService := NewDailyStoryService(mockDailyRepo, nil)
+
+	t.Run("success: should trim topics and save", func(t *testing.T) {
+		mockDailyRepo.On("UpsertPreference", mock.MatchedBy(func(p *model.DailyStoryPreference) bool {
+			return len(p.Topics) == 1 && p.Topics[0] == "travel"
+		})).Return(nil).Once()
+
+		preference, err := dailyStoryService.UpdatePreference(testUser.ID, DailyStoryPreferenceInput{
+			Enabled:      true,
+			Topics:       []string{" travel ", " "},
+			DeliveryHour: 7,
+			TimeZone:     "America/New_York",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, testUser.ID, preference.UserID)
+		mockDailyRepo.AssertExpectations(t)
+	})
+
+	t.Run("fail: should reject an unknown time zone", func(t *testing.T) {
+		_, err := dailyStoryService.UpdatePreference(testUser.ID, DailyStoryPreferenceInput{
+			Topics:   []string{"travel"},
+			TimeZone: "Mars/Olympus",
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidTimeZone)
+	})
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+)
+
+// 差分の種類
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// DiffChange は本文の差分の 1 区間。連続する同じ種類の変更はまとめる
+type DiffChange struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// maxDiffEdits は単語単位で差分を計算する編集数の上限。超えた場合は変更区間全体を置き換えとして返す
+const maxDiffEdits = 1000
+
+// diffWords は before から after への差分を単語・空白の単位で返す
+func diffWords(before, after string) []DiffChange {
+	a, b := tokenizeWords(before), tokenizeWords(after)
+
+	// 共通の先頭・末尾を除いて計算量を抑える
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	changes := []DiffChange{}
+	add := func(op, text string) {
+		if text == "" {
+			return
+		}
+		if n := len(changes); n > 0 && changes[n-1].Op == op {
+			changes[n-1].Text += text
+			return
+		}
+		changes = append(changes, DiffChange{Op: op, Text: text})
+	}
+
+	add(DiffEqual, strings.Join(a[:prefix], ""))
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if edits, ok := myersDiff(midA, midB); ok {
+		for _, e := range edits {
+			add(e.Op, e.Text)
+		}
+	} else {
+		add(DiffDelete, strings.Join(midA, ""))
+		add(DiffInsert, strings.Join(midB, ""))
+	}
+	add(DiffEqual, strings.Join(a[len(a)-suffix:], ""))
+	return changes
+}
+
+// tokenizeWords は文字列を空白の連続とそれ以外の連続に分割する。連結すると元の文字列に戻る
+func tokenizeWords(s string) []string {
+	var tokens []string
+	start := 0
+	for i, r := range s {
+		if i > start && unicode.IsSpace(r) != isSpaceAt(s, start) {
+			tokens = append(tokens, s[start:i])
+			start = i
+		}
+	}
+	if start < len(s) {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+func isSpaceAt(s string, i int) bool {
+	for _, r := range s[i:] {
+		return unicode.IsSpace(r)
+	}
+	return false
+}
+
+// myersDiff は Myers のアルゴリズムで最短の編集列を求める。編集数が maxDiffEdits を超える場合は false を返す
+func myersDiff(a, b []string) ([]DiffChange, bool) {
+	n, m := len(a), len(b)
+	limit := min(n+m, maxDiffEdits)
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+
+	// trace[d] は d 回目の探索を始める前の各対角線の到達点 (対角線 -d..d)
+	var trace [][]int
+	found := false
+	for d := 0; d <= limit && !found; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		return nil, false
+	}
+
+	// 終点から逆にたどって編集列を組み立てる
+	var reversed []DiffChange
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		prev := trace[d]
+		at := func(k int) int { return prev[k+d] }
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := 0
+		if d > 0 {
+			prevX = at(prevK)
+		}
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			reversed = append(reversed, DiffChange{Op: DiffEqual, Text: a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, DiffChange{Op: DiffInsert, Text: b[y-1]})
+			} else {
+				reversed = append(reversed, DiffChange{Op: DiffDelete, Text: a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	edits := make([]DiffChange, len(reversed))
+	for i, e := range reversed {
+		edits[len(reversed)-1-i] = e
+	}
+	return edits, true
+}
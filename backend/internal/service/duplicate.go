@@ -0,0 +1,120 @@
+package service
+
+import (
+	"cmp"
+	"log"
+	"slices"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
+	"github.com/shuheikomatsuki/readoku/backend/internal/simhash"
+)
+
+const (
+	// maxNearDuplicateDistance は本文がほぼ同じとみなす SimHash の異なるビット数の上限 (一致度 0.84 以上)。
+	// 同じ話題で書き直した別の文章は 18 ビット前後離れるため、数語の言い換え程度までを同じとみなす
+	maxNearDuplicateDistance = 10
+	// maxSimilarStories は生成・取り込みの結果に含める、本文がほぼ同じストーリーの最大数
+	maxSimilarStories = 5
+	// simhashBackfillBatchSize は定期ジョブの 1 回で SimHash を計算するストーリーの数
+	simhashBackfillBatchSize = 1000
+)
+
+// attachSimilarStories は保存したストーリーごとに、本文がほぼ同じユーザーの他のストーリーを一致度の高い順に SimilarStories に設定する。
+// 警告のための情報のため、取得に失敗しても保存済みのストーリーを返せるようログだけ残す
+func attachSimilarStories(storyRepo repository.IStoryRepository, userID int, stories ...*model.Story) {
+	if len(stories) == 0 {
+		return
+	}
+	fingerprints, err := storyRepo.ListStoryFingerprints(userID)
+	if err != nil {
+		log.Printf("WARNING: failed to find similar stories for user %d: %v", userID, err)
+		return
+	}
+
+	for _, story := range stories {
+		fingerprint := simhash.Fingerprint(story.Content)
+		var similar []*model.SimilarStory
+		for _, other := range fingerprints {
+			if other.ID == story.ID {
+				continue
+			}
+			if simhash.Distance(fingerprint, uint64(other.Simhash)) <= maxNearDuplicateDistance {
+				similar = append(similar, &model.SimilarStory{
+					StoryID:    other.ID,
+					Title:      other.Title,
+					Similarity: simhash.Similarity(fingerprint, uint64(other.Simhash)),
+				})
+			}
+		}
+		slices.SortStableFunc(similar, func(a, b *model.SimilarStory) int {
+			return cmp.Compare(b.Similarity, a.Similarity)
+		})
+		if len(similar) > maxSimilarStories {
+			similar = similar[:maxSimilarStories]
+		}
+		story.SimilarStories = similar
+	}
+}
+
+// clusterDuplicates は本文がほぼ同じストーリーをまとめ、2 件以上のまとまりを返す。
+// ほぼ同じ組を辿ってつながるものは同じまとまりにする。まとまりは件数の多い順、同数なら最も古いストーリーの順に並べる
+func clusterDuplicates(fingerprints []*model.StoryFingerprint) []*model.DuplicateCluster {
+	parent := make([]int, len(fingerprints))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range fingerprints {
+		for j := i + 1; j < len(fingerprints); j++ {
+			if simhash.Distance(uint64(fingerprints[i].Simhash), uint64(fingerprints[j].Simhash)) <= maxNearDuplicateDistance {
+				// 古い方 (添字の小さい方) を代表にして、まとまりの順序を入力順に揃える
+				ri, rj := find(i), find(j)
+				if ri < rj {
+					parent[rj] = ri
+				} else if rj < ri {
+					parent[ri] = rj
+				}
+			}
+		}
+	}
+
+	groups := make(map[int]*model.DuplicateCluster)
+	var roots []int
+	for i, fingerprint := range fingerprints {
+		root := find(i)
+		cluster, ok := groups[root]
+		if !ok {
+			cluster = &model.DuplicateCluster{}
+			groups[root] = cluster
+			roots = append(roots, root)
+		}
+		cluster.Stories = append(cluster.Stories, fingerprint)
+	}
+
+	clusters := []*model.DuplicateCluster{}
+	for _, root := range roots {
+		cluster := groups[root]
+		if len(cluster.Stories) < 2 {
+			continue
+		}
+		cluster.MinSimilarity = 1
+		for i, a := range cluster.Stories {
+			for _, b := range cluster.Stories[i+1:] {
+				cluster.MinSimilarity = min(cluster.MinSimilarity, simhash.Similarity(uint64(a.Simhash), uint64(b.Simhash)))
+			}
+		}
+		clusters = append(clusters, cluster)
+	}
+	slices.SortStableFunc(clusters, func(a, b *model.DuplicateCluster) int {
+		return cmp.Compare(len(b.Stories), len(a.Stories))
+	})
+	return clusters
+}
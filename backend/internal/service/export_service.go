@@ -0,0 +1,300 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"database/sql"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/archive"
+	"github.com/shuheikomatsuki/readoku/backend/internal/epub"
+	"github.com/shuheikomatsuki/readoku/backend/internal/markdown"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
+)
+
+var (
+	ErrInvalidExportSelection = errors.New("specify exactly one of ids, tag_id or shelf_id")
+	ErrNothingToExport        = errors.New("no stories to export")
+	ErrExportTooLarge         = errors.New("too many stories to export")
+	ErrArchiveTooLarge        = errors.New("library is too large to export as one archive")
+)
+
+const (
+	// MaxEPUBExportStories は 1 冊の EPUB に含めるストーリーの上限
+	MaxEPUBExportStories = 500
+	// archiveBatchSize はアーカイブの書き出しで 1 回に読み込むストーリーの数。ライブラリ全体をメモリに載せないよう少しずつ読む
+	archiveBatchSize = 100
+	// LambdaMaxArchiveBytes は Lambda で返せるアーカイブの大きさの上限。Lambda の応答は 6MB までで、
+	// バイナリは base64 (4/3 倍) で送るため、ヘッダーなどの余裕を残して 4MB にする
+	LambdaMaxArchiveBytes = 4 * 1024 * 1024
+)
+
+// epubAuthor は書き出す EPUB の著者名。ユーザーの情報は含めない
+const epubAuthor = "Readoku"
+
+// EPUBExport は書き出す EPUB と、ダウンロード時のファイル名
+type EPUBExport struct {
+	FileName    string
+	Publication *epub.Publication
+}
+
+type IExportService interface {
+	ExportEPUB(userID int, selection repository.StorySelection, title string) (*EPUBExport, error)
+	ExportArchive(userID int, w io.Writer) error
+}
+
+type ExportService struct {
+	StoryRepo repository.IStoryRepository
+	TagRepo   repository.ITagRepository
+	ShelfRepo repository.IShelfRepository
+	// MaxArchiveBytes はアーカイブの大きさの上限。0 の場合は上限を設けず、書き出しながら送る
+	MaxArchiveBytes int64
+}
+
+func NewExportService(storyRepo repository.IStoryRepository, tagRepo repository.ITagRepository, shelfRepo repository.IShelfRepository, maxArchiveBytes int64) IExportService {
+	return &ExportService{
+		StoryRepo:       storyRepo,
+		TagRepo:         tagRepo,
+		ShelfRepo:       shelfRepo,
+		MaxArchiveBytes: maxArchiveBytes,
+	}
+}
+
+// ExportEPUB は選んだストーリーを 1 章ずつ EPUB にまとめる。title を省略した場合はタグ名・棚の名前、
+// ID で選んだ場合は 1 件ならそのタイトル、複数なら件数入りの既定のタイトルにする
+func (s *ExportService) ExportEPUB(userID int, selection repository.StorySelection, title string) (*EPUBExport, error) {
+	defaultTitle, err := s.selectionTitle(userID, selection)
+	if err != nil {
+		return nil, err
+	}
+	if len(selection.StoryIDs) > MaxEPUBExportStories {
+		return nil, ErrExportTooLarge
+	}
+
+	stories, err := s.StoryRepo.GetStoriesBySelection(userID, selection)
+	if err != nil {
+		return nil, fmt.Errorf("database error (get stories for export): %w", err)
+	}
+	if selection.StoryIDs != nil && len(stories) != countUnique(selection.StoryIDs) {
+		return nil, ErrStoryNotFound
+	}
+	if len(stories) == 0 {
+		return nil, ErrNothingToExport
+	}
+	if len(stories) > MaxEPUBExportStories {
+		return nil, ErrExportTooLarge
+	}
+
+	if title = strings.TrimSpace(title); title == "" {
+		title = defaultTitle
+	}
+	if title == "" && len(stories) == 1 {
+		title = stories[0].Title
+	}
+	if title == "" {
+		title = fmt.Sprintf("Readoku Stories (%d)", len(stories))
+	}
+
+	pub := &epub.Publication{
+		Identifier: exportIdentifier(userID, stories),
+		Title:      title,
+		Author:     epubAuthor,
+		Language:   "en",
+		Modified:   latestUpdate(stories),
+	}
+	for _, story := range stories {
+		pub.Sections = append(pub.Sections, epub.Section{
+			Title: story.Title,
+			Body:  storyMetaXHTML(story) + markdown.ToXHTML(story.Content),
+		})
+	}
+
+	return &EPUBExport{FileName: exportFileName(title) + ".epub", Publication: pub}, nil
+}
+
+// ExportArchive はユーザーのすべてのストーリーを、タグと読了記録とともにアーカイブ (zip) として w に書き出す。
+// ストーリーは少しずつ読み込んでそのまま書き込むため、ライブラリが大きくてもメモリには一部しか載らない。
+// MaxArchiveBytes が設定されている場合は全体を作ってから書き出し、上限を超える場合は w に何も書かずに ErrArchiveTooLarge を返す
+func (s *ExportService) ExportArchive(userID int, w io.Writer) error {
+	if s.MaxArchiveBytes <= 0 {
+		return s.writeArchive(userID, w)
+	}
+	buf := &limitedBuffer{limit: s.MaxArchiveBytes}
+	if err := s.writeArchive(userID, buf); err != nil {
+		return err
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// limitedBuffer は limit バイトを超える書き込みを ErrArchiveTooLarge で失敗させるバッファ
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int64
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if int64(b.Len()+len(p)) > b.limit {
+		return 0, ErrArchiveTooLarge
+	}
+	return b.Buffer.Write(p)
+}
+
+func (s *ExportService) writeArchive(userID int, w io.Writer) error {
+	tags, err := s.TagRepo.ListTags(userID)
+	if err != nil {
+		return fmt.Errorf("database error (list tags): %w", err)
+	}
+	tagNames := make([]string, len(tags))
+	for i, tag := range tags {
+		tagNames[i] = tag.Name
+	}
+
+	aw := archive.NewWriter(w, time.Now(), tagNames)
+	afterID := 0
+	for {
+		stories, err := s.StoryRepo.GetArchiveStories(userID, afterID, archiveBatchSize)
+		if err != nil {
+			return fmt.Errorf("database error (get archive stories): %w", err)
+		}
+		for _, story := range stories {
+			if err := aw.AddStory(toArchiveStory(story)); err != nil {
+				return err
+			}
+		}
+		if len(stories) < archiveBatchSize {
+			break
+		}
+		afterID = stories[len(stories)-1].ID
+	}
+	return aw.Close()
+}
+
+func toArchiveStory(story *model.ArchiveStory) *archive.Story {
+	records := make([]*archive.ReadingRecord, len(story.ReadingRecords))
+	for i, record := range story.ReadingRecords {
+		records[i] = &archive.ReadingRecord{ReadAt: record.ReadAt, WordCount: record.WordCount}
+	}
+	return &archive.Story{
+		ID:             story.ID,
+		Title:          story.Title,
+		Content:        story.Content,
+		WordCount:      story.WordCount,
+		Level:          story.Level,
+		Tags:           story.Tags,
+		Source:         story.Source,
+		SourceURL:      story.SourceURL,
+		IsFavorite:     story.IsFavorite,
+		CreatedAt:      story.CreatedAt,
+		ReadingRecords: records,
+	}
+}
+
+// selectionTitle は選び方が 1 つだけ指定されていることと、タグ・本棚の所有者を確認し、既定のタイトルを返す
+func (s *ExportService) selectionTitle(userID int, selection repository.StorySelection) (string, error) {
+	specified := 0
+	if len(selection.StoryIDs) > 0 {
+		specified++
+	}
+	if selection.TagID != nil {
+		specified++
+	}
+	if selection.ShelfID != nil {
+		specified++
+	}
+	if specified != 1 {
+		return "", ErrInvalidExportSelection
+	}
+
+	switch {
+	case selection.TagID != nil:
+		tag, err := s.TagRepo.GetUserTag(*selection.TagID, userID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return "", ErrTagNotFound
+			}
+			return "", fmt.Errorf("database error (get tag): %w", err)
+		}
+		return tag.Name, nil
+	case selection.ShelfID != nil:
+		shelf, err := s.ShelfRepo.GetUserShelf(*selection.ShelfID, userID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return "", ErrShelfNotFound
+			}
+			return "", fmt.Errorf("database error (get shelf): %w", err)
+		}
+		return shelf.Name, nil
+	}
+	return "", nil
+}
+
+// storyMetaXHTML は章の冒頭に表示するレベルと語数
+func storyMetaXHTML(story *model.Story) string {
+	meta := fmt.Sprintf("%d words", story.WordCount)
+	if story.Level != nil && *story.Level != "" {
+		meta = *story.Level + " · " + meta
+	}
+	return fmt.Sprintf("<p class=\"meta\">%s</p>\n", html.EscapeString(meta))
+}
+
+// exportIdentifier は含めるストーリーから決まる UUID (v5 形式) を返す。
+// 同じ組み合わせを書き出し直した場合、リーダー側では同じ本として扱われる
+func exportIdentifier(userID int, stories []*model.Story) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "readoku:%d", userID)
+	for _, story := range stories {
+		fmt.Fprintf(h, ":%d", story.ID)
+	}
+	sum := h.Sum(nil)
+	sum[6] = sum[6]&0x0f | 0x50
+	sum[8] = sum[8]&0x3f | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+func latestUpdate(stories []*model.Story) time.Time {
+	var latest time.Time
+	for _, story := range stories {
+		if story.UpdatedAt.After(latest) {
+			latest = story.UpdatedAt
+		}
+	}
+	if latest.IsZero() {
+		return time.Now()
+	}
+	return latest
+}
+
+// exportFileName はタイトルをダウンロード用のファイル名にする。英数字以外は "-" にまとめる
+func exportFileName(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	name := strings.TrimSuffix(b.String(), "-")
+	if name == "" {
+		return "readoku-stories"
+	}
+	return strings.TrimSuffix(truncateRunes(name, 80), "-")
+}
+
+func countUnique(ids []int) int {
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		seen[id] = true
+	}
+	return len(seen)
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/archive"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupExportServiceTest(t *testing.T) (*MockStoryRepository, *MockTagRepository, *MockShelfRepository, IExportService) {
+	mockStoryRepo := new(MockStoryRepository)
+	mockTagRepo := new(MockTagRepository)
+	mockShelfRepo := new(MockShelfRepository)
+
+	exportService := NewExportService(mockStoryRepo, mockTagRepo, mockShelfRepo, 0)
+
+	return mockStoryRepo, mockTagRepo, mockShelfRepo, exportService
+}
+
+func TestExportService_ExportEPUB(t *testing.T) {
+	mockStoryRepo, mockTagRepo, mockShelfRepo, exportService := setupExportServiceTest(t)
+	level := "B1"
+	first := &model.Story{ID: 1, UserID: testUser.ID, Title: "The Lost Key", Content: "Tom looked *everywhere*.", WordCount: 3, Level: &level}
+	second := &model.Story{ID: 2, UserID: testUser.ID, Title: "Rivers", Content: "The river rose.", WordCount: 3}
+
+	t.Run("success: should build one chapter per story in the given order", func(t *testing.T) {
+		selection := repository.StorySelection{StoryIDs: []int{2, 1}}
+		mockStoryRepo.On("GetStoriesBySelection", testUser.ID, selection).Return([]*model.Story{second, first}, nil).Once()
+
+		export, err := exportService.ExportEPUB(testUser.ID, selection, "")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Readoku Stories (2)", export.Publication.Title)
+		assert.Equal(t, "readoku-stories-2.epub", export.FileName)
+		assert.True(t, strings.HasPrefix(export.Publication.Identifier, "urn:uuid:"))
+		require.Len(t, export.Publication.Sections, 2)
+		assert.Equal(t, "Rivers", export.Publication.Sections[0].Title)
+		assert.Contains(t, export.Publication.Sections[1].Body, "<p class=\"meta\">B1 · 3 words</p>")
+		assert.Contains(t, export.Publication.Sections[1].Body, "<p>Tom looked <em>everywhere</em>.</p>")
+		mockStoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("success: should use the tag name as the book title", func(t *testing.T) {
+		tagID := 5
+		selection := repository.StorySelection{TagID: &tagID}
+		mockTagRepo.On("GetUserTag", tagID, testUser.ID).Return(&model.Tag{ID: tagID, Name: "Week 1"}, nil).Once()
+		mockStoryRepo.On("GetStoriesBySelection", testUser.ID, selection).Return([]*model.Story{first}, nil).Once()
+
+		export, err := exportService.ExportEPUB(testUser.ID, selection, "")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Week 1", export.Publication.Title)
+		assert.Equal(t, "week-1.epub", export.FileName)
+	})
+
+	t.Run("fail: should return ErrShelfNotFound for another user's shelf", func(t *testing.T) {
+		shelfID := 9
+		mockShelfRepo.On("GetUserShelf", shelfID, testUser.ID).Return(nil, sql.ErrNoRows).Once()
+
+		_, err := exportService.ExportEPUB(testUser.ID, repository.StorySelection{ShelfID: &shelfID}, "")
+
+		assert.ErrorIs(t, err, ErrShelfNotFound)
+	})
+
+	t.Run("fail: should return ErrStoryNotFound when some ids are not the user's", func(t *testing.T) {
+		selection := repository.StorySelection{StoryIDs: []int{1, 99}}
+		mockStoryRepo.On("GetStoriesBySelection", testUser.ID, selection).Return([]*model.Story{first}, nil).Once()
+
+		_, err := exportService.ExportEPUB(testUser.ID, selection, "")
+
+		assert.ErrorIs(t, err, ErrStoryNotFound)
+	})
+
+	t.Run("fail: should require exactly one selection", func(t *testing.T) {
+		tagID := 5
+		_, err := exportService.ExportEPUB(testUser.ID, repository.StorySelection{StoryIDs: []int{1}, TagID: &tagID}, "")
+		assert.ErrorIs(t, err, ErrInvalidExportSelection)
+
+		_, err = exportService.ExportEPUB(testUser.ID, repository.StorySelection{}, "")
+		assert.ErrorIs(t, err, ErrInvalidExportSelection)
+	})
+}
+
+func TestExportService_ExportArchive(t *testing.T) {
+	mockStoryRepo, mockTagRepo, _, exportService := setupExportServiceTest(t)
+
+	t.Run("success: should page through all stories and write a manifest", func(t *testing.T) {
+		level := "A2"
+		firstBatch := make([]*model.ArchiveStory, archiveBatchSize)
+		for i := range firstBatch {
+			firstBatch[i] = &model.ArchiveStory{Story: model.Story{ID: i + 1, Title: fmt.Sprintf("Story %d", i+1), Content: "Text.", WordCount: 1}}
+		}
+		last := &model.ArchiveStory{
+			Story:          model.Story{ID: 500, Title: "Last", Content: "The end.", WordCount: 2, Level: &level},
+			Tags:           []string{"week 1"},
+			ReadingRecords: []*model.ReadingRecord{{StoryID: 500, WordCount: 2, ReadAt: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)}},
+		}
+		mockTagRepo.On("ListTags", testUser.ID).Return([]*model.Tag{{Name: "week 1"}, {Name: "unused"}}, nil).Once()
+		mockStoryRepo.On("GetArchiveStories", testUser.ID, 0, archiveBatchSize).Return(firstBatch, nil).Once()
+		mockStoryRepo.On("GetArchiveStories", testUser.ID, archiveBatchSize, archiveBatchSize).Return([]*model.ArchiveStory{last}, nil).Once()
+
+		var buf bytes.Buffer
+		err := exportService.ExportArchive(testUser.ID, &buf)
+
+		require.NoError(t, err)
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+		require.Len(t, zr.File, archiveBatchSize+2)
+		assert.Equal(t, "stories/00500-last.md", zr.File[archiveBatchSize].Name)
+
+		rc, err := zr.File[archiveBatchSize+1].Open()
+		require.NoError(t, err)
+		defer rc.Close()
+		var manifest archive.Manifest
+		require.NoError(t, json.NewDecoder(rc).Decode(&manifest))
+		assert.Equal(t, []string{"week 1", "unused"}, manifest.Tags)
+		require.Len(t, manifest.Stories, archiveBatchSize+1)
+		assert.Equal(t, []string{"week 1"}, manifest.Stories[archiveBatchSize].Tags)
+		assert.Len(t, manifest.Stories[archiveBatchSize].ReadingRecords, 1)
+		mockStoryRepo.AssertExpectations(t)
+		mockTagRepo.AssertExpectations(t)
+	})
+
+	t.Run("fail: should write nothing when the archive is over the size limit", func(t *testing.T) {
+		limited := NewExportService(mockStoryRepo, mockTagRepo, nil, 1024)
+		// 圧縮しても上限を超えるよう、繰り返しの少ない本文にする
+		words := make([]string, 2000)
+		for i := range words {
+			words[i] = fmt.Sprint(i * 7919)
+		}
+		large := &model.ArchiveStory{Story: model.Story{ID: 1, Title: "Large", Content: strings.Join(words, " "), WordCount: len(words)}}
+		mockTagRepo.On("ListTags", testUser.ID).Return([]*model.Tag{}, nil).Once()
+		mockStoryRepo.On("GetArchiveStories", testUser.ID, 0, archiveBatchSize).Return([]*model.ArchiveStory{large}, nil).Once()
+
+		var buf bytes.Buffer
+		err := limited.ExportArchive(testUser.ID, &buf)
+
+		assert.ErrorIs(t, err, ErrArchiveTooLarge)
+		assert.Zero(t, buf.Len())
+	})
+
+	t.Run("success: should write the whole archive when it fits the size limit", func(t *testing.T) {
+		limited := NewExportService(mockStoryRepo, mockTagRepo, nil, 1024*1024)
+		small := &model.ArchiveStory{Story: model.Story{ID: 1, Title: "Small", Content: "Text.", WordCount: 1}}
+		mockTagRepo.On("ListTags", testUser.ID).Return([]*model.Tag{}, nil).Once()
+		mockStoryRepo.On("GetArchiveStories", testUser.ID, 0, archiveBatchSize).Return([]*model.ArchiveStory{small}, nil).Once()
+
+		var buf bytes.Buffer
+		require.NoError(t, limited.ExportArchive(testUser.ID, &buf))
+
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+		assert.Len(t, zr.File, 2)
+	})
+
+	t.Run("fail: should write nothing when the stories cannot be loaded", func(t *testing.T) {
+		mockTagRepo.On("ListTags", testUser.ID).Return([]*model.Tag{}, nil).Once()
+		mockStoryRepo.On("GetArchiveStories", testUser.ID, 0, archiveBatchSize).Return(nil, errors.New("db error")).Once()
+
+		var buf bytes.Buffer
+		err := exportService.ExportArchive(testUser.ID, &buf)
+
+		assert.Error(t, err)
+		assert.Zero(t, buf.Len())
+	})
+}
@@ -0,0 +1,407 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/archive"
+	"github.com/shuheikomatsuki/readoku/backend/internal/epub"
+	"github.com/shuheikomatsuki/readoku/backend/internal/markdown"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
+	"github.com/shuheikomatsuki/readoku/backend/internal/webpage"
+)
+
+var (
+	ErrEmptyImport       = errors.New("imported text is empty")
+	ErrInvalidEPUB       = errors.New("invalid epub")
+	ErrImportTooLarge    = errors.New("imported text is too large")
+	ErrUnsupportedImport = errors.New("imported text must be UTF-8 plain text or Markdown")
+	ErrInvalidImportURL  = errors.New("import url is invalid or not allowed")
+	ErrImportFetchFailed = errors.New("failed to fetch the page to import")
+	ErrInvalidArchive    = errors.New("invalid library archive")
+)
+
+const (
+	// MaxImportContentBytes は取り込む 1 件あたりの本文の最大サイズ
+	MaxImportContentBytes = 512 * 1024
+	// maxImportTitleLength はタイトルの最大文字数 (PATCH /stories/:id の上限に合わせる)
+	maxImportTitleLength = 100
+	// MaxEPUBBytes は取り込む EPUB ファイルの最大サイズ
+	MaxEPUBBytes = 20 * 1024 * 1024
+	// maxEPUBChapters は EPUB から作るストーリー (章) の最大数
+	maxEPUBChapters = 300
+	// MaxArchiveBytes は取り込むライブラリのアーカイブ (zip) の最大サイズ
+	MaxArchiveBytes = 50 * 1024 * 1024
+	// maxArchiveStories はアーカイブから取り込むストーリーの最大数
+	maxArchiveStories = 5000
+	// maxArchiveTagLength はタグ名の最大文字数 (POST /tags の上限に合わせる)
+	maxArchiveTagLength = 50
+)
+
+// ImportInput は取り込む 1 件分のテキスト。Title が空の場合は本文の先頭の見出し、ファイル名、本文の 1 行目の順に決める
+type ImportInput struct {
+	Title    string
+	FileName string
+	Content  string
+}
+
+// EPUBImport は EPUB から作った連載と、章ごとのストーリー
+type EPUBImport struct {
+	Series  *model.Series
+	Stories []*model.Story
+}
+
+type IImportService interface {
+	ImportStories(userID int, inputs []ImportInput) ([]*model.Story, error)
+	ImportEPUB(userID int, fileName string, r io.ReaderAt, size int64) (*EPUBImport, error)
+	ImportURL(userID int, rawURL string) (*model.Story, error)
+	ImportArchive(userID int, r io.ReaderAt, size int64) (*model.ArchiveImport, error)
+}
+
+type ImportService struct {
+	StoryRepo  repository.IStoryRepository
+	SeriesRepo repository.ISeriesRepository
+	Fetcher    *webpage.Fetcher
+}
+
+func NewImportService(storyRepo repository.IStoryRepository, seriesRepo repository.ISeriesRepository, fetcher *webpage.Fetcher) IImportService {
+	return &ImportService{
+		StoryRepo:  storyRepo,
+		SeriesRepo: seriesRepo,
+		Fetcher:    fetcher,
+	}
+}
+
+// ImportStories はユーザーが用意したテキストをストーリーとして保存する。生成回数の制限には数えない
+func (s *ImportService) ImportStories(userID int, inputs []ImportInput) ([]*model.Story, error) {
+	// 途中で失敗して一部だけ保存されないよう、すべて検証してから保存する
+	stories := make([]*model.Story, 0, len(inputs))
+	for _, input := range inputs {
+		story, err := buildImportedStory(userID, input)
+		if err != nil {
+			return nil, err
+		}
+		stories = append(stories, story)
+	}
+
+	if err := s.StoryRepo.CreateStories(stories); err != nil {
+		return nil, fmt.Errorf("failed to save imported stories: %w", err)
+	}
+	attachSimilarStories(s.StoryRepo, userID, stories...)
+	return stories, nil
+}
+
+// ImportEPUB は EPUB の spine の各章を Markdown に変換し、本のタイトルの連載として章の順に保存する。
+// 画像やスクリプトは取り除き、本文のない章 (表紙など) は飛ばす
+func (s *ImportService) ImportEPUB(userID int, fileName string, r io.ReaderAt, size int64) (*EPUBImport, error) {
+	if size > MaxEPUBBytes {
+		return nil, ErrImportTooLarge
+	}
+	book, err := epub.Read(r, size)
+	if err != nil {
+		if errors.Is(err, epub.ErrTooLarge) {
+			return nil, ErrImportTooLarge
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEPUB, err)
+	}
+
+	bookTitle := book.Title
+	if bookTitle == "" {
+		bookTitle = titleFromFileName(fileName)
+	}
+	bookTitle = truncateRunes(strings.TrimSpace(bookTitle), maxImportTitleLength)
+
+	var stories []*model.Story
+	for _, chapter := range book.Chapters {
+		content, err := markdown.FromHTML(bytes.NewReader(chapter.Body))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidEPUB, err)
+		}
+		title, content := extractMarkdownTitle(content)
+		if title == "" {
+			title = fmt.Sprintf("%s (%d)", bookTitle, len(stories)+1)
+		}
+		story, err := buildImportedStory(userID, ImportInput{Title: title, Content: content})
+		if errors.Is(err, ErrEmptyImport) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		stories = append(stories, story)
+	}
+	if len(stories) == 0 {
+		return nil, ErrEmptyImport
+	}
+	if len(stories) > maxEPUBChapters {
+		return nil, ErrImportTooLarge
+	}
+
+	series := &model.Series{UserID: userID, Title: bookTitle}
+	if err := s.SeriesRepo.CreateSeriesWithChapters(series, stories); err != nil {
+		return nil, fmt.Errorf("failed to save imported epub: %w", err)
+	}
+	attachSimilarStories(s.StoryRepo, userID, stories...)
+	return &EPUBImport{Series: series, Stories: stories}, nil
+}
+
+// ImportURL は Web ページを取得して記事の本文を抜き出し、正規の URL を付けてストーリーとして保存する
+func (s *ImportService) ImportURL(userID int, rawURL string) (*model.Story, error) {
+	page, err := s.Fetcher.Fetch(rawURL)
+	if err != nil {
+		switch {
+		case errors.Is(err, webpage.ErrInvalidURL), errors.Is(err, webpage.ErrForbiddenAddress):
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImportURL, err)
+		case errors.Is(err, webpage.ErrTooLarge):
+			return nil, ErrImportTooLarge
+		case errors.Is(err, webpage.ErrUnsupportedContent):
+			return nil, ErrUnsupportedImport
+		}
+		return nil, fmt.Errorf("%w: %v", ErrImportFetchFailed, err)
+	}
+
+	article, err := webpage.Extract(page)
+	if err != nil {
+		if errors.Is(err, webpage.ErrNoContent) {
+			return nil, ErrEmptyImport
+		}
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedImport, err)
+	}
+
+	// 本文の先頭がページのタイトルと同じ見出しの場合は、本文から除く
+	title, content := article.Title, article.Content
+	if heading, rest := extractMarkdownTitle(content); heading != "" && (title == "" || strings.EqualFold(heading, title)) {
+		title, content = heading, rest
+	}
+
+	story, err := buildImportedStory(userID, ImportInput{Title: title, Content: content})
+	if err != nil {
+		return nil, err
+	}
+	story.SourceURL = &article.CanonicalURL
+
+	if err := s.StoryRepo.CreateStory(story); err != nil {
+		return nil, fmt.Errorf("failed to save imported story: %w", err)
+	}
+	attachSimilarStories(s.StoryRepo, userID, story)
+	return story, nil
+}
+
+// ImportArchive はライブラリのアーカイブ (GET /stories/export.zip の形式) からストーリー・タグ・読了記録を復元する。
+// 本文が同じストーリーが既にある場合は作成しないため、同じアーカイブを何度取り込んでも結果は変わらない。生成回数の制限には数えない
+func (s *ImportService) ImportArchive(userID int, r io.ReaderAt, size int64) (*model.ArchiveImport, error) {
+	if size > MaxArchiveBytes {
+		return nil, ErrImportTooLarge
+	}
+	lib, err := archive.Read(r, size)
+	if err != nil {
+		if errors.Is(err, archive.ErrTooLarge) {
+			return nil, ErrImportTooLarge
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	if len(lib.Stories) > maxArchiveStories {
+		return nil, ErrImportTooLarge
+	}
+
+	// 途中で失敗して一部だけ保存されないよう、すべて検証してから保存する
+	var tags []string
+	for _, name := range lib.Tags {
+		if tags, err = appendArchiveTag(tags, name); err != nil {
+			return nil, err
+		}
+	}
+	stories := make([]*model.ArchiveStory, 0, len(lib.Stories))
+	for _, entry := range lib.Stories {
+		story, err := buildArchiveStory(userID, entry)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range story.Tags {
+			if tags, err = appendArchiveTag(tags, name); err != nil {
+				return nil, err
+			}
+		}
+		stories = append(stories, story)
+	}
+
+	result, err := s.StoryRepo.ImportArchiveStories(userID, tags, stories)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save imported archive: %w", err)
+	}
+	// 本文が完全に同じものは重複として作らないため、作成したストーリーだけをほぼ同じ本文の警告の対象にする
+	var createdItems []*model.ArchiveImportResult
+	var created []*model.Story
+	for i, item := range result.Stories {
+		item.File = lib.Stories[i].File
+		if item.Status == model.ArchiveImportCreated {
+			createdItems = append(createdItems, item)
+			created = append(created, &model.Story{ID: item.StoryID, Content: stories[i].Content})
+		}
+	}
+	attachSimilarStories(s.StoryRepo, userID, created...)
+	for i, item := range createdItems {
+		item.SimilarStories = created[i].SimilarStories
+	}
+	return result, nil
+}
+
+// buildArchiveStory はアーカイブのストーリーを検証して保存する形にする。語数は本文から数え直す
+func buildArchiveStory(userID int, entry *archive.ArchivedStory) (*model.ArchiveStory, error) {
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w: %s: %s", ErrInvalidArchive, entry.File, reason)
+	}
+
+	if len(entry.Content) > MaxImportContentBytes {
+		return nil, ErrImportTooLarge
+	}
+	if !utf8.ValidString(entry.Content) || strings.ContainsRune(entry.Content, 0) {
+		return nil, invalid("content must be UTF-8 text")
+	}
+	if strings.TrimSpace(entry.Content) == "" {
+		return nil, invalid("content is empty")
+	}
+
+	title := strings.TrimSpace(entry.Title)
+	if title == "" {
+		title = titleFromFileName(entry.File)
+	}
+
+	var level *string
+	if entry.Level != nil && *entry.Level != "" {
+		if !slices.Contains(model.CEFRLevels, *entry.Level) {
+			return nil, invalid(fmt.Sprintf("unknown level %q", *entry.Level))
+		}
+		level = entry.Level
+	}
+
+	source := entry.Source
+	switch source {
+	case "":
+		source = model.StorySourceImported
+	case model.StorySourceGenerated, model.StorySourceImported, model.StorySourceCommunity:
+	default:
+		return nil, invalid(fmt.Sprintf("unknown source %q", source))
+	}
+
+	var sourceURL *string
+	if entry.SourceURL != nil && *entry.SourceURL != "" {
+		sourceURL = entry.SourceURL
+	}
+
+	createdAt := entry.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	tags := make([]string, 0, len(entry.Tags))
+	for _, name := range entry.Tags {
+		tags = append(tags, strings.TrimSpace(name))
+	}
+
+	records := make([]*model.ReadingRecord, 0, len(entry.ReadingRecords))
+	for _, record := range entry.ReadingRecords {
+		if record == nil || record.ReadAt.IsZero() || record.WordCount < 0 {
+			return nil, invalid("invalid reading record")
+		}
+		records = append(records, &model.ReadingRecord{UserID: userID, ReadAt: record.ReadAt, WordCount: record.WordCount})
+	}
+
+	return &model.ArchiveStory{
+		Story: model.Story{
+			UserID:     userID,
+			Title:      truncateRunes(title, maxImportTitleLength),
+			Content:    entry.Content,
+			WordCount:  countWords(entry.Content),
+			Level:      level,
+			IsFavorite: entry.IsFavorite,
+			Source:     source,
+			SourceURL:  sourceURL,
+			CreatedAt:  createdAt,
+		},
+		Tags:           tags,
+		ReadingRecords: records,
+	}, nil
+}
+
+// appendArchiveTag はタグ名を検証し、まだ含まれていなければ tags に追加する
+func appendArchiveTag(tags []string, name string) ([]string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxArchiveTagLength {
+		return nil, fmt.Errorf("%w: tag names must be 1 to %d characters", ErrInvalidArchive, maxArchiveTagLength)
+	}
+	if slices.Contains(tags, name) {
+		return tags, nil
+	}
+	return append(tags, name), nil
+}
+
+func buildImportedStory(userID int, input ImportInput) (*model.Story, error) {
+	if len(input.Content) > MaxImportContentBytes {
+		return nil, ErrImportTooLarge
+	}
+	if !utf8.ValidString(input.Content) || strings.ContainsRune(input.Content, 0) {
+		return nil, ErrUnsupportedImport
+	}
+
+	content := strings.TrimPrefix(input.Content, "\ufeff")
+	content = strings.TrimSpace(strings.ReplaceAll(content, "\r\n", "\n"))
+
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		title, content = extractMarkdownTitle(content)
+	}
+	if content == "" {
+		return nil, ErrEmptyImport
+	}
+	if title == "" {
+		title = titleFromFileName(input.FileName)
+	}
+	if title == "" {
+		title, _, _ = strings.Cut(content, "\n")
+	}
+
+	return &model.Story{
+		UserID:    userID,
+		Title:     truncateRunes(strings.TrimSpace(title), maxImportTitleLength),
+		Content:   content,
+		WordCount: countWords(content),
+		Source:    model.StorySourceImported,
+	}, nil
+}
+
+// extractMarkdownTitle は本文が "# " の見出しで始まる場合、見出しをタイトルとして取り出し残りの本文を返す
+func extractMarkdownTitle(content string) (string, string) {
+	firstLine, rest, _ := strings.Cut(content, "\n")
+	heading, ok := strings.CutPrefix(firstLine, "# ")
+	if !ok {
+		return "", content
+	}
+	return strings.TrimSpace(heading), strings.TrimSpace(rest)
+}
+
+// titleFromFileName はディレクトリと拡張子を除いたファイル名を返す
+func titleFromFileName(fileName string) string {
+	if fileName == "" {
+		return ""
+	}
+	name := path.Base(strings.ReplaceAll(fileName, "\\", "/"))
+	return strings.TrimSpace(strings.TrimSuffix(name, path.Ext(name)))
+}
+
+// truncateRunes は s を最大 n 文字に切り詰める
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:n]))
+}
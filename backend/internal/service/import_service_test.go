@@ -0,0 +1,385 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/archive"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/simhash"
+	"github.com/shuheikomatsuki/readoku/backend/internal/webpage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupImportServiceTest(t *testing.T) (*MockStoryRepository, *MockSeriesRepository, IImportService) {
+	mockStoryRepo := new(MockStoryRepository)
+	mockSeriesRepo := new(MockSeriesRepository)
+
+	importService := NewImportService(mockStoryRepo, mockSeriesRepo, &webpage.Fetcher{
+		Timeout:              webpage.DefaultTimeout,
+		MaxBytes:             webpage.DefaultMaxBytes,
+		AllowPrivateNetworks: true, // httptest のサーバーはループバックで待ち受ける
+	})
+
+	return mockStoryRepo, mockSeriesRepo, importService
+}
+
+func TestImportService_ImportStories(t *testing.T) {
+	mockStoryRepo, _, importService := setupImportServiceTest(t)
+
+	t.Run("success: should use the Markdown heading as the title and count words", func(t *testing.T) {
+		mockStoryRepo.On("CreateStories", mock.AnythingOfType("[]*model.Story")).Return(nil).Once()
+		mockStoryRepo.On("ListStoryFingerprints", testUser.ID).Return(nil, nil).Once()
+
+		stories, err := importService.ImportStories(testUser.ID, []ImportInput{{
+			FileName: "chapter1.md",
+			Content:  "\ufeff# The Lost Key\r\n\r\nTom looked everywhere for the key.\r\n",
+		}})
+
+		require.NoError(t, err)
+		require.Len(t, stories, 1)
+		assert.Equal(t, "The Lost Key", stories[0].Title)
+		assert.Equal(t, "Tom looked everywhere for the key.", stories[0].Content)
+		assert.Equal(t, 6, stories[0].WordCount)
+		assert.Equal(t, model.StorySourceImported, stories[0].Source)
+		mockStoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("success: should fall back to the file name for plain text", func(t *testing.T) {
+		mockStoryRepo.On("CreateStories", mock.AnythingOfType("[]*model.Story")).Return(nil).Once()
+		mockStoryRepo.On("ListStoryFingerprints", testUser.ID).Return(nil, nil).Once()
+
+		stories, err := importService.ImportStories(testUser.ID, []ImportInput{{
+			FileName: "graded readers/The Old Man.txt",
+			Content:  "Once upon a time.",
+		}})
+
+		require.NoError(t, err)
+		assert.Equal(t, "The Old Man", stories[0].Title)
+	})
+
+	t.Run("success: should warn about stories with nearly the same text", func(t *testing.T) {
+		content := "Tom lives in a small town near the sea and walks to the harbor every morning with his dog."
+		mockStoryRepo.On("CreateStories", mock.AnythingOfType("[]*model.Story")).Return(nil).Once()
+		mockStoryRepo.On("ListStoryFingerprints", testUser.ID).Return([]*model.StoryFingerprint{
+			{ID: 7, Title: "Harbor", Simhash: int64(simhash.Fingerprint(content + " The end."))},
+			{ID: 8, Title: "Unrelated", Simhash: int64(simhash.Fingerprint("Anna takes the train to a big city library."))},
+			{ID: 1, Title: "Itself", Simhash: int64(simhash.Fingerprint(content))},
+		}, nil).Once()
+
+		stories, err := importService.ImportStories(testUser.ID, []ImportInput{{Title: "Harbor again", Content: content}})
+
+		require.NoError(t, err)
+		require.Len(t, stories[0].SimilarStories, 1)
+		assert.Equal(t, 7, stories[0].SimilarStories[0].StoryID)
+		assert.Greater(t, stories[0].SimilarStories[0].Similarity, 0.8)
+	})
+
+	t.Run("success: should still return the saved story when the similarity check fails", func(t *testing.T) {
+		mockStoryRepo.On("CreateStories", mock.AnythingOfType("[]*model.Story")).Return(nil).Once()
+		mockStoryRepo.On("ListStoryFingerprints", testUser.ID).Return(nil, errors.New("db error")).Once()
+
+		stories, err := importService.ImportStories(testUser.ID, []ImportInput{{Title: "Saved", Content: "Some text."}})
+
+		require.NoError(t, err)
+		assert.Empty(t, stories[0].SimilarStories)
+	})
+
+	t.Run("fail: should save nothing when one of the texts is invalid", func(t *testing.T) {
+		_, err := importService.ImportStories(testUser.ID, []ImportInput{
+			{Title: "Valid", Content: "Some text."},
+			{Title: "Heading only", Content: "   \n"},
+		})
+
+		assert.ErrorIs(t, err, ErrEmptyImport)
+		mockStoryRepo.AssertNumberOfCalls(t, "CreateStories", 4)
+	})
+
+	t.Run("fail: should return an error when the batch could not be saved", func(t *testing.T) {
+		mockStoryRepo.On("CreateStories", mock.AnythingOfType("[]*model.Story")).Return(errors.New("db error")).Once()
+
+		_, err := importService.ImportStories(testUser.ID, []ImportInput{
+			{Title: "First", Content: "Some text."},
+			{Title: "Second", Content: "More text."},
+		})
+
+		assert.Error(t, err)
+		mockStoryRepo.AssertNotCalled(t, "CreateStory", mock.Anything)
+	})
+
+	t.Run("fail: should reject binary and oversized files", func(t *testing.T) {
+		_, err := importService.ImportStories(testUser.ID, []ImportInput{{Content: "PK\x03\x04\xff\xfe"}})
+		assert.ErrorIs(t, err, ErrUnsupportedImport)
+
+		_, err = importService.ImportStories(testUser.ID, []ImportInput{{Content: strings.Repeat("a ", MaxImportContentBytes)}})
+		assert.ErrorIs(t, err, ErrImportTooLarge)
+	})
+}
+
+func buildTestEPUB(t *testing.T, chapters ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files := map[string]string{
+		"META-INF/container.xml": `<container><rootfiles><rootfile full-path="content.opf"/></rootfiles></container>`,
+	}
+	var manifest, spine strings.Builder
+	for i, chapter := range chapters {
+		name := fmt.Sprintf("ch%d.xhtml", i+1)
+		files[name] = chapter
+		fmt.Fprintf(&manifest, `<item id="c%d" href="%s" media-type="application/xhtml+xml"/>`, i+1, name)
+		fmt.Fprintf(&spine, `<itemref idref="c%d"/>`, i+1)
+	}
+	files["content.opf"] = `<package><metadata><title>My Book</title></metadata><manifest>` +
+		manifest.String() + `</manifest><spine>` + spine.String() + `</spine></package>`
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestImportService_ImportEPUB(t *testing.T) {
+	mockStoryRepo, mockSeriesRepo, importService := setupImportServiceTest(t)
+
+	t.Run("success: should create a series with one story per chapter", func(t *testing.T) {
+		data := buildTestEPUB(t,
+			`<html><body><img src="cover.png"/></body></html>`,
+			`<html><body><h1>Chapter One</h1><p>Tom <em>found</em> a key.</p><script>alert(1)</script></body></html>`,
+			`<html><body><p>The door opened.</p></body></html>`,
+		)
+		mockSeriesRepo.On("CreateSeriesWithChapters", mock.AnythingOfType("*model.Series"), mock.Anything).Return(nil).Once()
+		mockStoryRepo.On("ListStoryFingerprints", testUser.ID).Return(nil, nil).Once()
+
+		result, err := importService.ImportEPUB(testUser.ID, "book.epub", bytes.NewReader(data), int64(len(data)))
+
+		require.NoError(t, err)
+		assert.Equal(t, "My Book", result.Series.Title)
+		require.Len(t, result.Stories, 2)
+		assert.Equal(t, "Chapter One", result.Stories[0].Title)
+		assert.Equal(t, "Tom *found* a key.", result.Stories[0].Content)
+		assert.Equal(t, "My Book (2)", result.Stories[1].Title)
+		assert.Equal(t, model.StorySourceImported, result.Stories[1].Source)
+		mockSeriesRepo.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return ErrInvalidEPUB for a file that is not an epub", func(t *testing.T) {
+		data := []byte("not a zip")
+
+		_, err := importService.ImportEPUB(testUser.ID, "book.epub", bytes.NewReader(data), int64(len(data)))
+
+		assert.ErrorIs(t, err, ErrInvalidEPUB)
+	})
+
+	t.Run("fail: should return ErrImportTooLarge when the file exceeds the limit", func(t *testing.T) {
+		_, err := importService.ImportEPUB(testUser.ID, "book.epub", bytes.NewReader(nil), MaxEPUBBytes+1)
+
+		assert.ErrorIs(t, err, ErrImportTooLarge)
+	})
+
+	t.Run("fail: should return ErrEmptyImport when no chapter has text", func(t *testing.T) {
+		data := buildTestEPUB(t, `<html><body><img src="cover.png"/></body></html>`)
+
+		_, err := importService.ImportEPUB(testUser.ID, "book.epub", bytes.NewReader(data), int64(len(data)))
+
+		assert.ErrorIs(t, err, ErrEmptyImport)
+	})
+}
+
+func TestImportService_ImportURL(t *testing.T) {
+	mockStoryRepo, _, importService := setupImportServiceTest(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/news/rivers", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(`<html><head>
+			<title>Rivers Are Rising - Example</title>
+			<meta property="og:title" content="Rivers Are Rising">
+			<link rel="canonical" href="https://news.example.com/rivers">
+		</head><body>
+			<nav><a href="/">Home</a></nav>
+			<article>
+				<h1>Rivers Are Rising</h1>
+				<p>Heavy rain fell across the valley for three days, and the river rose quickly.</p>
+				<p>Farmers moved their animals to higher ground.</p>
+			</article>
+		</body></html>`))
+	})
+	mux.HandleFunc("/photo", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = w.Write([]byte("\xff\xd8"))
+	})
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	t.Run("success: should save the article with its canonical url", func(t *testing.T) {
+		mockStoryRepo.On("CreateStory", mock.AnythingOfType("*model.Story")).Return(nil).Once()
+		mockStoryRepo.On("ListStoryFingerprints", testUser.ID).Return(nil, nil).Once()
+
+		story, err := importService.ImportURL(testUser.ID, server.URL+"/news/rivers")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Rivers Are Rising", story.Title)
+		assert.Equal(t, "Heavy rain fell across the valley for three days, and the river rose quickly.\n\n"+
+			"Farmers moved their animals to higher ground.", story.Content)
+		assert.Equal(t, model.StorySourceImported, story.Source)
+		require.NotNil(t, story.SourceURL)
+		assert.Equal(t, "https://news.example.com/rivers", *story.SourceURL)
+		mockStoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("fail: should reject private addresses with the default fetcher", func(t *testing.T) {
+		importService := NewImportService(new(MockStoryRepository), new(MockSeriesRepository), webpage.NewFetcher())
+
+		_, err := importService.ImportURL(testUser.ID, server.URL+"/news/rivers")
+
+		assert.ErrorIs(t, err, ErrInvalidImportURL)
+	})
+
+	t.Run("fail: should reject pages that are not html", func(t *testing.T) {
+		_, err := importService.ImportURL(testUser.ID, server.URL+"/photo")
+
+		assert.ErrorIs(t, err, ErrUnsupportedImport)
+	})
+
+	t.Run("fail: should return ErrImportFetchFailed for error responses", func(t *testing.T) {
+		_, err := importService.ImportURL(testUser.ID, server.URL+"/gone")
+
+		assert.ErrorIs(t, err, ErrImportFetchFailed)
+	})
+}
+
+func buildTestArchive(t *testing.T, tags []string, stories ...*archive.Story) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := archive.NewWriter(&buf, time.Now(), tags)
+	for _, story := range stories {
+		require.NoError(t, w.AddStory(story))
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestImportService_ImportArchive(t *testing.T) {
+	mockStoryRepo, _, importService := setupImportServiceTest(t)
+
+	level := "B1"
+	createdAt := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	readAt := time.Date(2024, 5, 2, 8, 30, 0, 0, time.UTC)
+
+	t.Run("success: should pass validated stories and every tag to the repository", func(t *testing.T) {
+		data := buildTestArchive(t, []string{"news", "unused"},
+			&archive.Story{
+				ID: 7, Title: "Rivers", Content: "Heavy rain fell.", WordCount: 99, Level: &level, Tags: []string{"news", " extra "},
+				Source: "generated", IsFavorite: true, CreatedAt: createdAt,
+				ReadingRecords: []*archive.ReadingRecord{{ReadAt: readAt, WordCount: 3}},
+			},
+			&archive.Story{ID: 8, Title: "Key", Content: "Tom found a key.", Source: "imported", CreatedAt: createdAt},
+		)
+		result := &model.ArchiveImport{
+			Created:    1,
+			Duplicates: 1,
+			Stories: []*model.ArchiveImportResult{
+				{Title: "Rivers", Status: model.ArchiveImportCreated, StoryID: 101},
+				{Title: "Key", Status: model.ArchiveImportDuplicate, StoryID: 55},
+			},
+		}
+		matchStories := mock.MatchedBy(func(stories []*model.ArchiveStory) bool {
+			return len(stories) == 2 &&
+				stories[0].UserID == testUser.ID &&
+				stories[0].Content == "Heavy rain fell." &&
+				stories[0].WordCount == 3 &&
+				*stories[0].Level == "B1" &&
+				stories[0].IsFavorite &&
+				stories[0].CreatedAt.Equal(createdAt) &&
+				assert.ObjectsAreEqual([]string{"news", "extra"}, stories[0].Tags) &&
+				len(stories[0].ReadingRecords) == 1 && stories[0].ReadingRecords[0].ReadAt.Equal(readAt) &&
+				stories[1].Source == model.StorySourceImported && stories[1].Level == nil
+		})
+		mockStoryRepo.On("ImportArchiveStories", testUser.ID, []string{"news", "unused", "extra"}, matchStories).Return(result, nil).Once()
+		mockStoryRepo.On("ListStoryFingerprints", testUser.ID).Return(nil, nil).Once()
+
+		imported, err := importService.ImportArchive(testUser.ID, bytes.NewReader(data), int64(len(data)))
+
+		require.NoError(t, err)
+		assert.Equal(t, "stories/00007-rivers.md", imported.Stories[0].File)
+		assert.Equal(t, "stories/00008-key.md", imported.Stories[1].File)
+		mockStoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("success: should warn about created stories with nearly the same text", func(t *testing.T) {
+		content := "Tom lives in a small town near the sea and walks to the harbor every morning with his dog."
+		data := buildTestArchive(t, nil,
+			&archive.Story{ID: 1, Title: "Harbor again", Content: content, Source: "imported", CreatedAt: createdAt},
+			&archive.Story{ID: 2, Title: "Harbor", Content: content + " The end.", Source: "imported", CreatedAt: createdAt},
+		)
+		result := &model.ArchiveImport{
+			Created:    1,
+			Duplicates: 1,
+			Stories: []*model.ArchiveImportResult{
+				{Title: "Harbor again", Status: model.ArchiveImportCreated, StoryID: 101},
+				{Title: "Harbor", Status: model.ArchiveImportDuplicate, StoryID: 7},
+			},
+		}
+		mockStoryRepo.On("ImportArchiveStories", testUser.ID, mock.Anything, mock.Anything).Return(result, nil).Once()
+		mockStoryRepo.On("ListStoryFingerprints", testUser.ID).Return([]*model.StoryFingerprint{
+			{ID: 7, Title: "Harbor", Simhash: int64(simhash.Fingerprint(content + " The end."))},
+			{ID: 101, Title: "Harbor again", Simhash: int64(simhash.Fingerprint(content))},
+		}, nil).Once()
+
+		imported, err := importService.ImportArchive(testUser.ID, bytes.NewReader(data), int64(len(data)))
+
+		require.NoError(t, err)
+		require.Len(t, imported.Stories[0].SimilarStories, 1)
+		assert.Equal(t, 7, imported.Stories[0].SimilarStories[0].StoryID)
+		// 本文が同じ既存のストーリーとして扱ったものには警告を付けない
+		assert.Empty(t, imported.Stories[1].SimilarStories)
+		mockStoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return ErrInvalidArchive for an unknown level", func(t *testing.T) {
+		unknown := "Z9"
+		data := buildTestArchive(t, nil, &archive.Story{ID: 1, Title: "A", Content: "Text.", Level: &unknown, Source: "generated", CreatedAt: createdAt})
+
+		_, err := importService.ImportArchive(testUser.ID, bytes.NewReader(data), int64(len(data)))
+
+		assert.ErrorIs(t, err, ErrInvalidArchive)
+	})
+
+	t.Run("fail: should return ErrInvalidArchive for an empty story", func(t *testing.T) {
+		data := buildTestArchive(t, nil, &archive.Story{ID: 1, Title: "A", Content: "  ", Source: "generated", CreatedAt: createdAt})
+
+		_, err := importService.ImportArchive(testUser.ID, bytes.NewReader(data), int64(len(data)))
+
+		assert.ErrorIs(t, err, ErrInvalidArchive)
+	})
+
+	t.Run("fail: should return ErrInvalidArchive for a file that is not an archive", func(t *testing.T) {
+		data := []byte("not a zip")
+
+		_, err := importService.ImportArchive(testUser.ID, bytes.NewReader(data), int64(len(data)))
+
+		assert.ErrorIs(t, err, ErrInvalidArchive)
+	})
+
+	t.Run("fail: should return ErrImportTooLarge when the file exceeds the limit", func(t *testing.T) {
+		_, err := importService.ImportArchive(testUser.ID, bytes.NewReader(nil), MaxArchiveBytes+1)
+
+		assert.ErrorIs(t, err, ErrImportTooLarge)
+	})
+}
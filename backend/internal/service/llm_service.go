@@ -2,15 +2,39 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"google.golang.org/genai"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
 )
 
 type ILLMService interface {
-	GenerateStory(prompt string) (string, error)
+	GenerateStory(prompt string, options GenerationOptions) (string, error)
+	SummarizeChapter(previousSummary, chapter string) (string, error)
+	ContinueStory(seriesTitle, summary string, chapterNumber int) (string, error)
+	GenerateQuiz(content string, numQuestions int) ([]GeneratedQuestion, error)
+	RewriteStory(content, currentLevel, targetLevel string) (string, error)
+	TranslateParagraphs(paragraphs []string) ([]string, error)
+}
+
+// GeneratedQuestion は LLM が生成した読解問題
+type GeneratedQuestion struct {
+	Type        string   `json:"type"`
+	Question    string   `json:"question"`
+	Choices     []string `json:"choices"`
+	AnswerIndex int      `json:"answer_index"`
+}
+
+// GenerationOptions は文章生成時の難易度・長さの指定。ゼロ値は指定なし
+type GenerationOptions struct {
+	Level     string
+	WordCount int
 }
 
 type LLMService struct {
@@ -37,25 +61,196 @@ func NewLLMService(apiKey string) (ILLMService, error) {
 	}, nil
 }
 
-func (s *LLMService) GenerateStory(prompt string) (string, error) {
-	if s.client == nil {
-		return "", fmt.Errorf("genai client is not initialized")
-	}
-
+func (s *LLMService) GenerateStory(prompt string, options GenerationOptions) (string, error) {
 	instructionalPrompt := fmt.Sprintf(
 		`Write a clear, factual explanation in English based on the user's prompt.
 The user's prompt may be written in Japanese or English.
 Always write the output in English.
-Use standard Markdown for paragraphs and lists where appropriate.
+%sUse standard Markdown for paragraphs and lists where appropriate.
 Do not write a story, narrative, or fictional content.
 Return only the Markdown content, without explanations or notes outside the text.
 
 --- USER PROMPT START ---
 %s
 --- USER PROMPT END ---`,
+		generationConstraints(options),
 		prompt,
 	)
 
+	return s.generateContent(instructionalPrompt)
+}
+
+// generationConstraints は難易度・長さの指定をプロンプト用の指示文に変換する
+func generationConstraints(options GenerationOptions) string {
+	var b strings.Builder
+	if options.Level != "" {
+		fmt.Fprintf(&b, "Write at CEFR level %s: use vocabulary and grammar appropriate for that level.\n", options.Level)
+	}
+	if options.WordCount > 0 {
+		fmt.Fprintf(&b, "The text should be about %d words long.\n", options.WordCount)
+	}
+	return b.String()
+}
+
+// SummarizeChapter は既存の要約に新しい章の内容を取り込み、圧縮した要約を返す
+func (s *LLMService) SummarizeChapter(previousSummary, chapter string) (string, error) {
+	instructionalPrompt := fmt.Sprintf(
+		`You maintain a running summary of a multi-chapter English reading text.
+Merge the previous summary and the new chapter into one updated summary.
+Keep key facts, names, terms and open threads needed to write the next chapter.
+Keep the summary under 200 words, in plain English, without Markdown.
+Return only the summary.
+
+--- PREVIOUS SUMMARY START ---
+%s
+--- PREVIOUS SUMMARY END ---
+
+--- NEW CHAPTER START ---
+%s
+--- NEW CHAPTER END ---`,
+		previousSummary,
+		chapter,
+	)
+
+	return s.generateContent(instructionalPrompt)
+}
+
+// ContinueStory は連載のこれまでの要約をもとに次の章を生成する
+func (s *LLMService) ContinueStory(seriesTitle, summary string, chapterNumber int) (string, error) {
+	instructionalPrompt := fmt.Sprintf(
+		`Write chapter %d of a multi-chapter English reading text titled "%s".
+The summary below describes the previous chapters.
+Continue naturally from where they left off, keeping the same tone, style and difficulty.
+Do not repeat content that was already covered.
+Always write the output in English.
+Use standard Markdown for paragraphs and lists where appropriate.
+Return only the Markdown content, without explanations or notes outside the text.
+
+--- SUMMARY OF PREVIOUS CHAPTERS START ---
+%s
+--- SUMMARY OF PREVIOUS CHAPTERS END ---`,
+		chapterNumber,
+		seriesTitle,
+		summary,
+	)
+
+	return s.generateContent(instructionalPrompt)
+}
+
+// GenerateQuiz は本文の内容理解を確認する多肢選択式・正誤式の問題を生成する
+func (s *LLMService) GenerateQuiz(content string, numQuestions int) ([]GeneratedQuestion, error) {
+	instructionalPrompt := fmt.Sprintf(
+		`Create %d reading comprehension questions for the English text below.
+Mix "multiple_choice" questions (exactly 4 choices) and "true_false" questions (choices must be ["True", "False"]).
+Every question must be answerable only from the text. Write questions and choices in English.
+Return only a JSON array without Markdown code fences, where each element has:
+{"type": "multiple_choice" | "true_false", "question": string, "choices": [string], "answer_index": number (0-based index of the correct choice)}
+
+--- TEXT START ---
+%s
+--- TEXT END ---`,
+		numQuestions,
+		content,
+	)
+
+	text, err := s.generateContent(instructionalPrompt)
+	if err != nil {
+		return nil, err
+	}
+
+	var questions []GeneratedQuestion
+	if err := json.Unmarshal([]byte(extractJSON(text)), &questions); err != nil {
+		return nil, fmt.Errorf("failed to parse quiz response: %w", err)
+	}
+
+	return questions, nil
+}
+
+// RewriteStory は本文を指定した CEFR レベルに合わせて書き換える。
+// currentLevel が不明な場合は空文字を渡す。
+func (s *LLMService) RewriteStory(content, currentLevel, targetLevel string) (string, error) {
+	instructionalPrompt := fmt.Sprintf(
+		`Rewrite the following English text for a learner at CEFR level %s.
+%s
+Keep the same topic, facts and overall structure so that the two versions can be read side by side.
+Always write the output in English.
+Use standard Markdown for paragraphs and lists where appropriate.
+Return only the Markdown content, without explanations or notes outside the text.
+
+--- TEXT START ---
+%s
+--- TEXT END ---`,
+		targetLevel,
+		rewriteInstruction(currentLevel, targetLevel),
+		content,
+	)
+
+	return s.generateContent(instructionalPrompt)
+}
+
+// rewriteInstruction は元のレベルと目標レベルの差に応じて、簡略化または高度化の指示を返す
+func rewriteInstruction(currentLevel, targetLevel string) string {
+	current := slices.Index(model.CEFRLevels, currentLevel)
+	target := slices.Index(model.CEFRLevels, targetLevel)
+
+	switch {
+	case current >= 0 && target < current:
+		return "Simplify the text: use more common words, shorter sentences and simpler grammar, and explain difficult ideas plainly."
+	case current >= 0 && target > current:
+		return "Enrich the text: use a wider vocabulary, more varied sentence structures and more detail, while staying natural."
+	default:
+		return "Adjust the vocabulary, sentence length and grammar so that they match the target level."
+	}
+}
+
+// TranslateParagraphs は英語の段落を 1 段落ずつ日本語に翻訳し、同じ順序・同じ数の配列で返す
+func (s *LLMService) TranslateParagraphs(paragraphs []string) ([]string, error) {
+	input, err := json.Marshal(paragraphs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode paragraphs: %w", err)
+	}
+
+	instructionalPrompt := fmt.Sprintf(
+		`Translate each English paragraph in the JSON array below into natural Japanese for an English learner.
+Translate paragraph by paragraph: the output must be a JSON array with exactly %d strings, in the same order as the input.
+Do not merge, split, skip or reorder paragraphs. Keep Markdown markers such as "#", "-" and "**" in place.
+Return only the JSON array without Markdown code fences.
+
+--- PARAGRAPHS START ---
+%s
+--- PARAGRAPHS END ---`,
+		len(paragraphs),
+		string(input),
+	)
+
+	text, err := s.generateContent(instructionalPrompt)
+	if err != nil {
+		return nil, err
+	}
+
+	var translated []string
+	if err := json.Unmarshal([]byte(extractJSON(text)), &translated); err != nil {
+		return nil, fmt.Errorf("failed to parse translation response: %w", err)
+	}
+
+	return translated, nil
+}
+
+// extractJSON は LLM の応答に含まれがちなコードフェンスを取り除く
+func extractJSON(text string) string {
+	text = strings.TrimSpace(text)
+	text = strings.TrimPrefix(text, "```json")
+	text = strings.TrimPrefix(text, "```")
+	text = strings.TrimSuffix(text, "```")
+	return strings.TrimSpace(text)
+}
+
+// generateContent は Gemini API を呼び出し、テキストのレスポンスを返す
+func (s *LLMService) generateContent(instructionalPrompt string) (string, error) {
+	if s.client == nil {
+		return "", fmt.Errorf("genai client is not initialized")
+	}
+
 	// API 呼び出しにタイムアウトを設定
 	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
 	defer cancel()
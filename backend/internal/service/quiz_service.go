@@ -0,0 +1,207 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
+)
+
+const (
+	// quizQuestionCount は 1 回の生成で作成する問題数
+	quizQuestionCount = 5
+	// quizPassingRatio は合格とみなす正答率
+	quizPassingRatio = 0.7
+)
+
+var (
+	ErrQuizNotFound       = errors.New("quiz not found")
+	ErrInvalidQuizAnswers = errors.New("invalid quiz answers")
+	ErrInvalidQuiz        = errors.New("invalid quiz generated")
+)
+
+// QuestionResult は問題ごとの採点結果
+type QuestionResult struct {
+	QuestionID   int  `json:"question_id"`
+	Answer       int  `json:"answer"`
+	CorrectIndex int  `json:"correct_index"`
+	Correct      bool `json:"correct"`
+}
+
+// QuizResult はサービス層が返す採点結果のモデル
+type QuizResult struct {
+	Attempt *model.QuizAttempt
+	Results []QuestionResult
+}
+
+type IQuizService interface {
+	GenerateQuiz(storyID, userID int) ([]*model.QuizQuestion, error)
+	GetQuiz(storyID, userID int) ([]*model.QuizQuestion, error)
+	SubmitAnswers(storyID, userID int, answers []int) (*QuizResult, error)
+}
+
+type QuizService struct {
+	StoryRepo  repository.IStoryRepository
+	QuizRepo   repository.IQuizRepository
+	UserRepo   repository.IUserRepository
+	LLMService ILLMService
+	DailyLimit int
+}
+
+func NewQuizService(storyRepo repository.IStoryRepository, quizRepo repository.IQuizRepository, userRepo repository.IUserRepository, llmService ILLMService, dailyLimit int) IQuizService {
+	return &QuizService{
+		StoryRepo:  storyRepo,
+		QuizRepo:   quizRepo,
+		UserRepo:   userRepo,
+		LLMService: llmService,
+		DailyLimit: dailyLimit,
+	}
+}
+
+func (s *QuizService) getUserStory(storyID, userID int) (*model.Story, error) {
+	story, err := s.StoryRepo.GetUserStory(storyID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrStoryNotFound
+		}
+		return nil, fmt.Errorf("database error (get story): %w", err)
+	}
+	return story, nil
+}
+
+// GenerateQuiz は読解問題を生成して保存する。既存の問題は置き換えられる。
+// 文章の生成と同じ 1 日の生成回数に数える
+func (s *QuizService) GenerateQuiz(storyID, userID int) ([]*model.QuizQuestion, error) {
+	story, err := s.getUserStory(storyID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	currentCount, now, err := checkGenerationLimit(s.UserRepo, s.DailyLimit, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	generated, err := s.LLMService.GenerateQuiz(story.Content, quizQuestionCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate quiz: %w", err)
+	}
+
+	questions, err := toQuizQuestions(generated)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.QuizRepo.ReplaceQuizQuestions(story.ID, questions); err != nil {
+		return nil, fmt.Errorf("failed to save quiz: %w", err)
+	}
+
+	recordGeneration(s.UserRepo, userID, currentCount, now)
+
+	return questions, nil
+}
+
+// toQuizQuestions は LLM の出力を検証し、保存用のモデルに変換する
+func toQuizQuestions(generated []GeneratedQuestion) ([]*model.QuizQuestion, error) {
+	if len(generated) == 0 {
+		return nil, ErrInvalidQuiz
+	}
+
+	questions := make([]*model.QuizQuestion, 0, len(generated))
+	for i, g := range generated {
+		switch g.Type {
+		case model.QuestionTypeMultipleChoice:
+			if len(g.Choices) < 2 {
+				return nil, fmt.Errorf("%w: question %d has too few choices", ErrInvalidQuiz, i+1)
+			}
+		case model.QuestionTypeTrueFalse:
+			g.Choices = []string{"True", "False"}
+		default:
+			return nil, fmt.Errorf("%w: unknown question type %q", ErrInvalidQuiz, g.Type)
+		}
+		if g.Question == "" || g.AnswerIndex < 0 || g.AnswerIndex >= len(g.Choices) {
+			return nil, fmt.Errorf("%w: question %d is malformed", ErrInvalidQuiz, i+1)
+		}
+
+		questions = append(questions, &model.QuizQuestion{
+			QuestionType: g.Type,
+			Question:     g.Question,
+			Choices:      pq.StringArray(g.Choices),
+			CorrectIndex: g.AnswerIndex,
+			Position:     i + 1,
+		})
+	}
+	return questions, nil
+}
+
+func (s *QuizService) GetQuiz(storyID, userID int) ([]*model.QuizQuestion, error) {
+	if _, err := s.getUserStory(storyID, userID); err != nil {
+		return nil, err
+	}
+
+	questions, err := s.QuizRepo.GetQuizQuestions(storyID)
+	if err != nil {
+		return nil, fmt.Errorf("database error (get quiz): %w", err)
+	}
+	if len(questions) == 0 {
+		return nil, ErrQuizNotFound
+	}
+	return questions, nil
+}
+
+// SubmitAnswers は解答を採点し、結果を保存する。answers は問題の並び順に対応する選択肢のインデックス。
+// 解答の数が問題の数と異なる場合や、選択肢の範囲外のインデックスを含む場合は ErrInvalidQuizAnswers を返す
+func (s *QuizService) SubmitAnswers(storyID, userID int, answers []int) (*QuizResult, error) {
+	questions, err := s.GetQuiz(storyID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(answers) != len(questions) {
+		return nil, ErrInvalidQuizAnswers
+	}
+	// 選択肢にない番号は不正解として保存せず、解答そのものを誤りとして扱う
+	for i, q := range questions {
+		if answers[i] < 0 || answers[i] >= len(q.Choices) {
+			return nil, ErrInvalidQuizAnswers
+		}
+	}
+
+	score := 0
+	results := make([]QuestionResult, 0, len(questions))
+	storedAnswers := make(pq.Int64Array, 0, len(answers))
+	for i, q := range questions {
+		correct := answers[i] == q.CorrectIndex
+		if correct {
+			score++
+		}
+		results = append(results, QuestionResult{
+			QuestionID:   q.ID,
+			Answer:       answers[i],
+			CorrectIndex: q.CorrectIndex,
+			Correct:      correct,
+		})
+		storedAnswers = append(storedAnswers, int64(answers[i]))
+	}
+
+	attempt := &model.QuizAttempt{
+		UserID:  userID,
+		StoryID: storyID,
+		Answers: storedAnswers,
+		Score:   score,
+		Total:   len(questions),
+		Passed:  float64(score) >= float64(len(questions))*quizPassingRatio,
+	}
+
+	if err := s.QuizRepo.CreateQuizAttempt(attempt); err != nil {
+		return nil, fmt.Errorf("failed to save quiz attempt: %w", err)
+	}
+
+	return &QuizResult{
+		Attempt: attempt,
+		Results: results,
+	}, nil
+}
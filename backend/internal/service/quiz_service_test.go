@@ -0,0 +1,141 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupQuizServiceTest(t *testing.T) (*MockStoryRepository, *MockQuizRepository, *MockUserRepository, *MockLLMService, IQuizService) {
+	mockStoryRepo := new(MockStoryRepository)
+	mockQuizRepo := new(MockQuizRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockLLM := new(MockLLMService)
+
+	quizService := NewQuizService(mockStoryRepo, mockQuizRepo, mockUserRepo, mockLLM, testDailyLimit)
+
+	return mockStoryRepo, mockQuizRepo, mockUserRepo, mockLLM, quizService
+}
+
+func TestQuizService_GenerateQuiz(t *testing.T) {
+	mockStoryRepo, mockQuizRepo, mockUserRepo, mockLLM, quizService := setupQuizServiceTest(t)
+	now := timeutil.NowTokyo()
+
+	t.Run("success: should generate and save questions", func(t *testing.T) {
+		generated := []GeneratedQuestion{
+			{Type: model.QuestionTypeMultipleChoice, Question: "What is tested?", Choices: []string{"A", "B", "C", "D"}, AnswerIndex: 2},
+			{Type: model.QuestionTypeTrueFalse, Question: "This is a test.", Choices: []string{"yes", "no"}, AnswerIndex: 0},
+		}
+
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Once()
+		mockUserRepo.On("GetUserByID", testUser.ID).Return(&model.User{ID: testUser.ID, GenerationCount: 2, LastGenerationAt: &now}, nil).Once()
+		mockLLM.On("GenerateQuiz", testStory.Content, quizQuestionCount).Return(generated, nil).Once()
+		mockQuizRepo.On("ReplaceQuizQuestions", testStory.ID, mock.AnythingOfType("[]*model.QuizQuestion")).Return(nil).Once()
+		mockUserRepo.On("UpdateGenerationStatus", testUser.ID, 3, mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+		questions, err := quizService.GenerateQuiz(testStory.ID, testUser.ID)
+
+		require.NoError(t, err)
+		require.Len(t, questions, 2)
+		assert.Equal(t, 2, questions[0].CorrectIndex)
+		assert.Equal(t, 1, questions[0].Position)
+		// 正誤問題の選択肢は固定される
+		assert.Equal(t, []string{"True", "False"}, []string(questions[1].Choices))
+
+		mockStoryRepo.AssertExpectations(t)
+		mockLLM.AssertExpectations(t)
+		mockQuizRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("fail: should reject a malformed quiz", func(t *testing.T) {
+		generated := []GeneratedQuestion{
+			{Type: model.QuestionTypeMultipleChoice, Question: "Out of range?", Choices: []string{"A", "B"}, AnswerIndex: 5},
+		}
+
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Once()
+		mockUserRepo.On("GetUserByID", testUser.ID).Return(&model.User{ID: testUser.ID}, nil).Once()
+		mockLLM.On("GenerateQuiz", testStory.Content, quizQuestionCount).Return(generated, nil).Once()
+
+		_, err := quizService.GenerateQuiz(testStory.ID, testUser.ID)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrInvalidQuiz)
+		// 最初のサブテストでの 1 回のみ
+		mockQuizRepo.AssertNumberOfCalls(t, "ReplaceQuizQuestions", 1)
+		mockUserRepo.AssertNumberOfCalls(t, "UpdateGenerationStatus", 1)
+	})
+
+	t.Run("fail: should return ErrGenerationLimitExceeded without calling the LLM", func(t *testing.T) {
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Once()
+		mockUserRepo.On("GetUserByID", testUser.ID).Return(&model.User{ID: testUser.ID, GenerationCount: testDailyLimit, LastGenerationAt: &now}, nil).Once()
+
+		_, err := quizService.GenerateQuiz(testStory.ID, testUser.ID)
+
+		assert.ErrorIs(t, err, ErrGenerationLimitExceeded)
+		mockLLM.AssertNumberOfCalls(t, "GenerateQuiz", 2)
+	})
+}
+
+func TestQuizService_SubmitAnswers(t *testing.T) {
+	mockStoryRepo, mockQuizRepo, _, _, quizService := setupQuizServiceTest(t)
+
+	questions := []*model.QuizQuestion{
+		{ID: 1, StoryID: testStory.ID, QuestionType: model.QuestionTypeMultipleChoice, Choices: []string{"A", "B", "C", "D"}, CorrectIndex: 1, Position: 1},
+		{ID: 2, StoryID: testStory.ID, QuestionType: model.QuestionTypeTrueFalse, Choices: []string{"True", "False"}, CorrectIndex: 0, Position: 2},
+		{ID: 3, StoryID: testStory.ID, QuestionType: model.QuestionTypeTrueFalse, Choices: []string{"True", "False"}, CorrectIndex: 1, Position: 3},
+	}
+
+	t.Run("success: should grade answers and store the attempt", func(t *testing.T) {
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Once()
+		mockQuizRepo.On("GetQuizQuestions", testStory.ID).Return(questions, nil).Once()
+		mockQuizRepo.On("CreateQuizAttempt", mock.AnythingOfType("*model.QuizAttempt")).Return(nil).Once()
+
+		result, err := quizService.SubmitAnswers(testStory.ID, testUser.ID, []int{1, 0, 0})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Attempt.Score)
+		assert.Equal(t, 3, result.Attempt.Total)
+		assert.False(t, result.Attempt.Passed, "2/3 is below the passing ratio")
+		assert.True(t, result.Results[0].Correct)
+		assert.False(t, result.Results[2].Correct)
+
+		mockQuizRepo.AssertExpectations(t)
+	})
+
+	t.Run("fail: should reject answers that do not match the questions", func(t *testing.T) {
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Once()
+		mockQuizRepo.On("GetQuizQuestions", testStory.ID).Return(questions, nil).Once()
+
+		_, err := quizService.SubmitAnswers(testStory.ID, testUser.ID, []int{1})
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrInvalidQuizAnswers)
+	})
+
+	t.Run("fail: should reject answers outside the choices", func(t *testing.T) {
+		for _, answers := range [][]int{{-1, 0, 0}, {1, 2, 0}, {1, 0, 99}} {
+			mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Once()
+			mockQuizRepo.On("GetQuizQuestions", testStory.ID).Return(questions, nil).Once()
+
+			_, err := quizService.SubmitAnswers(testStory.ID, testUser.ID, answers)
+
+			assert.ErrorIs(t, err, ErrInvalidQuizAnswers, answers)
+		}
+		mockQuizRepo.AssertNumberOfCalls(t, "CreateQuizAttempt", 1)
+	})
+
+	t.Run("fail: should return ErrQuizNotFound if no quiz exists", func(t *testing.T) {
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Once()
+		mockQuizRepo.On("GetQuizQuestions", testStory.ID).Return([]*model.QuizQuestion{}, nil).Once()
+
+		_, err := quizService.SubmitAnswers(testStory.ID, testUser.ID, []int{0})
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrQuizNotFound)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -49,16 +50,34 @@ func (m *MockStoryRepository) CreateStory(story *model.Story) error {
 	return args.Error(0)
 }
 
-func (m *MockStoryRepository) GetUserStories(userID, limit, offset int) ([]*model.Story, error) {
-	args := m.Called(userID, limit, offset)
+func (m *MockStoryRepository) CreateStories(stories []*model.Story) error {
+	args := m.Called(stories)
+	if args.Error(0) == nil {
+		for i, story := range stories {
+			story.ID = i + 1
+		}
+	}
+	return args.Error(0)
+}
+
+func (m *MockStoryRepository) GetUserStories(userID int, filter repository.StoryFilter, sort repository.StorySort, limit, offset int) ([]*model.StoryListItem, error) {
+	args := m.Called(userID, filter, sort, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*model.Story), args.Error(1)
+	return args.Get(0).([]*model.StoryListItem), args.Error(1)
 }
 
-func (m *MockStoryRepository) CountUserStories(userID int) (int, error) {
-	args := m.Called(userID)
+func (m *MockStoryRepository) GetUserStoriesByKeyset(userID int, filter repository.StoryFilter, sort repository.StorySort, keyset *repository.Keyset, limit int) ([]*model.StoryListItem, error) {
+	args := m.Called(userID, filter, sort, keyset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.StoryListItem), args.Error(1)
+}
+
+func (m *MockStoryRepository) CountUserStories(userID int, filter repository.StoryFilter) (int, error) {
+	args := m.Called(userID, filter)
 	return args.Int(0), args.Error(1)
 }
 
@@ -83,6 +102,174 @@ func (m *MockStoryRepository) UpdateStoryTitle(storyID, userID int, newTitle str
 	return args.Get(0).(*model.Story), args.Error(1)
 }
 
+func (m *MockStoryRepository) SetStorySeries(storyID, seriesID, chapterNumber int) error {
+	args := m.Called(storyID, seriesID, chapterNumber)
+	return args.Error(0)
+}
+
+func (m *MockStoryRepository) GetTrashedStories(userID int) ([]*model.Story, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Story), args.Error(1)
+}
+
+func (m *MockStoryRepository) RestoreStory(storyID, userID int) (*model.Story, error) {
+	args := m.Called(storyID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Story), args.Error(1)
+}
+
+func (m *MockStoryRepository) PurgeDeletedStories(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStoryRepository) BulkUpdateStories(userID int, op repository.BulkOperation) ([]*model.BulkStoryResult, error) {
+	args := m.Called(userID, op)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.BulkStoryResult), args.Error(1)
+}
+
+func (m *MockStoryRepository) GetStoriesBySelection(userID int, selection repository.StorySelection) ([]*model.Story, error) {
+	args := m.Called(userID, selection)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Story), args.Error(1)
+}
+
+func (m *MockStoryRepository) GetArchiveStories(userID, afterID, limit int) ([]*model.ArchiveStory, error) {
+	args := m.Called(userID, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.ArchiveStory), args.Error(1)
+}
+
+func (m *MockStoryRepository) ImportArchiveStories(userID int, tags []string, stories []*model.ArchiveStory) (*model.ArchiveImport, error) {
+	args := m.Called(userID, tags, stories)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ArchiveImport), args.Error(1)
+}
+
+func (m *MockStoryRepository) ListStoryFingerprints(userID int) ([]*model.StoryFingerprint, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.StoryFingerprint), args.Error(1)
+}
+
+func (m *MockStoryRepository) BackfillSimhashes(limit int) (int, error) {
+	args := m.Called(limit)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockStoryRepository) UpdateStoryContent(storyID, userID int, title, content string, wordCount int) (*model.Story, error) {
+	args := m.Called(storyID, userID, title, content, wordCount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Story), args.Error(1)
+}
+
+func (m *MockStoryRepository) GetStoryRevisions(storyID int) ([]*model.StoryRevision, error) {
+	args := m.Called(storyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.StoryRevision), args.Error(1)
+}
+
+func (m *MockStoryRepository) GetStoryRevision(storyID, revisionNumber int) (*model.StoryRevision, error) {
+	args := m.Called(storyID, revisionNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.StoryRevision), args.Error(1)
+}
+
+func (m *MockStoryRepository) SetFavorite(storyID, userID int, favorite bool) error {
+	args := m.Called(storyID, userID, favorite)
+	return args.Error(0)
+}
+
+func (m *MockStoryRepository) GetDerivedStories(storyID, userID int) ([]*model.StoryVariant, error) {
+	args := m.Called(storyID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.StoryVariant), args.Error(1)
+}
+
+type MockSeriesRepository struct {
+	mock.Mock
+}
+
+func (m *MockSeriesRepository) CreateSeries(series *model.Series) error {
+	args := m.Called(series)
+	if args.Error(0) == nil {
+		series.ID = 1 // モックでIDを設定
+	}
+	return args.Error(0)
+}
+
+func (m *MockSeriesRepository) CreateSeriesWithChapters(series *model.Series, chapters []*model.Story) error {
+	args := m.Called(series, chapters)
+	return args.Error(0)
+}
+
+func (m *MockSeriesRepository) GetUserSeries(seriesID, userID int) (*model.Series, error) {
+	args := m.Called(seriesID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Series), args.Error(1)
+}
+
+func (m *MockSeriesRepository) UpdateSeriesSummary(seriesID int, summary string, summarizedThrough int) error {
+	args := m.Called(seriesID, summary, summarizedThrough)
+	return args.Error(0)
+}
+
+func (m *MockSeriesRepository) GetSeriesChapters(seriesID int) ([]*model.SeriesChapter, error) {
+	args := m.Called(seriesID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SeriesChapter), args.Error(1)
+}
+
+func (m *MockSeriesRepository) GetLastChapterNumber(seriesID int) (int, error) {
+	args := m.Called(seriesID)
+	return args.Int(0), args.Error(1)
+}
+
+type MockTranslationRepository struct {
+	mock.Mock
+}
+
+func (m *MockTranslationRepository) UpsertTranslation(translation *model.StoryTranslation) error {
+	args := m.Called(translation)
+	return args.Error(0)
+}
+
+func (m *MockTranslationRepository) GetTranslation(storyID int, language string) (*model.StoryTranslation, error) {
+	args := m.Called(storyID, language)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.StoryTranslation), args.Error(1)
+}
+
 type MockReadingRecordRepository struct {
 	mock.Mock
 }
@@ -110,6 +297,14 @@ func (m *MockReadingRecordRepository) DeleteReadingRecord(recordID int, userID i
 	return args.Error(0)
 }
 
+func (m *MockReadingRecordRepository) GetReadingHistory(userID int, keyset *repository.Keyset, limit int) ([]*model.ReadingHistoryItem, error) {
+	args := m.Called(userID, keyset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.ReadingHistoryItem), args.Error(1)
+}
+
 func (m *MockReadingRecordRepository) GetUserTotalWordCount(userID int) (int, error) {
 	args := m.Called(userID)
 	return args.Int(0), args.Error(1)
@@ -135,11 +330,64 @@ type MockLLMService struct {
 	mock.Mock
 }
 
-func (m *MockLLMService) GenerateStory(prompt string) (string, error) {
-	args := m.Called(prompt)
+func (m *MockLLMService) GenerateStory(prompt string, options GenerationOptions) (string, error) {
+	args := m.Called(prompt, options)
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockLLMService) SummarizeChapter(previousSummary, chapter string) (string, error) {
+	args := m.Called(previousSummary, chapter)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockLLMService) ContinueStory(seriesTitle, summary string, chapterNumber int) (string, error) {
+	args := m.Called(seriesTitle, summary, chapterNumber)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockLLMService) GenerateQuiz(content string, numQuestions int) ([]GeneratedQuestion, error) {
+	args := m.Called(content, numQuestions)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]GeneratedQuestion), args.Error(1)
+}
+
+func (m *MockLLMService) RewriteStory(content, currentLevel, targetLevel string) (string, error) {
+	args := m.Called(content, currentLevel, targetLevel)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockLLMService) TranslateParagraphs(paragraphs []string) ([]string, error) {
+	args := m.Called(paragraphs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+type MockQuizRepository struct {
+	mock.Mock
+}
+
+func (m *MockQuizRepository) ReplaceQuizQuestions(storyID int, questions []*model.QuizQuestion) error {
+	args := m.Called(storyID, questions)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepository) GetQuizQuestions(storyID int) ([]*model.QuizQuestion, error) {
+	args := m.Called(storyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.QuizQuestion), args.Error(1)
+}
+
+func (m *MockQuizRepository) CreateQuizAttempt(attempt *model.QuizAttempt) error {
+	args := m.Called(attempt)
+	return args.Error(0)
+}
+
 var testUser = &model.User{
 	ID:           1,
 	Email:        "test@example.com",
@@ -153,3 +401,267 @@ var testStory = &model.Story{
 	Content:   "This is a test story content.",
 	WordCount: 6,
 }
+
+type MockTemplateRepository struct {
+	mock.Mock
+}
+
+func (m *MockTemplateRepository) CreateTemplate(template *model.PromptTemplate) error {
+	args := m.Called(template)
+	return args.Error(0)
+}
+
+func (m *MockTemplateRepository) GetTemplate(templateID int) (*model.PromptTemplate, error) {
+	args := m.Called(templateID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PromptTemplate), args.Error(1)
+}
+
+func (m *MockTemplateRepository) ListTemplates(userID int) ([]*model.PromptTemplate, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.PromptTemplate), args.Error(1)
+}
+
+func (m *MockTemplateRepository) UpdateTemplate(template *model.PromptTemplate) error {
+	args := m.Called(template)
+	return args.Error(0)
+}
+
+func (m *MockTemplateRepository) DeleteTemplate(templateID int) error {
+	args := m.Called(templateID)
+	return args.Error(0)
+}
+
+type MockTagRepository struct {
+	mock.Mock
+}
+
+func (m *MockTagRepository) CreateTag(tag *model.Tag) error {
+	args := m.Called(tag)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) GetUserTag(tagID, userID int) (*model.Tag, error) {
+	args := m.Called(tagID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) ListTags(userID int) ([]*model.Tag, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) RenameTag(tag *model.Tag) error {
+	args := m.Called(tag)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) DeleteTag(tagID int) error {
+	args := m.Called(tagID)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) AttachTag(storyID, tagID int) error {
+	args := m.Called(storyID, tagID)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) DetachTag(storyID, tagID int) error {
+	args := m.Called(storyID, tagID)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) GetStoryTags(storyID int) ([]*model.Tag, error) {
+	args := m.Called(storyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Tag), args.Error(1)
+}
+
+type MockShelfRepository struct {
+	mock.Mock
+}
+
+func (m *MockShelfRepository) CreateShelf(shelf *model.Shelf) error {
+	args := m.Called(shelf)
+	return args.Error(0)
+}
+
+func (m *MockShelfRepository) GetUserShelf(shelfID, userID int) (*model.Shelf, error) {
+	args := m.Called(shelfID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Shelf), args.Error(1)
+}
+
+func (m *MockShelfRepository) ListShelves(userID int) ([]*model.Shelf, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Shelf), args.Error(1)
+}
+
+func (m *MockShelfRepository) RenameShelf(shelf *model.Shelf) error {
+	args := m.Called(shelf)
+	return args.Error(0)
+}
+
+func (m *MockShelfRepository) DeleteShelf(shelfID int) error {
+	args := m.Called(shelfID)
+	return args.Error(0)
+}
+
+func (m *MockShelfRepository) GetShelfItems(shelfID int) ([]*model.ShelfItem, error) {
+	args := m.Called(shelfID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.ShelfItem), args.Error(1)
+}
+
+func (m *MockShelfRepository) AddStory(shelfID, storyID int) error {
+	args := m.Called(shelfID, storyID)
+	return args.Error(0)
+}
+
+func (m *MockShelfRepository) RemoveStory(shelfID, storyID int) error {
+	args := m.Called(shelfID, storyID)
+	return args.Error(0)
+}
+
+func (m *MockShelfRepository) ReorderStories(shelfID int, storyIDs []int) error {
+	args := m.Called(shelfID, storyIDs)
+	return args.Error(0)
+}
+
+type MockDailyStoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockDailyStoryRepository) GetPreference(userID int) (*model.DailyStoryPreference, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.DailyStoryPreference), args.Error(1)
+}
+
+func (m *MockDailyStoryRepository) UpsertPreference(preference *model.DailyStoryPreference) error {
+	args := m.Called(preference)
+	return args.Error(0)
+}
+
+func (m *MockDailyStoryRepository) ListEnabledPreferences() ([]*model.DailyStoryPreference, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.DailyStoryPreference), args.Error(1)
+}
+
+func (m *MockDailyStoryRepository) ClaimDelivery(userID int, deliveredOn time.Time) (bool, error) {
+	args := m.Called(userID, deliveredOn)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDailyStoryRepository) ReleaseDelivery(userID int, deliveredOn time.Time, previous *time.Time) error {
+	args := m.Called(userID, deliveredOn, previous)
+	return args.Error(0)
+}
+
+type MockShareRepository struct {
+	mock.Mock
+}
+
+func (m *MockShareRepository) CreateShare(share *model.StoryShare) error {
+	args := m.Called(share)
+	return args.Error(0)
+}
+
+func (m *MockShareRepository) ListShares(storyID int) ([]*model.StoryShare, error) {
+	args := m.Called(storyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.StoryShare), args.Error(1)
+}
+
+func (m *MockShareRepository) DeleteShare(shareID, storyID int) error {
+	args := m.Called(shareID, storyID)
+	return args.Error(0)
+}
+
+func (m *MockShareRepository) GetSharedStory(token string) (*model.SharedStory, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SharedStory), args.Error(1)
+}
+
+type MockCommunityRepository struct {
+	mock.Mock
+}
+
+func (m *MockCommunityRepository) PublishStory(story *model.CommunityStory) error {
+	args := m.Called(story)
+	return args.Error(0)
+}
+
+func (m *MockCommunityRepository) DeleteCommunityStory(communityStoryID, userID int) error {
+	args := m.Called(communityStoryID, userID)
+	return args.Error(0)
+}
+
+func (m *MockCommunityRepository) GetCommunityStory(communityStoryID, userID int) (*model.CommunityStory, error) {
+	args := m.Called(communityStoryID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CommunityStory), args.Error(1)
+}
+
+func (m *MockCommunityRepository) ListCommunityStories(userID int, filter repository.CommunityFilter, sort repository.CommunitySort, limit, offset int) ([]*model.CommunityStory, error) {
+	args := m.Called(userID, filter, sort, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.CommunityStory), args.Error(1)
+}
+
+func (m *MockCommunityRepository) CountCommunityStories(filter repository.CommunityFilter) (int, error) {
+	args := m.Called(filter)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCommunityRepository) LikeCommunityStory(communityStoryID, userID int) (int, error) {
+	args := m.Called(communityStoryID, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCommunityRepository) UnlikeCommunityStory(communityStoryID, userID int) (int, error) {
+	args := m.Called(communityStoryID, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCommunityRepository) CloneCommunityStory(communityStoryID, userID int) (*model.Story, error) {
+	args := m.Called(communityStoryID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Story), args.Error(1)
+}
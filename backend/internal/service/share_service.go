@@ -0,0 +1,134 @@
+package service
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
+)
+
+var (
+	ErrShareNotFound      = errors.New("share not found")
+	ErrInvalidShareExpiry = errors.New("expires_at must be in the future")
+	ErrTooManyShares      = errors.New("too many active share links")
+)
+
+const (
+	// maxActiveSharesPerStory は 1 つのストーリーで同時に有効にできる共有リンクの数
+	maxActiveSharesPerStory = 20
+	// shareTokenBytes は共有リンクのトークンの長さ (base64url で 43 文字になる)
+	shareTokenBytes = 32
+)
+
+type IShareService interface {
+	CreateShare(storyID, userID int, expiresAt *time.Time) (*model.StoryShare, error)
+	ListShares(storyID, userID int) ([]*model.StoryShare, error)
+	RevokeShare(shareID, storyID, userID int) error
+	GetSharedStory(token string) (*model.SharedStory, error)
+}
+
+type ShareService struct {
+	ShareRepo repository.IShareRepository
+	StoryRepo repository.IStoryRepository
+}
+
+func NewShareService(shareRepo repository.IShareRepository, storyRepo repository.IStoryRepository) IShareService {
+	return &ShareService{
+		ShareRepo: shareRepo,
+		StoryRepo: storyRepo,
+	}
+}
+
+// CreateShare はストーリーの共有リンクを作る。expiresAt が nil の場合は取り消すまで有効
+func (s *ShareService) CreateShare(storyID, userID int, expiresAt *time.Time) (*model.StoryShare, error) {
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, ErrInvalidShareExpiry
+	}
+	shares, err := s.ListShares(storyID, userID)
+	if err != nil {
+		return nil, err
+	}
+	active := 0
+	for _, share := range shares {
+		if share.ExpiresAt == nil || share.ExpiresAt.After(time.Now()) {
+			active++
+		}
+	}
+	if active >= maxActiveSharesPerStory {
+		return nil, ErrTooManyShares
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		return nil, err
+	}
+	share := &model.StoryShare{StoryID: storyID, Token: token, ExpiresAt: expiresAt}
+	if err := s.ShareRepo.CreateShare(share); err != nil {
+		return nil, fmt.Errorf("failed to create share: %w", err)
+	}
+	return share, nil
+}
+
+func (s *ShareService) ListShares(storyID, userID int) ([]*model.StoryShare, error) {
+	if err := s.checkStoryOwnership(storyID, userID); err != nil {
+		return nil, err
+	}
+	shares, err := s.ShareRepo.ListShares(storyID)
+	if err != nil {
+		return nil, fmt.Errorf("database error (list shares): %w", err)
+	}
+	if shares == nil {
+		shares = []*model.StoryShare{}
+	}
+	return shares, nil
+}
+
+// RevokeShare は共有リンクを取り消す。取り消した後はトークンで読めなくなる
+func (s *ShareService) RevokeShare(shareID, storyID, userID int) error {
+	if err := s.checkStoryOwnership(storyID, userID); err != nil {
+		return err
+	}
+	if err := s.ShareRepo.DeleteShare(shareID, storyID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrShareNotFound
+		}
+		return fmt.Errorf("failed to revoke share: %w", err)
+	}
+	return nil
+}
+
+// GetSharedStory は共有リンクのストーリーを返す。存在しない・取り消した・期限切れのリンクは区別せず ErrShareNotFound にする
+func (s *ShareService) GetSharedStory(token string) (*model.SharedStory, error) {
+	story, err := s.ShareRepo.GetSharedStory(token)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrShareNotFound
+		}
+		return nil, fmt.Errorf("database error (get shared story): %w", err)
+	}
+	return story, nil
+}
+
+func (s *ShareService) checkStoryOwnership(storyID, userID int) error {
+	if _, err := s.StoryRepo.GetUserStory(storyID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrStoryNotFound
+		}
+		return fmt.Errorf("database error (get story): %w", err)
+	}
+	return nil
+}
+
+// newShareToken は推測できない共有リンクのトークンを作る
+func newShareToken() (string, error) {
+	b := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate share token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
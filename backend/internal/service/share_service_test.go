@@ -0,0 +1,133 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupShareServiceTest(t *testing.T) (*MockShareRepository, *MockStoryRepository, IShareService) {
+	mockShareRepo := new(MockShareRepository)
+	mockStoryRepo := new(MockStoryRepository)
+
+	shareService := NewShareService(mockShareRepo, mockStoryRepo)
+
+	return mockShareRepo, mockStoryRepo, shareService
+}
+
+func TestShareService_CreateShare(t *testing.T) {
+	mockShareRepo, mockStoryRepo, shareService := setupShareServiceTest(t)
+
+	t.Run("success: should create a share with an unguessable token", func(t *testing.T) {
+		expiresAt := time.Now().Add(7 * 24 * time.Hour)
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Once()
+		mockShareRepo.On("ListShares", testStory.ID).Return(nil, nil).Once()
+		mockShareRepo.On("CreateShare", mock.MatchedBy(func(share *model.StoryShare) bool {
+			return share.StoryID == testStory.ID && len(share.Token) == 43 && share.ExpiresAt.Equal(expiresAt)
+		})).Return(nil).Once()
+
+		share, err := shareService.CreateShare(testStory.ID, testUser.ID, &expiresAt)
+
+		require.NoError(t, err)
+		assert.Len(t, share.Token, 43)
+		mockShareRepo.AssertExpectations(t)
+		mockStoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("success: tokens should differ between shares", func(t *testing.T) {
+		var tokens []string
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Twice()
+		mockShareRepo.On("ListShares", testStory.ID).Return(nil, nil).Twice()
+		mockShareRepo.On("CreateShare", mock.AnythingOfType("*model.StoryShare")).Run(func(args mock.Arguments) {
+			tokens = append(tokens, args.Get(0).(*model.StoryShare).Token)
+		}).Return(nil).Twice()
+
+		_, err := shareService.CreateShare(testStory.ID, testUser.ID, nil)
+		require.NoError(t, err)
+		_, err = shareService.CreateShare(testStory.ID, testUser.ID, nil)
+		require.NoError(t, err)
+
+		require.Len(t, tokens, 2)
+		assert.NotEqual(t, tokens[0], tokens[1])
+	})
+
+	t.Run("fail: should reject an expiry in the past", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+
+		_, err := shareService.CreateShare(testStory.ID, testUser.ID, &past)
+
+		assert.ErrorIs(t, err, ErrInvalidShareExpiry)
+	})
+
+	t.Run("fail: should limit the number of active shares", func(t *testing.T) {
+		expired := time.Now().Add(-time.Hour)
+		shares := []*model.StoryShare{{ID: 99, ExpiresAt: &expired}}
+		for i := 0; i < maxActiveSharesPerStory; i++ {
+			shares = append(shares, &model.StoryShare{ID: i + 1})
+		}
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Once()
+		mockShareRepo.On("ListShares", testStory.ID).Return(shares, nil).Once()
+
+		_, err := shareService.CreateShare(testStory.ID, testUser.ID, nil)
+
+		assert.ErrorIs(t, err, ErrTooManyShares)
+	})
+
+	t.Run("fail: should return ErrStoryNotFound for another user's story", func(t *testing.T) {
+		mockStoryRepo.On("GetUserStory", testStory.ID, 999).Return(nil, sql.ErrNoRows).Once()
+
+		_, err := shareService.CreateShare(testStory.ID, 999, nil)
+
+		assert.ErrorIs(t, err, ErrStoryNotFound)
+	})
+}
+
+func TestShareService_RevokeShare(t *testing.T) {
+	mockShareRepo, mockStoryRepo, shareService := setupShareServiceTest(t)
+
+	t.Run("success: should delete the share", func(t *testing.T) {
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Once()
+		mockShareRepo.On("DeleteShare", 5, testStory.ID).Return(nil).Once()
+
+		err := shareService.RevokeShare(5, testStory.ID, testUser.ID)
+
+		require.NoError(t, err)
+		mockShareRepo.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return ErrShareNotFound for a share of another story", func(t *testing.T) {
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Once()
+		mockShareRepo.On("DeleteShare", 6, testStory.ID).Return(sql.ErrNoRows).Once()
+
+		err := shareService.RevokeShare(6, testStory.ID, testUser.ID)
+
+		assert.ErrorIs(t, err, ErrShareNotFound)
+	})
+}
+
+func TestShareService_GetSharedStory(t *testing.T) {
+	mockShareRepo, _, shareService := setupShareServiceTest(t)
+
+	t.Run("success: should return the shared story", func(t *testing.T) {
+		shared := &model.SharedStory{Title: "Rivers", Content: "Heavy rain fell.", WordCount: 3}
+		mockShareRepo.On("GetSharedStory", "token").Return(shared, nil).Once()
+
+		story, err := shareService.GetSharedStory("token")
+
+		require.NoError(t, err)
+		assert.Equal(t, shared, story)
+	})
+
+	t.Run("fail: should return ErrShareNotFound for unknown or expired tokens", func(t *testing.T) {
+		mockShareRepo.On("GetSharedStory", "expired").Return(nil, sql.ErrNoRows).Once()
+
+		_, err := shareService.GetSharedStory("expired")
+
+		assert.ErrorIs(t, err, ErrShareNotFound)
+	})
+}
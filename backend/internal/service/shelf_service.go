@@ -0,0 +1,180 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
+)
+
+var (
+	ErrShelfNotFound     = errors.New("shelf not found")
+	ErrStoryNotOnShelf   = errors.New("story is not on the shelf")
+	ErrInvalidShelfOrder = errors.New("story_ids must list every story on the shelf exactly once")
+)
+
+// ShelfDetail は棚の進捗と並び順どおりのストーリー
+type ShelfDetail struct {
+	model.Shelf
+	Items []*model.ShelfItem
+}
+
+type IShelfService interface {
+	ListShelves(userID int) ([]*model.Shelf, error)
+	CreateShelf(userID int, name string) (*model.Shelf, error)
+	GetShelf(shelfID, userID int) (*ShelfDetail, error)
+	RenameShelf(shelfID, userID int, name string) (*model.Shelf, error)
+	DeleteShelf(shelfID, userID int) error
+	AddStory(shelfID, storyID, userID int) (*ShelfDetail, error)
+	RemoveStory(shelfID, storyID, userID int) error
+	ReorderStories(shelfID, userID int, storyIDs []int) (*ShelfDetail, error)
+}
+
+type ShelfService struct {
+	ShelfRepo repository.IShelfRepository
+	StoryRepo repository.IStoryRepository
+}
+
+func NewShelfService(shelfRepo repository.IShelfRepository, storyRepo repository.IStoryRepository) IShelfService {
+	return &ShelfService{
+		ShelfRepo: shelfRepo,
+		StoryRepo: storyRepo,
+	}
+}
+
+func (s *ShelfService) ListShelves(userID int) ([]*model.Shelf, error) {
+	shelves, err := s.ShelfRepo.ListShelves(userID)
+	if err != nil {
+		return nil, fmt.Errorf("database error (list shelves): %w", err)
+	}
+	if shelves == nil {
+		shelves = []*model.Shelf{}
+	}
+	return shelves, nil
+}
+
+func (s *ShelfService) CreateShelf(userID int, name string) (*model.Shelf, error) {
+	shelf := &model.Shelf{
+		UserID: userID,
+		Name:   name,
+	}
+	if err := s.ShelfRepo.CreateShelf(shelf); err != nil {
+		return nil, fmt.Errorf("failed to create shelf: %w", err)
+	}
+	return shelf, nil
+}
+
+func (s *ShelfService) GetShelf(shelfID, userID int) (*ShelfDetail, error) {
+	shelf, err := s.getUserShelf(shelfID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.ShelfRepo.GetShelfItems(shelfID)
+	if err != nil {
+		return nil, fmt.Errorf("database error (get shelf items): %w", err)
+	}
+	if items == nil {
+		items = []*model.ShelfItem{}
+	}
+
+	return &ShelfDetail{Shelf: *shelf, Items: items}, nil
+}
+
+func (s *ShelfService) RenameShelf(shelfID, userID int, name string) (*model.Shelf, error) {
+	shelf, err := s.getUserShelf(shelfID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	shelf.Name = name
+	if err := s.ShelfRepo.RenameShelf(shelf); err != nil {
+		return nil, fmt.Errorf("failed to rename shelf: %w", err)
+	}
+	return shelf, nil
+}
+
+// DeleteShelf は棚を削除する。棚に並んでいたストーリー自体は削除しない
+func (s *ShelfService) DeleteShelf(shelfID, userID int) error {
+	if _, err := s.getUserShelf(shelfID, userID); err != nil {
+		return err
+	}
+
+	if err := s.ShelfRepo.DeleteShelf(shelfID); err != nil {
+		return fmt.Errorf("failed to delete shelf: %w", err)
+	}
+	return nil
+}
+
+// AddStory はストーリーを棚の末尾に追加する。既に棚にある場合は位置を変えない
+func (s *ShelfService) AddStory(shelfID, storyID, userID int) (*ShelfDetail, error) {
+	if _, err := s.getUserShelf(shelfID, userID); err != nil {
+		return nil, err
+	}
+	if _, err := s.StoryRepo.GetUserStory(storyID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrStoryNotFound
+		}
+		return nil, fmt.Errorf("database error (get story): %w", err)
+	}
+
+	if err := s.ShelfRepo.AddStory(shelfID, storyID); err != nil {
+		return nil, fmt.Errorf("failed to add story to shelf: %w", err)
+	}
+	return s.GetShelf(shelfID, userID)
+}
+
+func (s *ShelfService) RemoveStory(shelfID, storyID, userID int) error {
+	if _, err := s.getUserShelf(shelfID, userID); err != nil {
+		return err
+	}
+
+	if err := s.ShelfRepo.RemoveStory(shelfID, storyID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrStoryNotOnShelf
+		}
+		return fmt.Errorf("failed to remove story from shelf: %w", err)
+	}
+	return nil
+}
+
+// ReorderStories は棚のストーリーを storyIDs の順に並べ替える
+func (s *ShelfService) ReorderStories(shelfID, userID int, storyIDs []int) (*ShelfDetail, error) {
+	detail, err := s.GetShelf(shelfID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make([]int, 0, len(detail.Items))
+	for _, item := range detail.Items {
+		current = append(current, item.StoryID)
+	}
+	requested := slices.Clone(storyIDs)
+	slices.Sort(current)
+	slices.Sort(requested)
+	if !slices.Equal(current, requested) {
+		return nil, ErrInvalidShelfOrder
+	}
+
+	if err := s.ShelfRepo.ReorderStories(shelfID, storyIDs); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidShelfOrder
+		}
+		return nil, fmt.Errorf("failed to reorder shelf: %w", err)
+	}
+	return s.GetShelf(shelfID, userID)
+}
+
+func (s *ShelfService) getUserShelf(shelfID, userID int) (*model.Shelf, error) {
+	shelf, err := s.ShelfRepo.GetUserShelf(shelfID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrShelfNotFound
+		}
+		return nil, fmt.Errorf("database error (get shelf): %w", err)
+	}
+	return shelf, nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupShelfServiceTest(t *testing.T) (*MockShelfRepository, *MockStoryRepository, IShelfService) {
+	mockShelfRepo := new(MockShelfRepository)
+	mockStoryRepo := new(MockStoryRepository)
+
+	shelfService := NewShelfService(mockShelfRepo, mockStoryRepo)
+
+	return mockShelfRepo, mockStoryRepo, shelfService
+}
+
+func TestShelfService_AddStory(t *testing.T) {
+	mockShelfRepo, mockStoryRepo, shelfService := setupShelfServiceTest(t)
+	shelf := &model.Shelf{ID: 1, UserID: testUser.ID, Name: "Week 1", StoryCount: 1, TotalWordCount: 100, RemainingWordCount: 100}
+
+	t.Run("success: should append the story and return the shelf", func(t *testing.T) {
+		mockShelfRepo.On("GetUserShelf", shelf.ID, testUser.ID).Return(shelf, nil).Twice()
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Once()
+		mockShelfRepo.On("AddStory", shelf.ID, testStory.ID).Return(nil).Once()
+		mockShelfRepo.On("GetShelfItems", shelf.ID).Return([]*model.ShelfItem{{Position: 1, StoryID: testStory.ID}}, nil).Once()
+
+		detail, err := shelfService.AddStory(shelf.ID, testStory.ID, testUser.ID)
+
+		require.NoError(t, err)
+		require.Len(t, detail.Items, 1)
+		assert.Equal(t, testStory.ID, detail.Items[0].StoryID)
+		mockShelfRepo.AssertExpectations(t)
+		mockStoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("fail: should reject stories of another user", func(t *testing.T) {
+		mockShelfRepo.On("GetUserShelf", shelf.ID, testUser.ID).Return(shelf, nil).Once()
+		mockStoryRepo.On("GetUserStory", 99, testUser.ID).Return(nil, sql.ErrNoRows).Once()
+
+		_, err := shelfService.AddStory(shelf.ID, 99, testUser.ID)
+
+		assert.ErrorIs(t, err, ErrStoryNotFound)
+		mockShelfRepo.AssertNumberOfCalls(t, "AddStory", 1)
+	})
+
+	t.Run("fail: should return ErrShelfNotFound for shelves of another user", func(t *testing.T) {
+		mockShelfRepo.On("GetUserShelf", 2, testUser.ID).Return(nil, sql.ErrNoRows).Once()
+
+		_, err := shelfService.AddStory(2, testStory.ID, testUser.ID)
+
+		assert.ErrorIs(t, err, ErrShelfNotFound)
+	})
+}
+
+func TestShelfService_ReorderStories(t *testing.T) {
+	mockShelfRepo, _, shelfService := setupShelfServiceTest(t)
+	shelf := &model.Shelf{ID: 1, UserID: testUser.ID, Name: "Week 1", StoryCount: 3}
+	items := []*model.ShelfItem{
+		{Position: 1, StoryID: 10},
+		{Position: 2, StoryID: 11},
+		{Position: 3, StoryID: 12},
+	}
+
+	t.Run("success: should reorder when every story is listed once", func(t *testing.T) {
+		mockShelfRepo.On("GetUserShelf", shelf.ID, testUser.ID).Return(shelf, nil).Twice()
+		mockShelfRepo.On("GetShelfItems", shelf.ID).Return(items, nil).Twice()
+		mockShelfRepo.On("ReorderStories", shelf.ID, []int{12, 10, 11}).Return(nil).Once()
+
+		_, err := shelfService.ReorderStories(shelf.ID, testUser.ID, []int{12, 10, 11})
+
+		require.NoError(t, err)
+		mockShelfRepo.AssertExpectations(t)
+	})
+
+	for name, storyIDs := range map[string][]int{
+		"missing":   {12, 10},
+		"duplicate": {12, 10, 10},
+		"unknown":   {12, 10, 99},
+	} {
+		t.Run("fail: should reject a "+name+" story id", func(t *testing.T) {
+			mockShelfRepo.On("GetUserShelf", shelf.ID, testUser.ID).Return(shelf, nil).Once()
+			mockShelfRepo.On("GetShelfItems", shelf.ID).Return(items, nil).Once()
+
+			_, err := shelfService.ReorderStories(shelf.ID, testUser.ID, storyIDs)
+
+			assert.ErrorIs(t, err, ErrInvalidShelfOrder)
+			mockShelfRepo.AssertNumberOfCalls(t, "ReorderStories", 1)
+		})
+	}
+}
+
+func TestShelfService_RemoveStory(t *testing.T) {
+	mockShelfRepo, _, shelfService := setupShelfServiceTest(t)
+	shelf := &model.Shelf{ID: 1, UserID: testUser.ID, Name: "Week 1"}
+
+	t.Run("fail: should report stories that are not on the shelf", func(t *testing.T) {
+		mockShelfRepo.On("GetUserShelf", shelf.ID, testUser.ID).Return(shelf, nil).Once()
+		mockShelfRepo.On("RemoveStory", shelf.ID, 99).Return(sql.ErrNoRows).Once()
+
+		err := shelfService.RemoveStory(shelf.ID, 99, testUser.ID)
+
+		assert.ErrorIs(t, err, ErrStoryNotOnShelf)
+	})
+}
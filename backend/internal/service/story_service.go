@@ -16,16 +16,47 @@ import (
 
 // PaginatedStories はサービス層が返すページネーション結果のモデル
 type PaginatedStories struct {
-	Stories     []*model.Story
+	Stories     []*model.StoryListItem
 	TotalCount  int
 	TotalPages  int
 	CurrentPage int
+	// NextCursor / PrevCursor はキーセットページネーションで前後のページを取得するためのカーソル
+	NextCursor *string
+	PrevCursor *string
 }
 
 // StoryDetail はサービス層が返すストーリー詳細のモデル
 type StoryDetail struct {
 	model.Story
-	ReadCount int
+	ReadCount    int
+	Series       *SeriesNavigation
+	Derivatives  []*model.StoryVariant
+	ParallelText []ParallelParagraph
+	Tags         []*model.Tag
+}
+
+// ParallelParagraph は英語の段落と対応する日本語訳の組
+type ParallelParagraph struct {
+	English  string `json:"en"`
+	Japanese string `json:"ja"`
+}
+
+// SeriesNavigation は連載に属するストーリーの前後の章と連載全体の情報
+type SeriesNavigation struct {
+	SeriesID       int    `json:"series_id"`
+	Title          string `json:"title"`
+	ChapterNumber  int    `json:"chapter_number"`
+	ChapterCount   int    `json:"chapter_count"`
+	PrevStoryID    *int   `json:"prev_story_id"`
+	NextStoryID    *int   `json:"next_story_id"`
+	TotalWordCount int    `json:"total_word_count"`
+}
+
+// SeriesDetail はサービス層が返す連載詳細のモデル
+type SeriesDetail struct {
+	model.Series
+	Chapters       []*model.SeriesChapter
+	TotalWordCount int
 }
 
 // サービス層で扱うためのドメインエラーを定義
@@ -34,71 +65,124 @@ var (
 	ErrForbidden               = errors.New("forbidden")
 	ErrNoReadingRecord         = errors.New("no reading record found")
 	ErrGenerationLimitExceeded = errors.New("generation limit exceeded")
+	ErrSeriesNotFound          = errors.New("series not found")
+	ErrAlreadyAtLevel          = errors.New("story is already at the requested level")
+	ErrTranslationMisaligned   = errors.New("translation is not aligned with the story paragraphs")
+	ErrInvalidBulkAction       = errors.New("invalid bulk action")
+	ErrRevisionNotFound        = errors.New("revision not found")
+	ErrChapterAlreadyExists    = errors.New("chapter already exists")
 )
 
 type IStoryService interface {
-	GenerateStory(userID int, prompt string) (*model.Story, error)
-	GetStories(userID int, page, limit int) (*PaginatedStories, error)
+	GenerateStory(userID int, input GenerateStoryInput) (*model.Story, error)
+	GetStories(userID int, query StoryListQuery) (*PaginatedStories, error)
 	GetStory(storyID, userID int) (*StoryDetail, error)
 	DeleteStory(storyID, userID int) error
+	GetTrash(userID int) ([]*model.Story, error)
+	RestoreStory(storyID, userID int) (*model.Story, error)
+	PurgeTrash(before time.Time) (int64, error)
+	BulkUpdateStories(userID int, op repository.BulkOperation) ([]*model.BulkStoryResult, error)
 	UpdateStoryTitle(storyID, userID int, newTitle string) (*model.Story, error)
+	UpdateStoryContent(storyID, userID int, title *string, content string) (*model.Story, error)
+	GetRevisions(storyID, userID int) ([]*model.StoryRevision, error)
+	GetRevisionDiff(storyID, userID, revisionNumber int) (*RevisionDiff, error)
+	RevertStory(storyID, userID, revisionNumber int) (*model.Story, error)
+	GetDuplicateClusters(userID int) ([]*model.DuplicateCluster, error)
+	BackfillSimhashes() (int, error)
 	MarkStoryAsRead(storyID, userID int) error
+	SetFavorite(storyID, userID int, favorite bool) error
 	UndoLastRead(storyID, userID int) error
+	ContinueStory(storyID, userID int) (*model.Story, error)
+	GetSeries(seriesID, userID int) (*SeriesDetail, error)
+	RewriteStory(storyID, userID int, level string) (*model.Story, error)
+	TranslateStory(storyID, userID int) ([]ParallelParagraph, error)
+}
+
+// StoryListQuery はストーリー一覧の取得条件
+type StoryListQuery struct {
+	repository.StoryFilter
+	Sort  repository.StorySort
+	Page  int
+	Limit int
+	// Cursor が指定された場合は Page を無視してキーセットページネーションで取得する
+	Cursor string
+}
+
+// keysetKind はキーセットページネーションに対応する並び順の場合にカーソルの種類を返す
+func (q StoryListQuery) keysetKind() (string, bool) {
+	switch {
+	case q.Sort == repository.StorySortNewest, q.Sort == repository.StorySortDefault && q.Query == "":
+		return "stories:newest", true
+	case q.Sort == repository.StorySortOldest:
+		return "stories:oldest", true
+	}
+	return "", false
+}
+
+// RevisionDiff は過去の版と現在の本文の差分
+type RevisionDiff struct {
+	Revision *model.StoryRevision
+	Changes  []DiffChange
+}
+
+// GenerateStoryInput は文章生成の入力。Level と WordCount は省略可能
+type GenerateStoryInput struct {
+	Prompt    string
+	Level     string
+	WordCount int
 }
 
 type StoryService struct {
 	StoryRepo         repository.IStoryRepository
+	SeriesRepo        repository.ISeriesRepository
+	TranslationRepo   repository.ITranslationRepository
 	ReadingRecordRepo repository.IReadingRecordRepository
 	UserRepo          repository.IUserRepository
+	TagRepo           repository.ITagRepository
 	LLMService        ILLMService // llm_service.go に依存
 	DailyLimit        int
 }
 
-func NewStoryService(storyRepo repository.IStoryRepository, readingRecordRepo repository.IReadingRecordRepository, userRepo repository.IUserRepository, llmService ILLMService, dailyLimit int) IStoryService {
+func NewStoryService(storyRepo repository.IStoryRepository, seriesRepo repository.ISeriesRepository, translationRepo repository.ITranslationRepository, readingRecordRepo repository.IReadingRecordRepository, userRepo repository.IUserRepository, tagRepo repository.ITagRepository, llmService ILLMService, dailyLimit int) IStoryService {
 	return &StoryService{
 		StoryRepo:         storyRepo,
+		SeriesRepo:        seriesRepo,
+		TranslationRepo:   translationRepo,
 		ReadingRecordRepo: readingRecordRepo,
 		UserRepo:          userRepo,
+		TagRepo:           tagRepo,
 		LLMService:        llmService,
 		DailyLimit:        dailyLimit,
 	}
 }
 
-func (s *StoryService) GenerateStory(userID int, prompt string) (*model.Story, error) {
+func (s *StoryService) GenerateStory(userID int, input GenerateStoryInput) (*model.Story, error) {
 
 	// ユーザーの生成制限を確認
-	user, err := s.UserRepo.GetUserByID(userID)
+	currentCount, now, err := checkGenerationLimit(s.UserRepo, s.DailyLimit, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user for validation: %w", err)
-	}
-
-	now := timeutil.NowTokyo()
-	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, timeutil.Tokyo())
-
-	currentCount := user.GenerationCount
-	lastGen := user.LastGenerationAt
-
-	if lastGen != nil && lastGen.In(timeutil.Tokyo()).Before(todayStart) {
-		currentCount = 0
-	}
-
-	if currentCount >= s.DailyLimit {
-		return nil, ErrGenerationLimitExceeded
+		return nil, err
 	}
 
 	// LLMサービス呼び出し
-	content, err := s.LLMService.GenerateStory(prompt)
+	content, err := s.LLMService.GenerateStory(input.Prompt, GenerationOptions{
+		Level:     input.Level,
+		WordCount: input.WordCount,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate story: %w", err)
 	}
 
-	wordCount := len(strings.Fields(content))
-
+	// テンプレートから作ったプロンプトは長くなりやすいため、タイトルの列に収まるよう切り詰める
 	story := &model.Story{
 		UserID:    userID,
-		Title:     prompt,
+		Title:     truncateRunes(strings.TrimSpace(input.Prompt), maxImportTitleLength),
 		Content:   content,
-		WordCount: wordCount,
+		WordCount: countWords(content),
+	}
+	if input.Level != "" {
+		level := input.Level
+		story.Level = &level
 	}
 
 	// DB保存
@@ -106,35 +190,88 @@ func (s *StoryService) GenerateStory(userID int, prompt string) (*model.Story, e
 		return nil, fmt.Errorf("failed to save story: %w", err)
 	}
 
+	recordGeneration(s.UserRepo, userID, currentCount, now)
+	attachSimilarStories(s.StoryRepo, userID, story)
+
+	return story, nil
+}
+
+// checkGenerationLimit は本日の生成回数を返す。上限に達している場合は ErrGenerationLimitExceeded を返す。
+// LLM を呼び出す操作 (文章の生成・読解問題の生成など) はすべて同じ回数に数える
+func checkGenerationLimit(userRepo repository.IUserRepository, dailyLimit, userID int) (int, time.Time, error) {
+	user, err := userRepo.GetUserByID(userID)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to get user for validation: %w", err)
+	}
+
+	now := timeutil.NowTokyo()
+	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, timeutil.Tokyo())
+
+	currentCount := user.GenerationCount
+	lastGen := user.LastGenerationAt
+
+	if lastGen != nil && lastGen.In(timeutil.Tokyo()).Before(todayStart) {
+		currentCount = 0
+	}
+
+	if currentCount >= dailyLimit {
+		return 0, time.Time{}, ErrGenerationLimitExceeded
+	}
+
+	return currentCount, now, nil
+}
+
+// recordGeneration は生成回数を 1 増やす。失敗しても生成結果は保存済みなのでログのみ残す
+func recordGeneration(userRepo repository.IUserRepository, userID, currentCount int, now time.Time) {
 	newCount := currentCount + 1
-	if err := s.UserRepo.UpdateGenerationStatus(userID, newCount, now); err != nil {
+	if err := userRepo.UpdateGenerationStatus(userID, newCount, now); err != nil {
 		log.Printf("WARNING: failed to update generation status for user %d: %v", userID, err)
 	}
+}
 
-	return story, nil
+func countWords(content string) int {
+	return len(strings.Fields(content))
+}
+
+// splitParagraphs は Markdown の本文を空行区切りの段落に分割する
+func splitParagraphs(content string) []string {
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+
+	var paragraphs []string
+	var current []string
+	for _, line := range strings.Split(normalized, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(current) > 0 {
+				paragraphs = append(paragraphs, strings.Join(current, "\n"))
+				current = nil
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		paragraphs = append(paragraphs, strings.Join(current, "\n"))
+	}
+	return paragraphs
 }
 
-func (s *StoryService) GetStories(userID int, page, limit int) (*PaginatedStories, error) {
+func (s *StoryService) GetStories(userID int, query StoryListQuery) (*PaginatedStories, error) {
+	page, limit := query.Page, query.Limit
 	if page <= 0 {
 		page = 1
 	}
 	if limit <= 0 {
 		limit = 10
 	}
-	offset := (page - 1) * limit
 
-	totalCount, err := s.StoryRepo.CountUserStories(userID)
-	if err != nil {
-		return nil, fmt.Errorf("database error (count): %w", err)
+	kind, keysetSupported := query.keysetKind()
+	if query.Cursor != "" && !keysetSupported {
+		return nil, ErrCursorNotSupported
 	}
 
-	stories, err := s.StoryRepo.GetUserStories(userID, limit, offset)
+	totalCount, err := s.StoryRepo.CountUserStories(userID, query.StoryFilter)
 	if err != nil {
-		return nil, fmt.Errorf("database error (get): %w", err)
-	}
-
-	if stories == nil {
-		stories = []*model.Story{}
+		return nil, fmt.Errorf("database error (count): %w", err)
 	}
 
 	// ページネーション計算
@@ -143,6 +280,20 @@ func (s *StoryService) GetStories(userID int, page, limit int) (*PaginatedStorie
 		totalPages = int(math.Ceil(float64(totalCount) / float64(limit)))
 	}
 
+	if query.Cursor != "" {
+		return s.getStoriesByCursor(userID, query, kind, limit, totalCount, totalPages)
+	}
+
+	offset := (page - 1) * limit
+	stories, err := s.StoryRepo.GetUserStories(userID, query.StoryFilter, query.Sort, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("database error (get): %w", err)
+	}
+
+	if stories == nil {
+		stories = []*model.StoryListItem{}
+	}
+
 	res := &PaginatedStories{
 		Stories:     stories,
 		TotalCount:  totalCount,
@@ -150,9 +301,50 @@ func (s *StoryService) GetStories(userID int, page, limit int) (*PaginatedStorie
 		CurrentPage: page,
 	}
 
+	// ページ番号で取得した場合も、以降をカーソルで辿れるようにする
+	if keysetSupported && len(stories) > 0 {
+		if offset+len(stories) < totalCount {
+			last := stories[len(stories)-1]
+			res.NextCursor = encodeCursor(kind, last.CreatedAt, last.ID, false)
+		}
+		if page > 1 {
+			first := stories[0]
+			res.PrevCursor = encodeCursor(kind, first.CreatedAt, first.ID, true)
+		}
+	}
+
 	return res, nil
 }
 
+// getStoriesByCursor はキーセットページネーションで一覧を取得する。CurrentPage は 0 を返す
+func (s *StoryService) getStoriesByCursor(userID int, query StoryListQuery, kind string, limit, totalCount, totalPages int) (*PaginatedStories, error) {
+	keyset, err := decodeCursor(kind, query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// 続きのページの有無を判定するため 1 件多く取得する
+	stories, err := s.StoryRepo.GetUserStoriesByKeyset(userID, query.StoryFilter, query.Sort, keyset, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("database error (get by keyset): %w", err)
+	}
+
+	stories, next, prev := paginateKeyset(stories, limit, keyset, kind, func(story *model.StoryListItem) (time.Time, int) {
+		return story.CreatedAt, story.ID
+	})
+	if stories == nil {
+		stories = []*model.StoryListItem{}
+	}
+
+	return &PaginatedStories{
+		Stories:    stories,
+		TotalCount: totalCount,
+		TotalPages: totalPages,
+		NextCursor: next,
+		PrevCursor: prev,
+	}, nil
+}
+
 func (s *StoryService) GetStory(storyID, userID int) (*StoryDetail, error) {
 	story, err := s.StoryRepo.GetUserStory(storyID, userID)
 	if err != nil {
@@ -167,14 +359,85 @@ func (s *StoryService) GetStory(storyID, userID int) (*StoryDetail, error) {
 		return nil, fmt.Errorf("failed to get reading count: %w", err)
 	}
 
+	derivatives, err := s.StoryRepo.GetDerivedStories(storyID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("database error (get derived stories): %w", err)
+	}
+	if derivatives == nil {
+		derivatives = []*model.StoryVariant{}
+	}
+
+	parallelText, err := s.getParallelText(story)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := s.TagRepo.GetStoryTags(storyID)
+	if err != nil {
+		return nil, fmt.Errorf("database error (get story tags): %w", err)
+	}
+	if tags == nil {
+		tags = []*model.Tag{}
+	}
+
 	res := &StoryDetail{
-		Story:     *story,
-		ReadCount: readCount,
+		Story:        *story,
+		ReadCount:    readCount,
+		Derivatives:  derivatives,
+		ParallelText: parallelText,
+		Tags:         tags,
+	}
+
+	if story.SeriesID != nil {
+		nav, err := s.buildSeriesNavigation(story, userID)
+		if err != nil {
+			return nil, err
+		}
+		res.Series = nav
 	}
 
 	return res, nil
 }
 
+func (s *StoryService) buildSeriesNavigation(story *model.Story, userID int) (*SeriesNavigation, error) {
+	series, err := s.SeriesRepo.GetUserSeries(*story.SeriesID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("database error (get series): %w", err)
+	}
+
+	chapters, err := s.SeriesRepo.GetSeriesChapters(series.ID)
+	if err != nil {
+		return nil, fmt.Errorf("database error (get series chapters): %w", err)
+	}
+
+	nav := &SeriesNavigation{
+		SeriesID:     series.ID,
+		Title:        series.Title,
+		ChapterCount: len(chapters),
+	}
+	if story.ChapterNumber != nil {
+		nav.ChapterNumber = *story.ChapterNumber
+	}
+
+	// 章番号は欠番がありうるため、並び順で前後の章を決める
+	for i, chapter := range chapters {
+		nav.TotalWordCount += chapter.WordCount
+		if chapter.StoryID != story.ID {
+			continue
+		}
+		if i > 0 {
+			prevID := chapters[i-1].StoryID
+			nav.PrevStoryID = &prevID
+		}
+		if i < len(chapters)-1 {
+			nextID := chapters[i+1].StoryID
+			nav.NextStoryID = &nextID
+		}
+	}
+
+	return nav, nil
+}
+
 func (s *StoryService) checkStoryOwnership(storyID, userID int) (*model.Story, error) {
 	story, err := s.StoryRepo.GetUserStory(storyID, userID)
 	if err != nil {
@@ -190,6 +453,7 @@ func (s *StoryService) checkStoryOwnership(storyID, userID int) (*model.Story, e
 	return story, nil
 }
 
+// DeleteStory はストーリーをゴミ箱に移す。保持期間内は RestoreStory で元に戻せる
 func (s *StoryService) DeleteStory(storyID, userID int) error {
 	_, err := s.checkStoryOwnership(storyID, userID)
 	if err != nil {
@@ -203,6 +467,73 @@ func (s *StoryService) DeleteStory(storyID, userID int) error {
 	return nil
 }
 
+func (s *StoryService) GetTrash(userID int) ([]*model.Story, error) {
+	stories, err := s.StoryRepo.GetTrashedStories(userID)
+	if err != nil {
+		return nil, fmt.Errorf("database error (get trashed stories): %w", err)
+	}
+	if stories == nil {
+		stories = []*model.Story{}
+	}
+	return stories, nil
+}
+
+func (s *StoryService) RestoreStory(storyID, userID int) (*model.Story, error) {
+	story, err := s.StoryRepo.RestoreStory(storyID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrStoryNotFound
+		}
+		return nil, fmt.Errorf("failed to restore story: %w", err)
+	}
+	return story, nil
+}
+
+// PurgeTrash は before より前にゴミ箱に移したストーリーを物理削除する
+func (s *StoryService) PurgeTrash(before time.Time) (int64, error) {
+	purged, err := s.StoryRepo.PurgeDeletedStories(before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trash: %w", err)
+	}
+	return purged, nil
+}
+
+// GetDuplicateClusters は本文がほぼ同じストーリーのまとまりを返す。整理のため、ゴミ箱のストーリーは含めない
+func (s *StoryService) GetDuplicateClusters(userID int) ([]*model.DuplicateCluster, error) {
+	fingerprints, err := s.StoryRepo.ListStoryFingerprints(userID)
+	if err != nil {
+		return nil, fmt.Errorf("database error (list fingerprints): %w", err)
+	}
+	return clusterDuplicates(fingerprints), nil
+}
+
+// BackfillSimhashes は SimHash を計算していないストーリーを一定数ずつ計算し、件数を返す
+func (s *StoryService) BackfillSimhashes() (int, error) {
+	filled, err := s.StoryRepo.BackfillSimhashes(simhashBackfillBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to backfill simhashes: %w", err)
+	}
+	return filled, nil
+}
+
+// BulkUpdateStories は複数のストーリーに同じ操作をまとめて適用し、ストーリーごとの結果を返す
+func (s *StoryService) BulkUpdateStories(userID int, op repository.BulkOperation) ([]*model.BulkStoryResult, error) {
+	if !op.Action.IsValid() {
+		return nil, ErrInvalidBulkAction
+	}
+	results, err := s.StoryRepo.BulkUpdateStories(userID, op)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			if op.Action == repository.BulkActionMoveToShelf {
+				return nil, ErrShelfNotFound
+			}
+			return nil, ErrTagNotFound
+		}
+		return nil, fmt.Errorf("failed to apply bulk operation: %w", err)
+	}
+	return results, nil
+}
+
 func (s *StoryService) UpdateStoryTitle(storyID, userID int, newTitle string) (*model.Story, error) {
 	updatedStory, err := s.StoryRepo.UpdateStoryTitle(storyID, userID, newTitle)
 	if err != nil {
@@ -215,6 +546,89 @@ func (s *StoryService) UpdateStoryTitle(storyID, userID int, newTitle string) (*
 	return updatedStory, nil
 }
 
+// UpdateStoryContent は本文 (と指定があればタイトル) を更新し、語数を数え直す。編集前の内容は版として保存する
+func (s *StoryService) UpdateStoryContent(storyID, userID int, title *string, content string) (*model.Story, error) {
+	story, err := s.checkStoryOwnership(storyID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	newTitle := story.Title
+	if title != nil {
+		newTitle = *title
+	}
+	return s.saveStoryContent(story, newTitle, content)
+}
+
+// saveStoryContent は内容が変わる場合のみ版を残して更新する
+func (s *StoryService) saveStoryContent(story *model.Story, title, content string) (*model.Story, error) {
+	if title == story.Title && content == story.Content {
+		return story, nil
+	}
+
+	updatedStory, err := s.StoryRepo.UpdateStoryContent(story.ID, story.UserID, title, content, countWords(content))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrStoryNotFound
+		}
+		return nil, fmt.Errorf("failed to update story content: %w", err)
+	}
+	return updatedStory, nil
+}
+
+func (s *StoryService) GetRevisions(storyID, userID int) ([]*model.StoryRevision, error) {
+	if _, err := s.checkStoryOwnership(storyID, userID); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.StoryRepo.GetStoryRevisions(storyID)
+	if err != nil {
+		return nil, fmt.Errorf("database error (get revisions): %w", err)
+	}
+	if revisions == nil {
+		revisions = []*model.StoryRevision{}
+	}
+	return revisions, nil
+}
+
+// GetRevisionDiff は指定した版から現在の本文への差分を返す
+func (s *StoryService) GetRevisionDiff(storyID, userID, revisionNumber int) (*RevisionDiff, error) {
+	story, revision, err := s.getRevision(storyID, userID, revisionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RevisionDiff{
+		Revision: revision,
+		Changes:  diffWords(revision.Content, story.Content),
+	}, nil
+}
+
+// RevertStory は指定した版の内容に戻す。戻す前の内容も新しい版として残るため、取り消しもできる
+func (s *StoryService) RevertStory(storyID, userID, revisionNumber int) (*model.Story, error) {
+	story, revision, err := s.getRevision(storyID, userID, revisionNumber)
+	if err != nil {
+		return nil, err
+	}
+	return s.saveStoryContent(story, revision.Title, revision.Content)
+}
+
+func (s *StoryService) getRevision(storyID, userID, revisionNumber int) (*model.Story, *model.StoryRevision, error) {
+	story, err := s.checkStoryOwnership(storyID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	revision, err := s.StoryRepo.GetStoryRevision(storyID, revisionNumber)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, ErrRevisionNotFound
+		}
+		return nil, nil, fmt.Errorf("database error (get revision): %w", err)
+	}
+	return story, revision, nil
+}
+
 func (s *StoryService) MarkStoryAsRead(storyID, userID int) error {
 	// ストーリーの存在と所有権を確認
 	story, err := s.checkStoryOwnership(storyID, userID)
@@ -229,6 +643,18 @@ func (s *StoryService) MarkStoryAsRead(storyID, userID int) error {
 	return nil
 }
 
+// SetFavorite はストーリーをお気に入りに登録・解除する。既に同じ状態でもエラーにしない
+func (s *StoryService) SetFavorite(storyID, userID int, favorite bool) error {
+	err := s.StoryRepo.SetFavorite(storyID, userID, favorite)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrStoryNotFound
+		}
+		return fmt.Errorf("failed to set favorite: %w", err)
+	}
+	return nil
+}
+
 func (s *StoryService) UndoLastRead(storyID, userID int) error {
 	// 最新の読書記録を取得
 	latestRecord, err := s.ReadingRecordRepo.GetLatestReadingRecord(userID, storyID)
@@ -247,3 +673,255 @@ func (s *StoryService) UndoLastRead(storyID, userID int) error {
 
 	return nil
 }
+
+// ContinueStory は指定したストーリーが属する連載の次の章を生成する。
+// 連載に属していないストーリーの場合は、そのストーリーを第 1 章とする連載を新たに作成する。
+func (s *StoryService) ContinueStory(storyID, userID int) (*model.Story, error) {
+	story, err := s.checkStoryOwnership(storyID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	currentCount, now, err := checkGenerationLimit(s.UserRepo, s.DailyLimit, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	series, err := s.ensureSeries(story)
+	if err != nil {
+		return nil, err
+	}
+
+	chapters, err := s.SeriesRepo.GetSeriesChapters(series.ID)
+	if err != nil {
+		return nil, fmt.Errorf("database error (get series chapters): %w", err)
+	}
+	if len(chapters) == 0 {
+		return nil, ErrStoryNotFound
+	}
+
+	// まだ要約に取り込まれていない章を要約に反映する
+	summary := series.Summary
+	for _, chapter := range chapters {
+		if chapter.ChapterNumber <= series.SummarizedThrough {
+			continue
+		}
+		chapterStory, err := s.StoryRepo.GetUserStory(chapter.StoryID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("database error (get chapter): %w", err)
+		}
+		summary, err = s.LLMService.SummarizeChapter(summary, chapterStory.Content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to summarize chapter: %w", err)
+		}
+	}
+
+	lastChapterNumber := chapters[len(chapters)-1].ChapterNumber
+	if lastChapterNumber > series.SummarizedThrough {
+		if err := s.SeriesRepo.UpdateSeriesSummary(series.ID, summary, lastChapterNumber); err != nil {
+			return nil, fmt.Errorf("failed to update series summary: %w", err)
+		}
+	}
+
+	// ゴミ箱の章と番号が重ならないよう、ゴミ箱を含めた最大の章番号の次にする
+	lastNumber, err := s.SeriesRepo.GetLastChapterNumber(series.ID)
+	if err != nil {
+		return nil, fmt.Errorf("database error (get last chapter number): %w", err)
+	}
+	nextChapterNumber := lastNumber + 1
+	content, err := s.LLMService.ContinueStory(series.Title, summary, nextChapterNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate next chapter: %w", err)
+	}
+
+	// 連載のタイトルは元のストーリーのタイトル (最大 255 文字) のため、章番号を付けても列に収まるよう先に切り詰める
+	seriesID := series.ID
+	next := &model.Story{
+		UserID:        userID,
+		Title:         fmt.Sprintf("%s (Chapter %d)", truncateRunes(series.Title, maxImportTitleLength), nextChapterNumber),
+		Content:       content,
+		WordCount:     countWords(content),
+		SeriesID:      &seriesID,
+		ChapterNumber: &nextChapterNumber,
+	}
+
+	if err := s.StoryRepo.CreateStory(next); err != nil {
+		// 同じ連載の続きを同時に生成した場合は、先に保存した方を残す
+		if errors.Is(err, repository.ErrChapterAlreadyExists) {
+			return nil, ErrChapterAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to save story: %w", err)
+	}
+
+	recordGeneration(s.UserRepo, userID, currentCount, now)
+
+	return next, nil
+}
+
+// ensureSeries はストーリーが属する連載を返す。未所属の場合は連載を作成して第 1 章として登録する
+func (s *StoryService) ensureSeries(story *model.Story) (*model.Series, error) {
+	if story.SeriesID != nil {
+		series, err := s.SeriesRepo.GetUserSeries(*story.SeriesID, story.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("database error (get series): %w", err)
+		}
+		return series, nil
+	}
+
+	series := &model.Series{
+		UserID: story.UserID,
+		Title:  story.Title,
+	}
+	if err := s.SeriesRepo.CreateSeries(series); err != nil {
+		return nil, fmt.Errorf("failed to create series: %w", err)
+	}
+
+	if err := s.StoryRepo.SetStorySeries(story.ID, series.ID, 1); err != nil {
+		return nil, fmt.Errorf("failed to attach story to series: %w", err)
+	}
+
+	return series, nil
+}
+
+func (s *StoryService) GetSeries(seriesID, userID int) (*SeriesDetail, error) {
+	series, err := s.SeriesRepo.GetUserSeries(seriesID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSeriesNotFound
+		}
+		return nil, fmt.Errorf("database error (get series): %w", err)
+	}
+
+	chapters, err := s.SeriesRepo.GetSeriesChapters(series.ID)
+	if err != nil {
+		return nil, fmt.Errorf("database error (get series chapters): %w", err)
+	}
+	if chapters == nil {
+		chapters = []*model.SeriesChapter{}
+	}
+
+	totalWordCount := 0
+	for _, chapter := range chapters {
+		totalWordCount += chapter.WordCount
+	}
+
+	res := &SeriesDetail{
+		Series:         *series,
+		Chapters:       chapters,
+		TotalWordCount: totalWordCount,
+	}
+
+	return res, nil
+}
+
+// RewriteStory は既存のストーリーを指定したレベルに書き換え、元のストーリーに紐付いた別のストーリーとして保存する
+func (s *StoryService) RewriteStory(storyID, userID int, level string) (*model.Story, error) {
+	story, err := s.checkStoryOwnership(storyID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	currentLevel := ""
+	if story.Level != nil {
+		currentLevel = *story.Level
+	}
+	if currentLevel == level {
+		return nil, ErrAlreadyAtLevel
+	}
+
+	currentCount, now, err := checkGenerationLimit(s.UserRepo, s.DailyLimit, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := s.LLMService.RewriteStory(story.Content, currentLevel, level)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewrite story: %w", err)
+	}
+
+	// 元のタイトルは最大 255 文字のため、レベルを付けても列に収まるよう先に切り詰める
+	parentID := story.ID
+	rewritten := &model.Story{
+		UserID:        userID,
+		Title:         fmt.Sprintf("%s [%s]", truncateRunes(story.Title, maxImportTitleLength), level),
+		Content:       content,
+		WordCount:     countWords(content),
+		Level:         &level,
+		ParentStoryID: &parentID,
+	}
+
+	if err := s.StoryRepo.CreateStory(rewritten); err != nil {
+		return nil, fmt.Errorf("failed to save story: %w", err)
+	}
+
+	recordGeneration(s.UserRepo, userID, currentCount, now)
+
+	return rewritten, nil
+}
+
+// TranslateStory は本文の日本語訳を段落単位で生成して保存する。既存の翻訳は置き換えられる。
+// 翻訳は本文とは別に保存されるため、ストーリーの語数や読了語数には影響しない。文章の生成と同じ 1 日の生成回数に数える。
+func (s *StoryService) TranslateStory(storyID, userID int) ([]ParallelParagraph, error) {
+	story, err := s.checkStoryOwnership(storyID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	paragraphs := splitParagraphs(story.Content)
+	if len(paragraphs) == 0 {
+		return []ParallelParagraph{}, nil
+	}
+
+	currentCount, now, err := checkGenerationLimit(s.UserRepo, s.DailyLimit, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	translated, err := s.LLMService.TranslateParagraphs(paragraphs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to translate story: %w", err)
+	}
+	if len(translated) != len(paragraphs) {
+		return nil, ErrTranslationMisaligned
+	}
+
+	translation := &model.StoryTranslation{
+		StoryID:    story.ID,
+		Language:   model.TranslationLanguageJapanese,
+		Paragraphs: translated,
+	}
+	if err := s.TranslationRepo.UpsertTranslation(translation); err != nil {
+		return nil, fmt.Errorf("failed to save translation: %w", err)
+	}
+
+	recordGeneration(s.UserRepo, userID, currentCount, now)
+
+	return alignParagraphs(paragraphs, translated), nil
+}
+
+// getParallelText は保存済みの翻訳を本文の段落と組にして返す。翻訳がない場合は nil を返す
+func (s *StoryService) getParallelText(story *model.Story) ([]ParallelParagraph, error) {
+	translation, err := s.TranslationRepo.GetTranslation(story.ID, model.TranslationLanguageJapanese)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("database error (get translation): %w", err)
+	}
+
+	paragraphs := splitParagraphs(story.Content)
+	if len(paragraphs) != len(translation.Paragraphs) {
+		// 翻訳後に本文が変わった場合など、対応が取れない翻訳は返さない
+		return nil, nil
+	}
+
+	return alignParagraphs(paragraphs, translation.Paragraphs), nil
+}
+
+func alignParagraphs(english, japanese []string) []ParallelParagraph {
+	pairs := make([]ParallelParagraph, len(english))
+	for i := range english {
+		pairs[i] = ParallelParagraph{English: english[i], Japanese: japanese[i]}
+	}
+	return pairs
+}
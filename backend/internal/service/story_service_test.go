@@ -2,9 +2,12 @@ package service
 
 import (
 	"database/sql"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
 	"github.com/shuheikomatsuki/readoku/backend/internal/timeutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -12,20 +15,23 @@ import (
 )
 
 // --- 共通セットアップ ---
-func setupStoryServiceTest(t *testing.T) (*MockStoryRepository, *MockReadingRecordRepository, *MockUserRepository, *MockLLMService, IStoryService) {
+func setupStoryServiceTest(t *testing.T) (*MockStoryRepository, *MockSeriesRepository, *MockTranslationRepository, *MockReadingRecordRepository, *MockUserRepository, *MockTagRepository, *MockLLMService, IStoryService) {
 	mockStoryRepo := new(MockStoryRepository)
+	mockSeriesRepo := new(MockSeriesRepository)
+	mockTranslationRepo := new(MockTranslationRepository)
 	mockReadingRepo := new(MockReadingRecordRepository)
 	mockUserRepo := new(MockUserRepository)
+	mockTagRepo := new(MockTagRepository)
 	mockLLM := new(MockLLMService)
 
-	storyService := NewStoryService(mockStoryRepo, mockReadingRepo, mockUserRepo, mockLLM, testDailyLimit)
+	storyService := NewStoryService(mockStoryRepo, mockSeriesRepo, mockTranslationRepo, mockReadingRepo, mockUserRepo, mockTagRepo, mockLLM, testDailyLimit)
 
-	return mockStoryRepo, mockReadingRepo, mockUserRepo, mockLLM, storyService
+	return mockStoryRepo, mockSeriesRepo, mockTranslationRepo, mockReadingRepo, mockUserRepo, mockTagRepo, mockLLM, storyService
 }
 
 func TestStoryService_GenerateStory(t *testing.T) {
 	// セットアップヘルパーを使用
-	mockStoryRepo, _, mockUserRepo, mockLLM, storyService := setupStoryServiceTest(t)
+	mockStoryRepo, _, _, _, mockUserRepo, _, mockLLM, storyService := setupStoryServiceTest(t)
 
 	// testUser (service_test.go で定義) をコピー
 	baseUser := *testUser
@@ -44,15 +50,16 @@ func TestStoryService_GenerateStory(t *testing.T) {
 		mockUserRepo.On("GetUserByID", testUser.ID).Return(&userState, nil).Once()
 
 		// LLM サービスが呼ばれる
-		mockLLM.On("GenerateStory", prompt).Return(generatedContent, nil).Once()
+		mockLLM.On("GenerateStory", prompt, GenerationOptions{}).Return(generatedContent, nil).Once()
 
 		// StoryRepo が呼ばれる (内容は変更なし)
 		mockStoryRepo.On("CreateStory", mock.AnythingOfType("*model.Story")).Return(nil).Once()
+		mockStoryRepo.On("ListStoryFingerprints", testUser.ID).Return(nil, nil).Once()
 
 		// UpdateGenerationStatus が呼ばれる (1回に更新)
 		mockUserRepo.On("UpdateGenerationStatus", testUser.ID, 1, mock.AnythingOfType("time.Time")).Return(nil).Once()
 
-		story, err := storyService.GenerateStory(testUser.ID, prompt)
+		story, err := storyService.GenerateStory(testUser.ID, GenerateStoryInput{Prompt: prompt})
 
 		require.NoError(t, err)
 		assert.Equal(t, expectedWordCount, story.WordCount)
@@ -62,6 +69,25 @@ func TestStoryService_GenerateStory(t *testing.T) {
 		mockStoryRepo.AssertExpectations(t)
 	})
 
+	t.Run("success: should shorten a long prompt for the title", func(t *testing.T) {
+		prompt := strings.Repeat("Write about a long journey. ", 20)
+
+		userState := baseUser
+		userState.GenerationCount = 0
+		userState.LastGenerationAt = nil
+
+		mockUserRepo.On("GetUserByID", testUser.ID).Return(&userState, nil).Once()
+		mockLLM.On("GenerateStory", prompt, GenerationOptions{}).Return("The journey was long.", nil).Once()
+		mockStoryRepo.On("CreateStory", mock.AnythingOfType("*model.Story")).Return(nil).Once()
+		mockStoryRepo.On("ListStoryFingerprints", testUser.ID).Return(nil, nil).Once()
+		mockUserRepo.On("UpdateGenerationStatus", testUser.ID, 1, mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+		story, err := storyService.GenerateStory(testUser.ID, GenerateStoryInput{Prompt: prompt})
+
+		require.NoError(t, err)
+		assert.Equal(t, strings.TrimSpace(prompt[:100]), story.Title)
+	})
+
 	t.Run("success: count should reset if last generation was yesterday", func(t *testing.T) {
 		prompt := "A story about resetting"
 
@@ -75,20 +101,44 @@ func TestStoryService_GenerateStory(t *testing.T) {
 		mockUserRepo.On("GetUserByID", testUser.ID).Return(&userState, nil).Once()
 
 		// LLM 呼び出し (カウントがリセットされ、実行される)
-		mockLLM.On("GenerateStory", prompt).Return("Content", nil).Once()
+		mockLLM.On("GenerateStory", prompt, GenerationOptions{}).Return("Content", nil).Once()
 
 		// Story 作成
 		mockStoryRepo.On("CreateStory", mock.AnythingOfType("*model.Story")).Return(nil).Once()
+		mockStoryRepo.On("ListStoryFingerprints", testUser.ID).Return(nil, nil).Once()
 
 		// カウントが 1 に更新される
 		mockUserRepo.On("UpdateGenerationStatus", testUser.ID, 1, mock.AnythingOfType("time.Time")).Return(nil).Once()
 
-		_, err := storyService.GenerateStory(testUser.ID, prompt)
+		_, err := storyService.GenerateStory(testUser.ID, GenerateStoryInput{Prompt: prompt})
 
 		require.NoError(t, err)
 		mockUserRepo.AssertExpectations(t)
 	})
 
+	t.Run("success: should pass level and length to the LLM and store the level", func(t *testing.T) {
+		prompt := "A story with options"
+
+		userState := baseUser
+		userState.GenerationCount = 0
+		userState.LastGenerationAt = nil
+
+		mockUserRepo.On("GetUserByID", testUser.ID).Return(&userState, nil).Once()
+		mockLLM.On("GenerateStory", prompt, GenerationOptions{Level: "B2", WordCount: 400}).Return("Content", nil).Once()
+		mockStoryRepo.On("CreateStory", mock.MatchedBy(func(s *model.Story) bool {
+			return s.Level != nil && *s.Level == "B2"
+		})).Return(nil).Once()
+		mockStoryRepo.On("ListStoryFingerprints", testUser.ID).Return(nil, nil).Once()
+		mockUserRepo.On("UpdateGenerationStatus", testUser.ID, 1, mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+		story, err := storyService.GenerateStory(testUser.ID, GenerateStoryInput{Prompt: prompt, Level: "B2", WordCount: 400})
+
+		require.NoError(t, err)
+		assert.Equal(t, "B2", *story.Level)
+		mockLLM.AssertExpectations(t)
+		mockStoryRepo.AssertExpectations(t)
+	})
+
 	t.Run("fail: should return ErrGenerationLimitExceeded if limit reached", func(t *testing.T) {
 		prompt := "A story that should fail"
 
@@ -101,7 +151,7 @@ func TestStoryService_GenerateStory(t *testing.T) {
 		// GetUserByID が呼ばれる
 		mockUserRepo.On("GetUserByID", testUser.ID).Return(&userState, nil).Once()
 
-		story, err := storyService.GenerateStory(testUser.ID, prompt)
+		story, err := storyService.GenerateStory(testUser.ID, GenerateStoryInput{Prompt: prompt})
 
 		require.Error(t, err)
 		assert.ErrorIs(t, err, ErrGenerationLimitExceeded)
@@ -113,7 +163,7 @@ func TestStoryService_GenerateStory(t *testing.T) {
 
 func TestStoryService_GetStories(t *testing.T) {
 	// セットアップヘルパーを使用
-	mockStoryRepo, _, mockUserRepo, _, storyService := setupStoryServiceTest(t)
+	mockStoryRepo, _, _, _, mockUserRepo, _, _, storyService := setupStoryServiceTest(t)
 	_ = mockUserRepo // (このテストでは使わないため、エラー回避)
 
 	t.Run("success: should calculate pagination correctly", func(t *testing.T) {
@@ -124,10 +174,10 @@ func TestStoryService_GetStories(t *testing.T) {
 		totalPages := 3
 		_ = totalPages // (未使用変数エラー回避)
 
-		mockStories := []*model.Story{testStory}
-		mockStoryRepo.On("CountUserStories", testUser.ID).Return(totalCount, nil).Once()
-		mockStoryRepo.On("GetUserStories", testUser.ID, limit, offset).Return(mockStories, nil).Once()
-		result, err := storyService.GetStories(testUser.ID, page, limit)
+		mockStories := []*model.StoryListItem{{Story: *testStory}}
+		mockStoryRepo.On("CountUserStories", testUser.ID, repository.StoryFilter{}).Return(totalCount, nil).Once()
+		mockStoryRepo.On("GetUserStories", testUser.ID, repository.StoryFilter{}, repository.StorySortDefault, limit, offset).Return(mockStories, nil).Once()
+		result, err := storyService.GetStories(testUser.ID, StoryListQuery{Page: page, Limit: limit})
 
 		require.NoError(t, err)
 		assert.Equal(t, totalCount, result.TotalCount)
@@ -136,22 +186,104 @@ func TestStoryService_GetStories(t *testing.T) {
 	})
 }
 
+func TestStoryService_GetStories_Cursor(t *testing.T) {
+	mockStoryRepo, _, _, _, _, _, _, storyService := setupStoryServiceTest(t)
+
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	listItem := func(id int, minutesAgo int) *model.StoryListItem {
+		return &model.StoryListItem{Story: model.Story{ID: id, UserID: testUser.ID, CreatedAt: base.Add(-time.Duration(minutesAgo) * time.Minute)}}
+	}
+
+	t.Run("success: page mode should also return a next cursor", func(t *testing.T) {
+		mockStoryRepo.On("CountUserStories", testUser.ID, repository.StoryFilter{}).Return(3, nil).Once()
+		mockStoryRepo.On("GetUserStories", testUser.ID, repository.StoryFilter{}, repository.StorySortDefault, 2, 0).
+			Return([]*model.StoryListItem{listItem(3, 0), listItem(2, 1)}, nil).Once()
+
+		result, err := storyService.GetStories(testUser.ID, StoryListQuery{Page: 1, Limit: 2})
+
+		require.NoError(t, err)
+		require.NotNil(t, result.NextCursor)
+		assert.Nil(t, result.PrevCursor)
+
+		// 返されたカーソルで次のページを取得すると、最後の要素が基準位置になる
+		keyset, err := decodeCursor("stories:newest", *result.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, 2, keyset.ID)
+		assert.False(t, keyset.Backward)
+
+		mockStoryRepo.On("CountUserStories", testUser.ID, repository.StoryFilter{}).Return(3, nil).Once()
+		mockStoryRepo.On("GetUserStoriesByKeyset", testUser.ID, repository.StoryFilter{}, repository.StorySortDefault, keyset, 3).
+			Return([]*model.StoryListItem{listItem(1, 2)}, nil).Once()
+
+		next, err := storyService.GetStories(testUser.ID, StoryListQuery{Limit: 2, Cursor: *result.NextCursor})
+
+		require.NoError(t, err)
+		require.Len(t, next.Stories, 1)
+		assert.Nil(t, next.NextCursor, "last page should not have a next cursor")
+		require.NotNil(t, next.PrevCursor)
+		assert.Zero(t, next.CurrentPage)
+
+		mockStoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("success: backward page should drop the extra item at the front", func(t *testing.T) {
+		cursor := encodeCursor("stories:newest", base.Add(-5*time.Minute), 5, true)
+		keyset, err := decodeCursor("stories:newest", *cursor)
+		require.NoError(t, err)
+
+		mockStoryRepo.On("CountUserStories", testUser.ID, repository.StoryFilter{}).Return(10, nil).Once()
+		mockStoryRepo.On("GetUserStoriesByKeyset", testUser.ID, repository.StoryFilter{}, repository.StorySortDefault, keyset, 3).
+			Return([]*model.StoryListItem{listItem(8, 2), listItem(7, 3), listItem(6, 4)}, nil).Once()
+
+		result, err := storyService.GetStories(testUser.ID, StoryListQuery{Limit: 2, Cursor: *cursor})
+
+		require.NoError(t, err)
+		require.Len(t, result.Stories, 2)
+		assert.Equal(t, 7, result.Stories[0].ID)
+		assert.Equal(t, 6, result.Stories[1].ID)
+		assert.NotNil(t, result.PrevCursor)
+		assert.NotNil(t, result.NextCursor)
+	})
+
+	t.Run("fail: should reject a cursor issued for another sort order", func(t *testing.T) {
+		cursor := encodeCursor("stories:oldest", base, 1, false)
+		mockStoryRepo.On("CountUserStories", testUser.ID, repository.StoryFilter{}).Return(10, nil).Once()
+
+		_, err := storyService.GetStories(testUser.ID, StoryListQuery{Limit: 2, Cursor: *cursor})
+
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+
+	t.Run("fail: should reject cursors for sorts without keyset support", func(t *testing.T) {
+		_, err := storyService.GetStories(testUser.ID, StoryListQuery{Limit: 2, Sort: repository.StorySortTitle, Cursor: "abc"})
+
+		assert.ErrorIs(t, err, ErrCursorNotSupported)
+	})
+}
+
 func TestStoryService_GetStory(t *testing.T) {
 	// セットアップヘルパーを使用
-	mockStoryRepo, mockReadingRepo, mockUserRepo, _, storyService := setupStoryServiceTest(t)
+	mockStoryRepo, _, mockTranslationRepo, mockReadingRepo, mockUserRepo, mockTagRepo, _, storyService := setupStoryServiceTest(t)
 	_ = mockUserRepo // (このテストでは使わないため、エラー回避)
 
-	t.Run("success: should return story detail with read count", func(t *testing.T) {
+	t.Run("success: should return story detail with read count and tags", func(t *testing.T) {
 		expectedReadCount := 5
+		tags := []*model.Tag{{ID: 1, UserID: testUser.ID, Name: "science"}}
 		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Once()
 		mockReadingRepo.On("CountReadingRecords", testUser.ID, testStory.ID).Return(expectedReadCount, nil).Once()
+		mockStoryRepo.On("GetDerivedStories", testStory.ID, testUser.ID).Return([]*model.StoryVariant{}, nil).Once()
+		mockTranslationRepo.On("GetTranslation", testStory.ID, model.TranslationLanguageJapanese).Return(nil, sql.ErrNoRows).Once()
+		mockTagRepo.On("GetStoryTags", testStory.ID).Return(tags, nil).Once()
 		detail, err := storyService.GetStory(testStory.ID, testUser.ID)
 
 		require.NoError(t, err)
 		assert.Equal(t, expectedReadCount, detail.ReadCount)
+		assert.Nil(t, detail.ParallelText)
+		assert.Equal(t, tags, detail.Tags)
 
 		mockStoryRepo.AssertExpectations(t)
 		mockReadingRepo.AssertExpectations(t)
+		mockTagRepo.AssertExpectations(t)
 	})
 
 	t.Run("fail: should return ErrStoryNotFound if story repo returns ErrNoRows", func(t *testing.T) {
@@ -168,7 +300,7 @@ func TestStoryService_GetStory(t *testing.T) {
 
 func TestStoryService_MarkStoryAsRead(t *testing.T) {
 	// セットアップヘルパーを使用
-	mockStoryRepo, mockReadingRepo, mockUserRepo, _, storyService := setupStoryServiceTest(t)
+	mockStoryRepo, _, _, mockReadingRepo, mockUserRepo, _, _, storyService := setupStoryServiceTest(t)
 	_ = mockUserRepo // (このテストでは使わないため、Linterエラー回避)
 
 	t.Run("success: should create reading record with correct word count", func(t *testing.T) {
@@ -181,3 +313,571 @@ func TestStoryService_MarkStoryAsRead(t *testing.T) {
 		mockReadingRepo.AssertExpectations(t)
 	})
 }
+
+func TestStoryService_SetFavorite(t *testing.T) {
+	mockStoryRepo, _, _, _, _, _, _, storyService := setupStoryServiceTest(t)
+
+	t.Run("success: should mark the story as favorite", func(t *testing.T) {
+		mockStoryRepo.On("SetFavorite", testStory.ID, testUser.ID, true).Return(nil).Once()
+
+		require.NoError(t, storyService.SetFavorite(testStory.ID, testUser.ID, true))
+		mockStoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return ErrStoryNotFound for stories of another user", func(t *testing.T) {
+		mockStoryRepo.On("SetFavorite", testStory.ID, testUser.ID, false).Return(sql.ErrNoRows).Once()
+
+		err := storyService.SetFavorite(testStory.ID, testUser.ID, false)
+
+		assert.ErrorIs(t, err, ErrStoryNotFound)
+	})
+}
+
+func TestStoryService_Trash(t *testing.T) {
+	mockStoryRepo, _, _, _, _, _, _, storyService := setupStoryServiceTest(t)
+
+	t.Run("success: should return an empty list when the trash is empty", func(t *testing.T) {
+		mockStoryRepo.On("GetTrashedStories", testUser.ID).Return(nil, nil).Once()
+
+		stories, err := storyService.GetTrash(testUser.ID)
+
+		require.NoError(t, err)
+		assert.NotNil(t, stories)
+		assert.Empty(t, stories)
+	})
+
+	t.Run("fail: should return ErrStoryNotFound when restoring a story not in the trash", func(t *testing.T) {
+		mockStoryRepo.On("RestoreStory", testStory.ID, testUser.ID).Return(nil, sql.ErrNoRows).Once()
+
+		_, err := storyService.RestoreStory(testStory.ID, testUser.ID)
+
+		assert.ErrorIs(t, err, ErrStoryNotFound)
+	})
+
+	t.Run("success: should purge stories trashed before the cutoff", func(t *testing.T) {
+		cutoff := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+		mockStoryRepo.On("PurgeDeletedStories", cutoff).Return(int64(3), nil).Once()
+
+		purged, err := storyService.PurgeTrash(cutoff)
+
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, purged)
+		mockStoryRepo.AssertExpectations(t)
+	})
+}
+
+func TestStoryService_BulkUpdateStories(t *testing.T) {
+	mockStoryRepo, _, _, _, _, _, _, storyService := setupStoryServiceTest(t)
+
+	t.Run("fail: should reject an unknown action", func(t *testing.T) {
+		op := repository.BulkOperation{Action: "archive", StoryIDs: []int{testStory.ID}}
+
+		_, err := storyService.BulkUpdateStories(testUser.ID, op)
+
+		assert.ErrorIs(t, err, ErrInvalidBulkAction)
+		mockStoryRepo.AssertNotCalled(t, "BulkUpdateStories", testUser.ID, op)
+	})
+
+	t.Run("fail: should return ErrShelfNotFound when the shelf is not the user's", func(t *testing.T) {
+		op := repository.BulkOperation{Action: repository.BulkActionMoveToShelf, StoryIDs: []int{testStory.ID}, ShelfID: 7}
+		mockStoryRepo.On("BulkUpdateStories", testUser.ID, op).Return(nil, sql.ErrNoRows).Once()
+
+		_, err := storyService.BulkUpdateStories(testUser.ID, op)
+
+		assert.ErrorIs(t, err, ErrShelfNotFound)
+	})
+
+	t.Run("fail: should return ErrTagNotFound when the tag is not the user's", func(t *testing.T) {
+		op := repository.BulkOperation{Action: repository.BulkActionTag, StoryIDs: []int{testStory.ID}, TagID: 3}
+		mockStoryRepo.On("BulkUpdateStories", testUser.ID, op).Return(nil, sql.ErrNoRows).Once()
+
+		_, err := storyService.BulkUpdateStories(testUser.ID, op)
+
+		assert.ErrorIs(t, err, ErrTagNotFound)
+		mockStoryRepo.AssertExpectations(t)
+	})
+}
+
+func TestStoryService_GetDuplicateClusters(t *testing.T) {
+	mockStoryRepo, _, _, _, _, _, _, storyService := setupStoryServiceTest(t)
+
+	t.Run("success: should group chained near duplicates, largest first", func(t *testing.T) {
+		mockStoryRepo.On("ListStoryFingerprints", testUser.ID).Return([]*model.StoryFingerprint{
+			{ID: 1, Simhash: 0x00ffffff00000000},
+			{ID: 2, Simhash: 0x00ffffff00000001},
+			{ID: 3, Simhash: 0},
+			{ID: 4, Simhash: 0xff},
+			{ID: 5, Simhash: 0xffff},
+			{ID: 6, Simhash: 0x7fffffff00ff0000},
+		}, nil).Once()
+
+		clusters, err := storyService.GetDuplicateClusters(testUser.ID)
+
+		require.NoError(t, err)
+		require.Len(t, clusters, 2)
+		ids := func(cluster *model.DuplicateCluster) []int {
+			var ids []int
+			for _, story := range cluster.Stories {
+				ids = append(ids, story.ID)
+			}
+			return ids
+		}
+		// 3 と 5 は直接は遠いが、4 を介してつながる
+		assert.Equal(t, []int{3, 4, 5}, ids(clusters[0]))
+		assert.Equal(t, 0.75, clusters[0].MinSimilarity)
+		assert.Equal(t, []int{1, 2}, ids(clusters[1]))
+		mockStoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("success: should return an empty list when nothing is duplicated", func(t *testing.T) {
+		mockStoryRepo.On("ListStoryFingerprints", testUser.ID).Return([]*model.StoryFingerprint{{ID: 1, Simhash: 0}}, nil).Once()
+
+		clusters, err := storyService.GetDuplicateClusters(testUser.ID)
+
+		require.NoError(t, err)
+		assert.NotNil(t, clusters)
+		assert.Empty(t, clusters)
+	})
+}
+
+func TestStoryService_BackfillSimhashes(t *testing.T) {
+	mockStoryRepo, _, _, _, _, _, _, storyService := setupStoryServiceTest(t)
+
+	t.Run("success: should fill one batch and return the count", func(t *testing.T) {
+		mockStoryRepo.On("BackfillSimhashes", simhashBackfillBatchSize).Return(12, nil).Once()
+
+		filled, err := storyService.BackfillSimhashes()
+
+		require.NoError(t, err)
+		assert.Equal(t, 12, filled)
+		mockStoryRepo.AssertExpectations(t)
+	})
+}
+
+func TestStoryService_UpdateStoryContent(t *testing.T) {
+	mockStoryRepo, _, _, _, _, _, _, storyService := setupStoryServiceTest(t)
+
+	t.Run("success: should recount words and keep the title when only the content changes", func(t *testing.T) {
+		content := "A fixed sentence without typos."
+		updated := *testStory
+		updated.Content = content
+		updated.WordCount = 5
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Once()
+		mockStoryRepo.On("UpdateStoryContent", testStory.ID, testUser.ID, testStory.Title, content, 5).Return(&updated, nil).Once()
+
+		story, err := storyService.UpdateStoryContent(testStory.ID, testUser.ID, nil, content)
+
+		require.NoError(t, err)
+		assert.Equal(t, 5, story.WordCount)
+		mockStoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("success: should not create a revision when nothing changes", func(t *testing.T) {
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Once()
+
+		story, err := storyService.UpdateStoryContent(testStory.ID, testUser.ID, &testStory.Title, testStory.Content)
+
+		require.NoError(t, err)
+		assert.Equal(t, testStory, story)
+		mockStoryRepo.AssertNumberOfCalls(t, "UpdateStoryContent", 1)
+	})
+}
+
+func TestStoryService_Revisions(t *testing.T) {
+	mockStoryRepo, _, _, _, _, _, _, storyService := setupStoryServiceTest(t)
+
+	revision := &model.StoryRevision{
+		StoryID:        testStory.ID,
+		RevisionNumber: 1,
+		Title:          "Old Title",
+		Content:        "This is a tset story content.",
+		WordCount:      6,
+	}
+
+	t.Run("success: should return the diff from the revision to the current content", func(t *testing.T) {
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Once()
+		mockStoryRepo.On("GetStoryRevision", testStory.ID, 1).Return(revision, nil).Once()
+
+		diff, err := storyService.GetRevisionDiff(testStory.ID, testUser.ID, 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, revision, diff.Revision)
+		assert.Equal(t, []DiffChange{
+			{Op: DiffEqual, Text: "This is a "},
+			{Op: DiffDelete, Text: "tset"},
+			{Op: DiffInsert, Text: "test"},
+			{Op: DiffEqual, Text: " story content."},
+		}, diff.Changes)
+	})
+
+	t.Run("success: should revert to the revision's title and content", func(t *testing.T) {
+		reverted := *testStory
+		reverted.Title = revision.Title
+		reverted.Content = revision.Content
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Once()
+		mockStoryRepo.On("GetStoryRevision", testStory.ID, 1).Return(revision, nil).Once()
+		mockStoryRepo.On("UpdateStoryContent", testStory.ID, testUser.ID, revision.Title, revision.Content, 6).Return(&reverted, nil).Once()
+
+		story, err := storyService.RevertStory(testStory.ID, testUser.ID, 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, revision.Content, story.Content)
+	})
+
+	t.Run("fail: should return ErrRevisionNotFound for an unknown revision", func(t *testing.T) {
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Once()
+		mockStoryRepo.On("GetStoryRevision", testStory.ID, 9).Return(nil, sql.ErrNoRows).Once()
+
+		_, err := storyService.RevertStory(testStory.ID, testUser.ID, 9)
+
+		assert.ErrorIs(t, err, ErrRevisionNotFound)
+		mockStoryRepo.AssertExpectations(t)
+	})
+}
+
+func TestDiffWords(t *testing.T) {
+	testCases := []struct {
+		name          string
+		before, after string
+		expected      []DiffChange
+	}{
+		{
+			name:     "identical text",
+			before:   "Same text.",
+			after:    "Same text.",
+			expected: []DiffChange{{Op: DiffEqual, Text: "Same text."}},
+		},
+		{
+			name:   "inserted and deleted words",
+			before: "The cat sat on the mat.",
+			after:  "The black cat sat on a mat.",
+			expected: []DiffChange{
+				{Op: DiffEqual, Text: "The "},
+				{Op: DiffInsert, Text: "black "},
+				{Op: DiffEqual, Text: "cat sat on "},
+				{Op: DiffDelete, Text: "the"},
+				{Op: DiffInsert, Text: "a"},
+				{Op: DiffEqual, Text: " mat."},
+			},
+		},
+		{
+			name:     "from empty text",
+			before:   "",
+			after:    "New story.",
+			expected: []DiffChange{{Op: DiffInsert, Text: "New story."}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, diffWords(tc.before, tc.after))
+		})
+	}
+}
+
+func TestStoryService_ContinueStory(t *testing.T) {
+	mockStoryRepo, mockSeriesRepo, _, _, mockUserRepo, _, mockLLM, storyService := setupStoryServiceTest(t)
+
+	baseUser := *testUser
+
+	t.Run("success: should create a series and generate chapter 2 for a standalone story", func(t *testing.T) {
+		userState := baseUser
+		userState.GenerationCount = 0
+		userState.LastGenerationAt = nil
+
+		standalone := *testStory
+		chapterOne := 1
+		firstChapter := standalone
+		firstChapter.ChapterNumber = &chapterOne
+
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(&standalone, nil).Once()
+		mockUserRepo.On("GetUserByID", testUser.ID).Return(&userState, nil).Once()
+		mockSeriesRepo.On("CreateSeries", mock.AnythingOfType("*model.Series")).Return(nil).Once()
+		mockStoryRepo.On("SetStorySeries", testStory.ID, 1, 1).Return(nil).Once()
+		mockSeriesRepo.On("GetSeriesChapters", 1).Return([]*model.SeriesChapter{
+			{StoryID: testStory.ID, Title: testStory.Title, ChapterNumber: 1, WordCount: testStory.WordCount},
+		}, nil).Once()
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(&firstChapter, nil).Once()
+		mockLLM.On("SummarizeChapter", "", testStory.Content).Return("Summary of chapter 1.", nil).Once()
+		mockSeriesRepo.On("UpdateSeriesSummary", 1, "Summary of chapter 1.", 1).Return(nil).Once()
+		mockSeriesRepo.On("GetLastChapterNumber", 1).Return(1, nil).Once()
+		mockLLM.On("ContinueStory", testStory.Title, "Summary of chapter 1.", 2).Return("The second chapter begins.", nil).Once()
+		mockStoryRepo.On("CreateStory", mock.AnythingOfType("*model.Story")).Return(nil).Once()
+		mockUserRepo.On("UpdateGenerationStatus", testUser.ID, 1, mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+		next, err := storyService.ContinueStory(testStory.ID, testUser.ID)
+
+		require.NoError(t, err)
+		require.NotNil(t, next.SeriesID)
+		require.NotNil(t, next.ChapterNumber)
+		assert.Equal(t, 1, *next.SeriesID)
+		assert.Equal(t, 2, *next.ChapterNumber)
+		assert.Equal(t, "Test Story (Chapter 2)", next.Title)
+		assert.Equal(t, 4, next.WordCount)
+
+		mockStoryRepo.AssertExpectations(t)
+		mockSeriesRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
+		mockLLM.AssertExpectations(t)
+	})
+
+	t.Run("success: should shorten a long series title before adding the chapter number", func(t *testing.T) {
+		userState := baseUser
+		userState.GenerationCount = 0
+		userState.LastGenerationAt = nil
+
+		longTitle := strings.Repeat("あ", 255)
+		seriesID, chapterOne := 1, 1
+		chapter := *testStory
+		chapter.Title = longTitle
+		chapter.SeriesID = &seriesID
+		chapter.ChapterNumber = &chapterOne
+
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(&chapter, nil).Once()
+		mockUserRepo.On("GetUserByID", testUser.ID).Return(&userState, nil).Once()
+		mockSeriesRepo.On("GetUserSeries", seriesID, testUser.ID).Return(&model.Series{
+			ID: seriesID, UserID: testUser.ID, Title: longTitle, Summary: "Summary of chapter 1.", SummarizedThrough: 1,
+		}, nil).Once()
+		mockSeriesRepo.On("GetSeriesChapters", seriesID).Return([]*model.SeriesChapter{
+			{StoryID: testStory.ID, Title: longTitle, ChapterNumber: 1, WordCount: testStory.WordCount},
+		}, nil).Once()
+		mockSeriesRepo.On("GetLastChapterNumber", seriesID).Return(1, nil).Once()
+		mockLLM.On("ContinueStory", longTitle, "Summary of chapter 1.", 2).Return("The second chapter begins.", nil).Once()
+		mockStoryRepo.On("CreateStory", mock.AnythingOfType("*model.Story")).Return(nil).Once()
+		mockUserRepo.On("UpdateGenerationStatus", testUser.ID, 1, mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+		next, err := storyService.ContinueStory(testStory.ID, testUser.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, strings.Repeat("あ", 100)+" (Chapter 2)", next.Title)
+	})
+
+	t.Run("fail: should return ErrChapterAlreadyExists when another request saved the chapter first", func(t *testing.T) {
+		userState := baseUser
+		userState.GenerationCount = 0
+		userState.LastGenerationAt = nil
+
+		seriesID, chapterOne := 1, 1
+		chapter := *testStory
+		chapter.SeriesID = &seriesID
+		chapter.ChapterNumber = &chapterOne
+
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(&chapter, nil).Once()
+		mockUserRepo.On("GetUserByID", testUser.ID).Return(&userState, nil).Once()
+		mockSeriesRepo.On("GetUserSeries", seriesID, testUser.ID).Return(&model.Series{
+			ID: seriesID, UserID: testUser.ID, Title: testStory.Title, Summary: "Summary of chapter 1.", SummarizedThrough: 1,
+		}, nil).Once()
+		mockSeriesRepo.On("GetSeriesChapters", seriesID).Return([]*model.SeriesChapter{
+			{StoryID: testStory.ID, Title: testStory.Title, ChapterNumber: 1, WordCount: testStory.WordCount},
+		}, nil).Once()
+		mockSeriesRepo.On("GetLastChapterNumber", seriesID).Return(1, nil).Once()
+		mockLLM.On("ContinueStory", testStory.Title, "Summary of chapter 1.", 2).Return("The second chapter begins.", nil).Once()
+		mockStoryRepo.On("CreateStory", mock.AnythingOfType("*model.Story")).Return(repository.ErrChapterAlreadyExists).Once()
+
+		next, err := storyService.ContinueStory(testStory.ID, testUser.ID)
+
+		assert.ErrorIs(t, err, ErrChapterAlreadyExists)
+		assert.Nil(t, next)
+	})
+
+	t.Run("fail: should return ErrGenerationLimitExceeded if limit reached", func(t *testing.T) {
+		userState := baseUser
+		userState.GenerationCount = testDailyLimit
+		today := timeutil.NowTokyo()
+		userState.LastGenerationAt = &today
+
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Once()
+		mockUserRepo.On("GetUserByID", testUser.ID).Return(&userState, nil).Once()
+
+		next, err := storyService.ContinueStory(testStory.ID, testUser.ID)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrGenerationLimitExceeded)
+		assert.Nil(t, next)
+
+		mockStoryRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
+	})
+}
+
+func TestStoryService_GetStory_SeriesNavigation(t *testing.T) {
+	mockStoryRepo, mockSeriesRepo, mockTranslationRepo, mockReadingRepo, _, mockTagRepo, _, storyService := setupStoryServiceTest(t)
+
+	t.Run("success: should return previous and next chapters with series word total", func(t *testing.T) {
+		seriesID, chapterNumber := 3, 2
+		chapter := *testStory
+		chapter.SeriesID = &seriesID
+		chapter.ChapterNumber = &chapterNumber
+
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(&chapter, nil).Once()
+		mockReadingRepo.On("CountReadingRecords", testUser.ID, testStory.ID).Return(0, nil).Once()
+		mockStoryRepo.On("GetDerivedStories", testStory.ID, testUser.ID).Return(nil, nil).Once()
+		mockTranslationRepo.On("GetTranslation", testStory.ID, model.TranslationLanguageJapanese).Return(nil, sql.ErrNoRows).Once()
+		mockTagRepo.On("GetStoryTags", testStory.ID).Return(nil, nil).Once()
+		mockSeriesRepo.On("GetUserSeries", seriesID, testUser.ID).Return(&model.Series{ID: seriesID, UserID: testUser.ID, Title: "Series"}, nil).Once()
+		mockSeriesRepo.On("GetSeriesChapters", seriesID).Return([]*model.SeriesChapter{
+			{StoryID: 9, ChapterNumber: 1, WordCount: 100},
+			{StoryID: testStory.ID, ChapterNumber: 2, WordCount: 200},
+			{StoryID: 11, ChapterNumber: 4, WordCount: 300},
+		}, nil).Once()
+
+		detail, err := storyService.GetStory(testStory.ID, testUser.ID)
+
+		require.NoError(t, err)
+		require.NotNil(t, detail.Series)
+		assert.Equal(t, 3, detail.Series.ChapterCount)
+		assert.Equal(t, 600, detail.Series.TotalWordCount)
+		require.NotNil(t, detail.Series.PrevStoryID)
+		require.NotNil(t, detail.Series.NextStoryID)
+		assert.Equal(t, 9, *detail.Series.PrevStoryID)
+		assert.Equal(t, 11, *detail.Series.NextStoryID)
+
+		mockStoryRepo.AssertExpectations(t)
+		mockSeriesRepo.AssertExpectations(t)
+	})
+}
+
+func TestStoryService_RewriteStory(t *testing.T) {
+	mockStoryRepo, _, _, _, mockUserRepo, _, mockLLM, storyService := setupStoryServiceTest(t)
+
+	baseUser := *testUser
+
+	t.Run("success: should save a linked derivative at the target level", func(t *testing.T) {
+		userState := baseUser
+		userState.GenerationCount = 0
+		userState.LastGenerationAt = nil
+
+		original := *testStory
+		currentLevel := "B2"
+		original.Level = &currentLevel
+
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(&original, nil).Once()
+		mockUserRepo.On("GetUserByID", testUser.ID).Return(&userState, nil).Once()
+		mockLLM.On("RewriteStory", testStory.Content, "B2", "A2").Return("A simple text.", nil).Once()
+		mockStoryRepo.On("CreateStory", mock.AnythingOfType("*model.Story")).Return(nil).Once()
+		mockUserRepo.On("UpdateGenerationStatus", testUser.ID, 1, mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+		rewritten, err := storyService.RewriteStory(testStory.ID, testUser.ID, "A2")
+
+		require.NoError(t, err)
+		require.NotNil(t, rewritten.ParentStoryID)
+		require.NotNil(t, rewritten.Level)
+		assert.Equal(t, testStory.ID, *rewritten.ParentStoryID)
+		assert.Equal(t, "A2", *rewritten.Level)
+		assert.Equal(t, 3, rewritten.WordCount)
+
+		mockStoryRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
+		mockLLM.AssertExpectations(t)
+	})
+
+	t.Run("success: should shorten a long title before adding the level", func(t *testing.T) {
+		userState := baseUser
+		userState.GenerationCount = 0
+		userState.LastGenerationAt = nil
+
+		original := *testStory
+		original.Title = strings.Repeat("a", 255)
+		currentLevel := "B2"
+		original.Level = &currentLevel
+
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(&original, nil).Once()
+		mockUserRepo.On("GetUserByID", testUser.ID).Return(&userState, nil).Once()
+		mockLLM.On("RewriteStory", testStory.Content, "B2", "A2").Return("A simple text.", nil).Once()
+		mockStoryRepo.On("CreateStory", mock.AnythingOfType("*model.Story")).Return(nil).Once()
+		mockUserRepo.On("UpdateGenerationStatus", testUser.ID, 1, mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+		rewritten, err := storyService.RewriteStory(testStory.ID, testUser.ID, "A2")
+
+		require.NoError(t, err)
+		assert.Equal(t, strings.Repeat("a", 100)+" [A2]", rewritten.Title)
+	})
+
+	t.Run("fail: should return ErrAlreadyAtLevel for the same level", func(t *testing.T) {
+		original := *testStory
+		currentLevel := "B1"
+		original.Level = &currentLevel
+
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(&original, nil).Once()
+
+		_, err := storyService.RewriteStory(testStory.ID, testUser.ID, "B1")
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrAlreadyAtLevel)
+		mockStoryRepo.AssertExpectations(t)
+	})
+}
+
+func TestStoryService_TranslateStory(t *testing.T) {
+	mockStoryRepo, _, mockTranslationRepo, mockReadingRepo, mockUserRepo, mockTagRepo, mockLLM, storyService := setupStoryServiceTest(t)
+
+	story := *testStory
+	story.Content = "# Title\n\nFirst paragraph\ncontinues here.\n\n- item one\n- item two\n"
+	story.WordCount = countWords(story.Content)
+	paragraphs := []string{"# Title", "First paragraph\ncontinues here.", "- item one\n- item two"}
+
+	t.Run("success: should translate paragraph by paragraph without changing word count", func(t *testing.T) {
+		translated := []string{"# タイトル", "最初の段落", "- 項目1\n- 項目2"}
+
+		mockStoryRepo.On("GetUserStory", story.ID, testUser.ID).Return(&story, nil).Once()
+		mockUserRepo.On("GetUserByID", testUser.ID).Return(&model.User{ID: testUser.ID}, nil).Once()
+		mockLLM.On("TranslateParagraphs", paragraphs).Return(translated, nil).Once()
+		mockTranslationRepo.On("UpsertTranslation", mock.AnythingOfType("*model.StoryTranslation")).Return(nil).Once()
+		mockUserRepo.On("UpdateGenerationStatus", testUser.ID, 1, mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+		pairs, err := storyService.TranslateStory(story.ID, testUser.ID)
+
+		require.NoError(t, err)
+		require.Len(t, pairs, 3)
+		assert.Equal(t, "First paragraph\ncontinues here.", pairs[1].English)
+		assert.Equal(t, "最初の段落", pairs[1].Japanese)
+		assert.Equal(t, countWords(story.Content), story.WordCount)
+
+		mockLLM.AssertExpectations(t)
+		mockTranslationRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("fail: should reject a misaligned translation", func(t *testing.T) {
+		mockStoryRepo.On("GetUserStory", story.ID, testUser.ID).Return(&story, nil).Once()
+		mockUserRepo.On("GetUserByID", testUser.ID).Return(&model.User{ID: testUser.ID}, nil).Once()
+		mockLLM.On("TranslateParagraphs", paragraphs).Return([]string{"一つだけ"}, nil).Once()
+
+		_, err := storyService.TranslateStory(story.ID, testUser.ID)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrTranslationMisaligned)
+		mockTranslationRepo.AssertNumberOfCalls(t, "UpsertTranslation", 1)
+	})
+
+	t.Run("fail: should return ErrGenerationLimitExceeded without calling the LLM", func(t *testing.T) {
+		today := timeutil.NowTokyo()
+		mockStoryRepo.On("GetUserStory", story.ID, testUser.ID).Return(&story, nil).Once()
+		mockUserRepo.On("GetUserByID", testUser.ID).Return(&model.User{ID: testUser.ID, GenerationCount: testDailyLimit, LastGenerationAt: &today}, nil).Once()
+
+		_, err := storyService.TranslateStory(story.ID, testUser.ID)
+
+		assert.ErrorIs(t, err, ErrGenerationLimitExceeded)
+		mockLLM.AssertNumberOfCalls(t, "TranslateParagraphs", 2)
+		mockUserRepo.AssertNumberOfCalls(t, "UpdateGenerationStatus", 1)
+	})
+
+	t.Run("success: detail should return aligned pairs of a stored translation", func(t *testing.T) {
+		mockStoryRepo.On("GetUserStory", story.ID, testUser.ID).Return(&story, nil).Once()
+		mockReadingRepo.On("CountReadingRecords", testUser.ID, story.ID).Return(0, nil).Once()
+		mockStoryRepo.On("GetDerivedStories", story.ID, testUser.ID).Return(nil, nil).Once()
+		mockTranslationRepo.On("GetTranslation", story.ID, model.TranslationLanguageJapanese).Return(&model.StoryTranslation{
+			StoryID:    story.ID,
+			Language:   model.TranslationLanguageJapanese,
+			Paragraphs: []string{"# タイトル", "最初の段落", "- 項目"},
+		}, nil).Once()
+		mockTagRepo.On("GetStoryTags", story.ID).Return(nil, nil).Once()
+
+		detail, err := storyService.GetStory(story.ID, testUser.ID)
+
+		require.NoError(t, err)
+		require.Len(t, detail.ParallelText, 3)
+		assert.Equal(t, "# Title", detail.ParallelText[0].English)
+		assert.Equal(t, "# タイトル", detail.ParallelText[0].Japanese)
+	})
+}
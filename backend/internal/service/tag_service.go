@@ -0,0 +1,131 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
+)
+
+var ErrTagNotFound = errors.New("tag not found")
+
+type ITagService interface {
+	ListTags(userID int) ([]*model.Tag, error)
+	CreateTag(userID int, name string) (*model.Tag, error)
+	RenameTag(tagID, userID int, name string) (*model.Tag, error)
+	DeleteTag(tagID, userID int) error
+	AttachTag(storyID, tagID, userID int) error
+	DetachTag(storyID, tagID, userID int) error
+}
+
+type TagService struct {
+	TagRepo   repository.ITagRepository
+	StoryRepo repository.IStoryRepository
+}
+
+func NewTagService(tagRepo repository.ITagRepository, storyRepo repository.IStoryRepository) ITagService {
+	return &TagService{
+		TagRepo:   tagRepo,
+		StoryRepo: storyRepo,
+	}
+}
+
+func (s *TagService) ListTags(userID int) ([]*model.Tag, error) {
+	tags, err := s.TagRepo.ListTags(userID)
+	if err != nil {
+		return nil, fmt.Errorf("database error (list tags): %w", err)
+	}
+	if tags == nil {
+		tags = []*model.Tag{}
+	}
+	return tags, nil
+}
+
+func (s *TagService) CreateTag(userID int, name string) (*model.Tag, error) {
+	tag := &model.Tag{
+		UserID: userID,
+		Name:   name,
+	}
+	if err := s.TagRepo.CreateTag(tag); err != nil {
+		if errors.Is(err, repository.ErrTagAlreadyExists) {
+			return nil, repository.ErrTagAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+	return tag, nil
+}
+
+func (s *TagService) RenameTag(tagID, userID int, name string) (*model.Tag, error) {
+	tag, err := s.getUserTag(tagID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tag.Name = name
+	if err := s.TagRepo.RenameTag(tag); err != nil {
+		if errors.Is(err, repository.ErrTagAlreadyExists) {
+			return nil, repository.ErrTagAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to rename tag: %w", err)
+	}
+	return tag, nil
+}
+
+// DeleteTag はタグを削除する。ストーリーからの付与も合わせて外れる
+func (s *TagService) DeleteTag(tagID, userID int) error {
+	if _, err := s.getUserTag(tagID, userID); err != nil {
+		return err
+	}
+
+	if err := s.TagRepo.DeleteTag(tagID); err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	return nil
+}
+
+func (s *TagService) AttachTag(storyID, tagID, userID int) error {
+	if err := s.checkOwnership(storyID, tagID, userID); err != nil {
+		return err
+	}
+
+	if err := s.TagRepo.AttachTag(storyID, tagID); err != nil {
+		return fmt.Errorf("failed to attach tag: %w", err)
+	}
+	return nil
+}
+
+func (s *TagService) DetachTag(storyID, tagID, userID int) error {
+	if err := s.checkOwnership(storyID, tagID, userID); err != nil {
+		return err
+	}
+
+	if err := s.TagRepo.DetachTag(storyID, tagID); err != nil {
+		return fmt.Errorf("failed to detach tag: %w", err)
+	}
+	return nil
+}
+
+// checkOwnership はストーリーとタグがどちらもユーザーのものであることを確認する
+func (s *TagService) checkOwnership(storyID, tagID, userID int) error {
+	if _, err := s.StoryRepo.GetUserStory(storyID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrStoryNotFound
+		}
+		return fmt.Errorf("database error (get story): %w", err)
+	}
+	_, err := s.getUserTag(tagID, userID)
+	return err
+}
+
+func (s *TagService) getUserTag(tagID, userID int) (*model.Tag, error) {
+	tag, err := s.TagRepo.GetUserTag(tagID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTagNotFound
+		}
+		return nil, fmt.Errorf("database error (get tag): %w", err)
+	}
+	return tag, nil
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTagServiceTest(t *testing.T) (*MockTagRepository, *MockStoryRepository, ITagService) {
+	mockTagRepo := new(MockTagRepository)
+	mockStoryRepo := new(MockStoryRepository)
+
+	tagService := NewTagService(mockTagRepo, mockStoryRepo)
+
+	return mockTagRepo, mockStoryRepo, tagService
+}
+
+func TestTagService_CreateTag(t *testing.T) {
+	mockTagRepo, _, tagService := setupTagServiceTest(t)
+
+	t.Run("success: should create a tag for the user", func(t *testing.T) {
+		mockTagRepo.On("CreateTag", mock.MatchedBy(func(tag *model.Tag) bool {
+			return tag.UserID == testUser.ID && tag.Name == "science"
+		})).Return(nil).Once()
+
+		tag, err := tagService.CreateTag(testUser.ID, "science")
+
+		require.NoError(t, err)
+		assert.Equal(t, "science", tag.Name)
+		mockTagRepo.AssertExpectations(t)
+	})
+
+	t.Run("fail: should report duplicate names", func(t *testing.T) {
+		mockTagRepo.On("CreateTag", mock.Anything).Return(repository.ErrTagAlreadyExists).Once()
+
+		_, err := tagService.CreateTag(testUser.ID, "science")
+
+		assert.ErrorIs(t, err, repository.ErrTagAlreadyExists)
+	})
+}
+
+func TestTagService_RenameTag(t *testing.T) {
+	mockTagRepo, _, tagService := setupTagServiceTest(t)
+
+	t.Run("success: should rename the tag", func(t *testing.T) {
+		mockTagRepo.On("GetUserTag", 1, testUser.ID).Return(&model.Tag{ID: 1, UserID: testUser.ID, Name: "travel"}, nil).Once()
+		mockTagRepo.On("RenameTag", mock.MatchedBy(func(tag *model.Tag) bool {
+			return tag.ID == 1 && tag.Name == "trips"
+		})).Return(nil).Once()
+
+		tag, err := tagService.RenameTag(1, testUser.ID, "trips")
+
+		require.NoError(t, err)
+		assert.Equal(t, "trips", tag.Name)
+		mockTagRepo.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return not found for tags of another user", func(t *testing.T) {
+		mockTagRepo.On("GetUserTag", 2, testUser.ID).Return(nil, sql.ErrNoRows).Once()
+
+		_, err := tagService.RenameTag(2, testUser.ID, "trips")
+
+		assert.ErrorIs(t, err, ErrTagNotFound)
+		mockTagRepo.AssertNumberOfCalls(t, "RenameTag", 1)
+	})
+}
+
+func TestTagService_AttachTag(t *testing.T) {
+	mockTagRepo, mockStoryRepo, tagService := setupTagServiceTest(t)
+
+	t.Run("success: should attach an owned tag to an owned story", func(t *testing.T) {
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Once()
+		mockTagRepo.On("GetUserTag", 1, testUser.ID).Return(&model.Tag{ID: 1, UserID: testUser.ID}, nil).Once()
+		mockTagRepo.On("AttachTag", testStory.ID, 1).Return(nil).Once()
+
+		require.NoError(t, tagService.AttachTag(testStory.ID, 1, testUser.ID))
+		mockTagRepo.AssertExpectations(t)
+	})
+
+	t.Run("fail: should return not found for stories of another user", func(t *testing.T) {
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(nil, sql.ErrNoRows).Once()
+
+		err := tagService.AttachTag(testStory.ID, 1, testUser.ID)
+
+		assert.ErrorIs(t, err, ErrStoryNotFound)
+	})
+
+	t.Run("fail: should return not found for tags of another user", func(t *testing.T) {
+		mockStoryRepo.On("GetUserStory", testStory.ID, testUser.ID).Return(testStory, nil).Once()
+		mockTagRepo.On("GetUserTag", 2, testUser.ID).Return(nil, sql.ErrNoRows).Once()
+
+		err := tagService.AttachTag(testStory.ID, 2, testUser.ID)
+
+		assert.ErrorIs(t, err, ErrTagNotFound)
+		mockTagRepo.AssertNumberOfCalls(t, "AttachTag", 1)
+	})
+}
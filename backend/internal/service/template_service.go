@@ -0,0 +1,206 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
+)
+
+var (
+	ErrTemplateNotFound         = errors.New("template not found")
+	ErrMissingTemplateVariables = errors.New("missing template variables")
+)
+
+// placeholderPattern は {{ name }} 形式のプレースホルダにマッチする
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// TemplateInput はテンプレートの作成・更新に使う入力
+type TemplateInput struct {
+	Name             string
+	Body             string
+	DefaultLevel     *string
+	DefaultWordCount *int
+	// Global はプリセットとして公開するかどうか (管理者のみ)
+	Global bool
+}
+
+// RenderedTemplate はプレースホルダを展開したプロンプトと生成パラメータ
+type RenderedTemplate struct {
+	Prompt    string
+	Level     string
+	WordCount int
+}
+
+type ITemplateService interface {
+	ListTemplates(userID int) ([]*model.PromptTemplate, error)
+	CreateTemplate(userID int, input TemplateInput) (*model.PromptTemplate, error)
+	UpdateTemplate(templateID, userID int, input TemplateInput) (*model.PromptTemplate, error)
+	DeleteTemplate(templateID, userID int) error
+	RenderTemplate(templateID, userID int, variables map[string]string) (*RenderedTemplate, error)
+}
+
+type TemplateService struct {
+	TemplateRepo repository.ITemplateRepository
+	UserRepo     repository.IUserRepository
+}
+
+func NewTemplateService(templateRepo repository.ITemplateRepository, userRepo repository.IUserRepository) ITemplateService {
+	return &TemplateService{
+		TemplateRepo: templateRepo,
+		UserRepo:     userRepo,
+	}
+}
+
+func (s *TemplateService) ListTemplates(userID int) ([]*model.PromptTemplate, error) {
+	templates, err := s.TemplateRepo.ListTemplates(userID)
+	if err != nil {
+		return nil, fmt.Errorf("database error (list templates): %w", err)
+	}
+	if templates == nil {
+		templates = []*model.PromptTemplate{}
+	}
+	return templates, nil
+}
+
+func (s *TemplateService) CreateTemplate(userID int, input TemplateInput) (*model.PromptTemplate, error) {
+	template := &model.PromptTemplate{
+		Name:             input.Name,
+		Body:             input.Body,
+		DefaultLevel:     input.DefaultLevel,
+		DefaultWordCount: input.DefaultWordCount,
+	}
+
+	if input.Global {
+		if err := s.requireAdmin(userID); err != nil {
+			return nil, err
+		}
+	} else {
+		template.UserID = &userID
+	}
+
+	if err := s.TemplateRepo.CreateTemplate(template); err != nil {
+		return nil, fmt.Errorf("failed to create template: %w", err)
+	}
+	return template, nil
+}
+
+func (s *TemplateService) UpdateTemplate(templateID, userID int, input TemplateInput) (*model.PromptTemplate, error) {
+	template, err := s.getWritableTemplate(templateID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Name = input.Name
+	template.Body = input.Body
+	template.DefaultLevel = input.DefaultLevel
+	template.DefaultWordCount = input.DefaultWordCount
+
+	if err := s.TemplateRepo.UpdateTemplate(template); err != nil {
+		return nil, fmt.Errorf("failed to update template: %w", err)
+	}
+	return template, nil
+}
+
+func (s *TemplateService) DeleteTemplate(templateID, userID int) error {
+	if _, err := s.getWritableTemplate(templateID, userID); err != nil {
+		return err
+	}
+
+	if err := s.TemplateRepo.DeleteTemplate(templateID); err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+	return nil
+}
+
+// RenderTemplate はテンプレートのプレースホルダを変数で置き換える。未指定の変数があればエラーを返す
+func (s *TemplateService) RenderTemplate(templateID, userID int, variables map[string]string) (*RenderedTemplate, error) {
+	template, err := s.getReadableTemplate(templateID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	prompt := placeholderPattern.ReplaceAllStringFunc(template.Body, func(match string) string {
+		name := placeholderPattern.FindStringSubmatch(match)[1]
+		value, ok := variables[name]
+		if !ok || strings.TrimSpace(value) == "" {
+			missing = append(missing, name)
+			return match
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("%w: %s", ErrMissingTemplateVariables, strings.Join(missing, ", "))
+	}
+
+	rendered := &RenderedTemplate{Prompt: prompt}
+	if template.DefaultLevel != nil {
+		rendered.Level = *template.DefaultLevel
+	}
+	if template.DefaultWordCount != nil {
+		rendered.WordCount = *template.DefaultWordCount
+	}
+	return rendered, nil
+}
+
+// TemplatePlaceholders はテンプレート本文に含まれるプレースホルダ名を重複なく出現順に返す
+func TemplatePlaceholders(body string) []string {
+	names := []string{}
+	seen := map[string]bool{}
+	for _, match := range placeholderPattern.FindAllStringSubmatch(body, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// getReadableTemplate は自分のテンプレートまたはプリセットを返す
+func (s *TemplateService) getReadableTemplate(templateID, userID int) (*model.PromptTemplate, error) {
+	template, err := s.TemplateRepo.GetTemplate(templateID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTemplateNotFound
+		}
+		return nil, fmt.Errorf("database error (get template): %w", err)
+	}
+
+	if !template.IsGlobal() && *template.UserID != userID {
+		return nil, ErrTemplateNotFound
+	}
+	return template, nil
+}
+
+// getWritableTemplate は編集可能なテンプレートを返す。プリセットの編集は管理者のみ
+func (s *TemplateService) getWritableTemplate(templateID, userID int) (*model.PromptTemplate, error) {
+	template, err := s.getReadableTemplate(templateID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if template.IsGlobal() {
+		if err := s.requireAdmin(userID); err != nil {
+			return nil, err
+		}
+	}
+	return template, nil
+}
+
+func (s *TemplateService) requireAdmin(userID int) error {
+	user, err := s.UserRepo.GetUserByID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if !user.IsAdmin {
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTemplateServiceTest(t *testing.T) (*MockTemplateRepository, *MockUserRepository, ITemplateService) {
+	mockTemplateRepo := new(MockTemplateRepository)
+	mockUserRepo := new(MockUserRepository)
+
+	templateService := NewTemplateService(mockTemplateRepo, mockUserRepo)
+
+	return mockTemplateRepo, mockUserRepo, templateService
+}
+
+func TestTemplateService_RenderTemplate(t *testing.T) {
+	mockTemplateRepo, _, templateService := setupTemplateServiceTest(t)
+
+	level := "A2"
+	wordCount := 200
+	template := &model.PromptTemplate{
+		ID:               1,
+		UserID:           &testUser.ID,
+		Name:             "Topic",
+		Body:             "Explain {{topic}} for {{ audience }}.",
+		DefaultLevel:     &level,
+		DefaultWordCount: &wordCount,
+	}
+
+	t.Run("success: should substitute placeholders and return defaults", func(t *testing.T) {
+		mockTemplateRepo.On("GetTemplate", template.ID).Return(template, nil).Once()
+
+		rendered, err := templateService.RenderTemplate(template.ID, testUser.ID, map[string]string{"topic": "volcanoes", "audience": "kids"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Explain volcanoes for kids.", rendered.Prompt)
+		assert.Equal(t, "A2", rendered.Level)
+		assert.Equal(t, 200, rendered.WordCount)
+		mockTemplateRepo.AssertExpectations(t)
+	})
+
+	t.Run("fail: should report missing variables", func(t *testing.T) {
+		mockTemplateRepo.On("GetTemplate", template.ID).Return(template, nil).Once()
+
+		_, err := templateService.RenderTemplate(template.ID, testUser.ID, map[string]string{"topic": "volcanoes"})
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrMissingTemplateVariables)
+		assert.Contains(t, err.Error(), "audience")
+	})
+
+	t.Run("fail: should hide templates owned by another user", func(t *testing.T) {
+		mockTemplateRepo.On("GetTemplate", template.ID).Return(template, nil).Once()
+
+		_, err := templateService.RenderTemplate(template.ID, testUser.ID+1, map[string]string{})
+
+		assert.ErrorIs(t, err, ErrTemplateNotFound)
+	})
+
+	t.Run("fail: should return not found for unknown templates", func(t *testing.T) {
+		mockTemplateRepo.On("GetTemplate", 99).Return(nil, sql.ErrNoRows).Once()
+
+		_, err := templateService.RenderTemplate(99, testUser.ID, nil)
+
+		assert.ErrorIs(t, err, ErrTemplateNotFound)
+	})
+}
+
+func TestTemplateService_CreateTemplate(t *testing.T) {
+	mockTemplateRepo, mockUserRepo, templateService := setupTemplateServiceTest(t)
+
+	t.Run("success: should create a personal template", func(t *testing.T) {
+		mockTemplateRepo.On("CreateTemplate", mock.MatchedBy(func(tmpl *model.PromptTemplate) bool {
+			return tmpl.UserID != nil && *tmpl.UserID == testUser.ID
+		})).Return(nil).Once()
+
+		template, err := templateService.CreateTemplate(testUser.ID, TemplateInput{Name: "Mine", Body: "{{topic}}"})
+
+		require.NoError(t, err)
+		assert.False(t, template.IsGlobal())
+		mockTemplateRepo.AssertExpectations(t)
+	})
+
+	t.Run("fail: non-admin users cannot publish presets", func(t *testing.T) {
+		mockUserRepo.On("GetUserByID", testUser.ID).Return(&model.User{ID: testUser.ID}, nil).Once()
+
+		_, err := templateService.CreateTemplate(testUser.ID, TemplateInput{Name: "Preset", Body: "{{topic}}", Global: true})
+
+		assert.ErrorIs(t, err, ErrForbidden)
+		mockTemplateRepo.AssertNumberOfCalls(t, "CreateTemplate", 1)
+	})
+
+	t.Run("success: admins can publish presets", func(t *testing.T) {
+		mockUserRepo.On("GetUserByID", testUser.ID).Return(&model.User{ID: testUser.ID, IsAdmin: true}, nil).Once()
+		mockTemplateRepo.On("CreateTemplate", mock.MatchedBy(func(tmpl *model.PromptTemplate) bool {
+			return tmpl.UserID == nil
+		})).Return(nil).Once()
+
+		template, err := templateService.CreateTemplate(testUser.ID, TemplateInput{Name: "Preset", Body: "{{topic}}", Global: true})
+
+		require.NoError(t, err)
+		assert.True(t, template.IsGlobal())
+		mockUserRepo.AssertExpectations(t)
+	})
+}
+
+func TestTemplatePlaceholders(t *testing.T) {
+	assert.Equal(t, []string{"topic", "level"}, TemplatePlaceholders("{{topic}} at {{ level }} about {{topic}}"))
+	assert.Empty(t, TemplatePlaceholders("no placeholders"))
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
 	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
 	"github.com/shuheikomatsuki/readoku/backend/internal/timeutil"
 )
@@ -22,9 +23,20 @@ type GenerationStatus struct {
 	Limit        int `json:"limit"`
 }
 
+// ReadingHistoryPage はキーセットページネーションで取得した読了履歴
+type ReadingHistoryPage struct {
+	Records    []*model.ReadingHistoryItem
+	NextCursor *string
+	PrevCursor *string
+}
+
+// readingHistoryCursorKind は読了履歴のカーソルの種類
+const readingHistoryCursorKind = "history"
+
 type IUserService interface {
 	GetUserStats(userID int) (*UserStats, error)
 	GetGenerationStatus(userID int) (*GenerationStatus, error)
+	GetReadingHistory(userID int, cursor string, limit int) (*ReadingHistoryPage, error)
 }
 
 type UserService struct {
@@ -124,3 +136,34 @@ func (s *UserService) GetGenerationStatus(userID int) (*GenerationStatus, error)
 
 	return status, nil
 }
+
+// GetReadingHistory は読了履歴を新しい順に返す。cursor が空の場合は最新のページを返す
+func (s *UserService) GetReadingHistory(userID int, cursor string, limit int) (*ReadingHistoryPage, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+
+	var keyset *repository.Keyset
+	if cursor != "" {
+		var err error
+		keyset, err = decodeCursor(readingHistoryCursorKind, cursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 続きのページの有無を判定するため 1 件多く取得する
+	records, err := s.ReadingRecordRepo.GetReadingHistory(userID, keyset, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reading history: %w", err)
+	}
+
+	records, next, prev := paginateKeyset(records, limit, keyset, readingHistoryCursorKind, func(record *model.ReadingHistoryItem) (time.Time, int) {
+		return record.ReadAt, record.ID
+	})
+	if records == nil {
+		records = []*model.ReadingHistoryItem{}
+	}
+
+	return &ReadingHistoryPage{Records: records, NextCursor: next, PrevCursor: prev}, nil
+}
@@ -2,7 +2,10 @@ package service
 
 import (
 	"testing"
+	"time"
 
+	"github.com/shuheikomatsuki/readoku/backend/internal/model"
+	"github.com/shuheikomatsuki/readoku/backend/internal/repository"
 	"github.com/shuheikomatsuki/readoku/backend/internal/timeutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -92,3 +95,41 @@ func TestUserService_GetGenerationStatus(t *testing.T) {
 		mockUserRepo.AssertExpectations(t)
 	})
 }
+
+func TestUserService_GetReadingHistory(t *testing.T) {
+	mockReadingRepo := new(MockReadingRecordRepository)
+	mockUserRepo := new(MockUserRepository)
+	userService := NewUserService(mockReadingRepo, mockUserRepo, testDailyLimit)
+
+	readAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	records := []*model.ReadingHistoryItem{
+		{ID: 3, ReadAt: readAt},
+		{ID: 2, ReadAt: readAt.Add(-time.Hour)},
+		{ID: 1, ReadAt: readAt.Add(-2 * time.Hour)},
+	}
+
+	t.Run("success: first page should return a next cursor only", func(t *testing.T) {
+		mockReadingRepo.On("GetReadingHistory", testUser.ID, (*repository.Keyset)(nil), 3).Return(records, nil).Once()
+
+		page, err := userService.GetReadingHistory(testUser.ID, "", 2)
+
+		require.NoError(t, err)
+		require.Len(t, page.Records, 2)
+		assert.Equal(t, 3, page.Records[0].ID)
+		require.NotNil(t, page.NextCursor)
+		assert.Nil(t, page.PrevCursor)
+
+		keyset, err := decodeCursor(readingHistoryCursorKind, *page.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, 2, keyset.ID)
+		assert.True(t, keyset.Time.Equal(readAt.Add(-time.Hour)))
+
+		mockReadingRepo.AssertExpectations(t)
+	})
+
+	t.Run("fail: should reject a malformed cursor", func(t *testing.T) {
+		_, err := userService.GetReadingHistory(testUser.ID, "not-a-cursor", 2)
+
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+}
@@ -0,0 +1,55 @@
+// Package simhash は本文がほぼ同じストーリーを見つけるための 64 ビットの SimHash を計算する
+package simhash
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"unicode"
+)
+
+// shingleSize は特徴量にする連続した単語の数。単語単位だと同じ話題の別の文章も近くなるため、語順を含めて比べる
+const shingleSize = 3
+
+// Fingerprint は本文の SimHash を返す。大文字・小文字と句読点・空白の違いは無視する。単語がない場合は 0 を返す
+func Fingerprint(text string) uint64 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\''
+	})
+	if len(words) == 0 {
+		return 0
+	}
+
+	size := min(shingleSize, len(words))
+	var weights [64]int
+	for i := 0; i+size <= len(words); i++ {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(strings.Join(words[i:i+size], " ")))
+		sum := h.Sum64()
+		for bit := range weights {
+			if sum&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	var fingerprint uint64
+	for bit, weight := range weights {
+		if weight > 0 {
+			fingerprint |= 1 << bit
+		}
+	}
+	return fingerprint
+}
+
+// Distance は 2 つの SimHash で異なるビットの数 (0〜64) を返す。小さいほど本文が似ている
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Similarity は 2 つの SimHash の一致度を 0〜1 で返す
+func Similarity(a, b uint64) float64 {
+	return 1 - float64(Distance(a, b))/64
+}
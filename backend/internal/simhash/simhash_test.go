@@ -0,0 +1,52 @@
+package simhash
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const harbor = `Tom lives in a small town near the sea. Every morning he walks to the harbor with his dog, Max. ` +
+	`The fishermen are already busy with their boats and nets. Tom likes to watch the seagulls flying over the water. ` +
+	`Sometimes an old fisherman named Ben gives him a small fish for Max. After that, Tom goes to school. ` +
+	`His favorite subject is science because he wants to study the ocean when he grows up. ` +
+	`In the evening, he sits on the beach and watches the sunset with his family. ` +
+	`He thinks his town is the most beautiful place in the world.`
+
+const city = `Anna lives in a big city far from the sea. Every morning she takes the train to school with her friend, Lily. ` +
+	`The streets are already busy with cars and buses. Anna likes to look at the tall buildings from the window. ` +
+	`Sometimes a kind old woman named Rose gives her a flower. After school, Anna goes to the library. ` +
+	`Her favorite subject is history because she wants to visit old cities when she grows up. ` +
+	`In the evening, she reads books with her family. She thinks her city is the most exciting place in the world.`
+
+func TestFingerprint(t *testing.T) {
+	t.Run("ignores case, punctuation and whitespace", func(t *testing.T) {
+		reformatted := strings.ToUpper(strings.ReplaceAll(harbor, ". ", ".\n\n"))
+		reformatted = strings.ReplaceAll(reformatted, ",", "")
+
+		assert.Equal(t, Fingerprint(harbor), Fingerprint(reformatted))
+	})
+
+	t.Run("lightly edited text stays close", func(t *testing.T) {
+		edited := strings.Replace(harbor, "small town", "little town", 1)
+		edited = strings.Replace(edited, "beautiful", "wonderful", 1)
+
+		assert.LessOrEqual(t, Distance(Fingerprint(harbor), Fingerprint(edited)), 10)
+	})
+
+	t.Run("different text on a similar pattern is far", func(t *testing.T) {
+		assert.Greater(t, Distance(Fingerprint(harbor), Fingerprint(city)), 10)
+	})
+
+	t.Run("text without words is zero", func(t *testing.T) {
+		assert.Zero(t, Fingerprint(" ... "))
+		assert.NotZero(t, Fingerprint("Hi"))
+	})
+}
+
+func TestSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, Similarity(42, 42))
+	assert.Equal(t, 0.0, Similarity(0, ^uint64(0)))
+	assert.Equal(t, 0.75, Similarity(0, 0xffff))
+}
@@ -0,0 +1,250 @@
+package webpage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"golang.org/x/net/html/charset"
+
+	"github.com/shuheikomatsuki/readoku/backend/internal/markdown"
+)
+
+var ErrNoContent = errors.New("no readable content")
+
+// Article はページから抜き出した記事。Content は Markdown、CanonicalURL は正規の URL (なければ取得した URL)
+type Article struct {
+	Title        string
+	CanonicalURL string
+	Content      string
+}
+
+var (
+	// unlikelyCandidates は本文ではない可能性が高い class / id (Readability の判定を簡略化したもの)
+	unlikelyCandidates = regexp.MustCompile(`(?i)comment|sidebar|footer|footnote|menu|nav|share|social|related|advert|promo|sponsor|cookie|banner|subscribe|newsletter|popup|breadcrumb|masthead`)
+	// maybeCandidates は unlikelyCandidates に当てはまっても本文の可能性がある class / id
+	maybeCandidates = regexp.MustCompile(`(?i)article|content|main|body|post|story|entry|text`)
+)
+
+// boilerplateElements は本文の候補から外す要素
+var boilerplateElements = map[atom.Atom]bool{
+	atom.Nav:    true,
+	atom.Header: true,
+	atom.Footer: true,
+	atom.Aside:  true,
+	atom.Form:   true,
+}
+
+// minParagraphLength はスコアの対象とする段落の最小文字数
+const minParagraphLength = 25
+
+// Extract は取得したページから記事のタイトル・正規の URL・本文を抜き出す。
+// 本文は段落の多い要素を選ぶ Readability 風の方法で決め、ナビゲーションや広告などは除く
+func Extract(page *Page) (*Article, error) {
+	r, err := charset.NewReader(bytes.NewReader(page.Body), page.ContentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode page: %w", err)
+	}
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse page: %w", err)
+	}
+
+	article := &Article{
+		Title:        findTitle(doc),
+		CanonicalURL: findCanonicalURL(doc, page.URL),
+	}
+
+	removeBoilerplate(doc)
+	content := findContentNode(doc)
+	if content == nil {
+		return nil, ErrNoContent
+	}
+	article.Content = strings.TrimSpace(markdown.FromNode(content))
+	if article.Content == "" {
+		return nil, ErrNoContent
+	}
+	return article, nil
+}
+
+// findTitle は og:title、<title>、最初の <h1> の順にタイトルを探す
+func findTitle(doc *html.Node) string {
+	if title := metaContent(doc, "og:title"); title != "" {
+		return title
+	}
+	if n := findFirst(doc, atom.Title); n != nil {
+		if title := collapse(textOf(n)); title != "" {
+			return title
+		}
+	}
+	if n := findFirst(doc, atom.H1); n != nil {
+		return collapse(textOf(n))
+	}
+	return ""
+}
+
+// findCanonicalURL は <link rel="canonical">、og:url の順に正規の URL を探す。
+// http / https 以外や解決できない URL は使わず、取得した URL を返す
+func findCanonicalURL(doc *html.Node, pageURL *url.URL) string {
+	candidates := []string{}
+	walk(doc, func(n *html.Node) {
+		if n.DataAtom == atom.Link && strings.EqualFold(attr(n, "rel"), "canonical") {
+			candidates = append(candidates, attr(n, "href"))
+		}
+	})
+	candidates = append(candidates, metaContent(doc, "og:url"))
+
+	for _, candidate := range candidates {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "" {
+			continue
+		}
+		u, err := pageURL.Parse(candidate)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			continue
+		}
+		u.Fragment = ""
+		return u.String()
+	}
+	return pageURL.String()
+}
+
+// removeBoilerplate はナビゲーション・ヘッダー・広告などの本文ではない要素を取り除く
+func removeBoilerplate(doc *html.Node) {
+	var remove []*html.Node
+	walk(doc, func(n *html.Node) {
+		if n.DataAtom == atom.Body || n.DataAtom == atom.Html || n.DataAtom == atom.Article || n.DataAtom == atom.Main {
+			return
+		}
+		if boilerplateElements[n.DataAtom] {
+			remove = append(remove, n)
+			return
+		}
+		match := attr(n, "class") + " " + attr(n, "id")
+		if unlikelyCandidates.MatchString(match) && !maybeCandidates.MatchString(match) {
+			remove = append(remove, n)
+		}
+	})
+	for _, n := range remove {
+		if n.Parent != nil {
+			n.Parent.RemoveChild(n)
+		}
+	}
+}
+
+// findContentNode は段落の長さと読点の数から要素に点数を付け、リンクの割合で割り引いて最も高い要素を返す
+func findContentNode(doc *html.Node) *html.Node {
+	scores := map[*html.Node]float64{}
+	walk(doc, func(n *html.Node) {
+		if n.DataAtom != atom.P && n.DataAtom != atom.Pre && n.DataAtom != atom.Blockquote {
+			return
+		}
+		text := collapse(textOf(n))
+		if len(text) < minParagraphLength {
+			return
+		}
+		score := 1 + float64(strings.Count(text, ",")) + min(float64(len(text))/100, 3)
+		if parent := n.Parent; parent != nil {
+			scores[parent] += score
+			if grandparent := parent.Parent; grandparent != nil {
+				scores[grandparent] += score / 2
+			}
+		}
+	})
+
+	// map の順序は実行ごとに変わるため文書の順にたどる。同点なら先に現れた要素 (祖先を含む) を選ぶ
+	var best *html.Node
+	bestScore := 0.0
+	walk(doc, func(n *html.Node) {
+		score, ok := scores[n]
+		if !ok {
+			return
+		}
+		score *= 1 - linkDensity(n)
+		if score > bestScore {
+			best, bestScore = n, score
+		}
+	})
+	if best != nil {
+		return best
+	}
+	// 段落として認識できる要素がない場合は <body> 全体を使う
+	return findFirst(doc, atom.Body)
+}
+
+// linkDensity は要素のテキストのうちリンクが占める割合
+func linkDensity(n *html.Node) float64 {
+	total := len(collapse(textOf(n)))
+	if total == 0 {
+		return 0
+	}
+	linkLength := 0
+	walk(n, func(child *html.Node) {
+		if child.DataAtom == atom.A {
+			linkLength += len(collapse(textOf(child)))
+		}
+	})
+	return float64(linkLength) / float64(total)
+}
+
+func metaContent(doc *html.Node, property string) string {
+	var content string
+	walk(doc, func(n *html.Node) {
+		if content == "" && n.DataAtom == atom.Meta && (attr(n, "property") == property || attr(n, "name") == property) {
+			content = collapse(attr(n, "content"))
+		}
+	})
+	return content
+}
+
+func findFirst(doc *html.Node, a atom.Atom) *html.Node {
+	var found *html.Node
+	walk(doc, func(n *html.Node) {
+		if found == nil && n.DataAtom == a {
+			found = n
+		}
+	})
+	return found
+}
+
+// walk は要素を深さ優先の文書順にたどる
+func walk(n *html.Node, fn func(*html.Node)) {
+	if n.Type == html.ElementNode {
+		fn(n)
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		walk(child, fn)
+	}
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func textOf(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && (child.DataAtom == atom.Script || child.DataAtom == atom.Style) {
+			continue
+		}
+		b.WriteString(textOf(child))
+	}
+	return b.String()
+}
+
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
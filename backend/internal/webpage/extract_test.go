@@ -0,0 +1,87 @@
+package webpage
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtract(t *testing.T) {
+	pageURL, _ := url.Parse("https://news.example.com/2024/05/story?utm_source=feed")
+
+	t.Run("success: should extract the main text and the canonical url", func(t *testing.T) {
+		page := &Page{
+			URL:         pageURL,
+			ContentType: "text/html; charset=utf-8",
+			Body: []byte(`<html><head>
+				<title>Rivers Are Rising | Example News</title>
+				<meta property="og:title" content="Rivers Are Rising">
+				<link rel="canonical" href="/2024/05/story">
+				<script>track()</script>
+			</head><body>
+				<nav><a href="/">Home</a> <a href="/world">World</a></nav>
+				<div class="sidebar"><p>Popular: a very long list of unrelated headlines, links, and more.</p></div>
+				<article>
+					<h1>Rivers Are Rising</h1>
+					<p>Heavy rain fell across the valley for three days, and the river rose quickly.</p>
+					<p>Farmers moved their animals to higher ground, while schools closed early.</p>
+					<img src="flood.jpg" alt="Flood">
+				</article>
+				<div class="share-buttons"><a href="#">Share on social media</a></div>
+				<footer><p>Copyright Example News, all rights reserved, since forever.</p></footer>
+			</body></html>`),
+		}
+
+		article, err := Extract(page)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Rivers Are Rising", article.Title)
+		assert.Equal(t, "https://news.example.com/2024/05/story", article.CanonicalURL)
+		assert.Equal(t, "# Rivers Are Rising\n\n"+
+			"Heavy rain fell across the valley for three days, and the river rose quickly.\n\n"+
+			"Farmers moved their animals to higher ground, while schools closed early.", article.Content)
+	})
+
+	t.Run("success: should decode the declared charset and fall back to the page url", func(t *testing.T) {
+		page := &Page{
+			URL:         pageURL,
+			ContentType: "text/html; charset=iso-8859-1",
+			Body:        []byte("<html><head><title>Caf\xe9</title></head><body><div><p>The caf\xe9 opened early on Monday morning, as usual.</p></div></body></html>"),
+		}
+
+		article, err := Extract(page)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Café", article.Title)
+		assert.Equal(t, pageURL.String(), article.CanonicalURL)
+		assert.Equal(t, "The café opened early on Monday morning, as usual.", article.Content)
+	})
+
+	t.Run("success: should pick the first of equally scored blocks", func(t *testing.T) {
+		page := &Page{
+			URL:         pageURL,
+			ContentType: "text/html",
+			Body: []byte(`<html><body>
+				<section><div><p>The first block has a sentence that is long enough to count.</p></div></section>
+				<section><div><p>The other block has a sentence that is long enough to count.</p></div></section>
+			</body></html>`),
+		}
+
+		// 同点の候補を map の順で選ぶと結果が実行ごとに変わるため、何度か繰り返して確かめる
+		for i := 0; i < 20; i++ {
+			article, err := Extract(page)
+			require.NoError(t, err)
+			assert.Equal(t, "The first block has a sentence that is long enough to count.", article.Content)
+		}
+	})
+
+	t.Run("fail: should return ErrNoContent for pages without text", func(t *testing.T) {
+		page := &Page{URL: pageURL, ContentType: "text/html", Body: []byte("<html><body><img src='a.png'></body></html>")}
+
+		_, err := Extract(page)
+
+		assert.ErrorIs(t, err, ErrNoContent)
+	})
+}
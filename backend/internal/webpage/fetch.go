@@ -0,0 +1,195 @@
+// Package webpage は取り込み用に外部の Web ページを取得し、本文を抜き出す
+package webpage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrInvalidURL         = errors.New("invalid url")
+	ErrForbiddenAddress   = errors.New("address is not allowed")
+	ErrTooLarge           = errors.New("page is too large")
+	ErrUnsupportedContent = errors.New("page is not html")
+	ErrFetchFailed        = errors.New("failed to fetch page")
+)
+
+const (
+	// DefaultTimeout はリダイレクトや本文の読み込みを含めた 1 回の取得にかける時間の上限
+	DefaultTimeout = 10 * time.Second
+	// DefaultMaxBytes は読み込むレスポンスボディの上限
+	DefaultMaxBytes = 2 * 1024 * 1024
+	maxRedirects    = 5
+	userAgent       = "readoku-importer/1.0"
+)
+
+// blockedPrefixes は netip の分類に含まれない、外部から到達できないアドレス帯
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"),  // CGNAT
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF プロトコル割り当て
+	netip.MustParsePrefix("198.18.0.0/15"),  // ベンチマーク用
+	netip.MustParsePrefix("240.0.0.0/4"),    // 予約済み (ブロードキャストを含む)
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64 (IPv4 のアドレスを埋め込めるため内部にも届く)
+	netip.MustParsePrefix("64:ff9b:1::/48"), // ローカル用の NAT64
+	netip.MustParsePrefix("2001:db8::/32"),  // ドキュメント用
+}
+
+// allowedPorts は接続を許可するポート。Web ページ以外のサービスには接続しない
+var allowedPorts = map[uint16]bool{80: true, 443: true}
+
+// Page は取得したページ。URL はリダイレクト後の最終的な URL
+type Page struct {
+	URL         *url.URL
+	ContentType string
+	Body        []byte
+}
+
+// Fetcher は外部の Web ページを取得する。接続先はアドレスを解決した後に確認し、
+// ループバック・プライベート・リンクローカルなどのアドレスには接続しない (SSRF 対策)
+type Fetcher struct {
+	Timeout  time.Duration
+	MaxBytes int64
+	// AllowPrivateNetworks はプライベートアドレスへの接続を許可する (ローカルのテストサーバー用)
+	AllowPrivateNetworks bool
+}
+
+func NewFetcher() *Fetcher {
+	return &Fetcher{
+		Timeout:  DefaultTimeout,
+		MaxBytes: DefaultMaxBytes,
+	}
+}
+
+// Fetch は rawURL の HTML を取得する。http / https 以外の URL、2xx 以外の応答、HTML 以外の本文はエラーにする
+func (f *Fetcher) Fetch(rawURL string) (*Page, error) {
+	target, err := validateURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), f.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := f.client().Do(req)
+	if err != nil {
+		if errors.Is(err, ErrForbiddenAddress) || errors.Is(err, ErrInvalidURL) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrFetchFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%w: status %d", ErrFetchFailed, resp.StatusCode)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedContent, mediaType)
+	}
+	if resp.ContentLength > f.MaxBytes {
+		return nil, ErrTooLarge
+	}
+
+	// Content-Length は省略や偽装ができるため、実際に読んだ量で上限を確認する
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.MaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFetchFailed, err)
+	}
+	if int64(len(body)) > f.MaxBytes {
+		return nil, ErrTooLarge
+	}
+
+	return &Page{URL: resp.Request.URL, ContentType: contentType, Body: body}, nil
+}
+
+func (f *Fetcher) client() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: f.Timeout,
+		// 名前解決後の実際の接続先で確認するため、DNS リバインディングでも内部のアドレスには届かない
+		Control: func(network, address string, _ syscall.RawConn) error {
+			if f.AllowPrivateNetworks {
+				return nil
+			}
+			return checkAddress(address)
+		},
+	}
+	transport := &http.Transport{
+		Proxy:                  nil, // 環境変数のプロキシ経由にすると接続先の確認が効かなくなる
+		DialContext:            dialer.DialContext,
+		TLSHandshakeTimeout:    f.Timeout,
+		ResponseHeaderTimeout:  f.Timeout,
+		MaxResponseHeaderBytes: 64 * 1024,
+		DisableKeepAlives:      true,
+	}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   f.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("%w: too many redirects", ErrFetchFailed)
+			}
+			_, err := validateURL(req.URL.String())
+			return err
+		},
+	}
+}
+
+func validateURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("%w: scheme must be http or https", ErrInvalidURL)
+	}
+	if u.Hostname() == "" || u.User != nil {
+		return nil, fmt.Errorf("%w: host is required and credentials are not allowed", ErrInvalidURL)
+	}
+	return u, nil
+}
+
+// checkAddress は接続先の "IP:port" が外部の公開アドレスの 80 / 443 番ポートかどうかを確認する
+func checkAddress(address string) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, address)
+	}
+	if !isPublicAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, addrPort.Addr())
+	}
+	if !allowedPorts[addrPort.Port()] {
+		return fmt.Errorf("%w: port %d", ErrForbiddenAddress, addrPort.Port())
+	}
+	return nil
+}
+
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
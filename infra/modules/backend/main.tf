@@ -148,7 +148,7 @@ resource "aws_apigatewayv2_api" "api" {
   cors_configuration {
     allow_credentials = true
     allow_origins     = local.cors_allow_origins
-    allow_methods     = ["GET", "POST", "PATCH", "DELETE", "OPTIONS"]
+    allow_methods     = ["GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"]
     allow_headers     = local.cors_allow_headers
   }
 }
@@ -188,3 +188,30 @@ resource "aws_lambda_permission" "apigw" {
   principal     = "apigateway.amazonaws.com"
   source_arn    = "${aws_apigatewayv2_api.api[0].execution_arn}/*/*"
 }
+
+# 定期ジョブ (「今日の文章」の自動生成など) を API と同じ Lambda で実行する
+resource "aws_cloudwatch_event_rule" "scheduled_jobs" {
+  count = var.enable_backend ? 1 : 0
+
+  name                = "${var.name_prefix}-scheduled-jobs"
+  description         = "Invoke the API Lambda to run scheduled jobs"
+  schedule_expression = var.scheduled_jobs_schedule_expression
+  tags                = var.tags
+}
+
+resource "aws_cloudwatch_event_target" "scheduled_jobs" {
+  count = var.enable_backend ? 1 : 0
+
+  rule = aws_cloudwatch_event_rule.scheduled_jobs[0].name
+  arn  = aws_lambda_function.api[0].arn
+}
+
+resource "aws_lambda_permission" "scheduled_jobs" {
+  count = var.enable_backend ? 1 : 0
+
+  statement_id  = "AllowEventBridgeInvoke"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.api[0].function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.scheduled_jobs[0].arn
+}
@@ -82,3 +82,9 @@ variable "log_retention_in_days" {
   type        = number
   default     = 14
 }
+
+variable "scheduled_jobs_schedule_expression" {
+  description = "EventBridge schedule expression for running scheduled jobs (delivery hours are checked per user)."
+  type        = string
+  default     = "cron(0 * * * ? *)"
+}